@@ -67,6 +67,7 @@ func messageHandler(logger logging.Logger) *cli.Command {
 				RoutingKeys: []string{
 					integrationevent.RoutingKeyPrefix + "#",
 					"user.#",
+					"order.#",
 				},
 			}
 			amqpEventProducer := amqpConnection.Producer(
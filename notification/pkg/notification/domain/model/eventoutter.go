@@ -57,3 +57,59 @@ func (e OrderStatusChanged) Type() string {
 func (u UserDeleted) Type() string {
 	return "user_deleted"
 }
+
+// ScheduledOrderFailed is sent by order service when a recurring order was not placed or paid
+type ScheduledOrderFailed struct {
+	TemplateID uuid.UUID  `json:"template_id"`
+	CustomerID uuid.UUID  `json:"customer_id"`
+	OrderID    *uuid.UUID `json:"order_id,omitempty"`
+	Reason     string     `json:"reason"`
+}
+
+func (e ScheduledOrderFailed) Type() string {
+	return "ScheduledOrderFailed"
+}
+
+// OrderReturnRequested, OrderReturnApproved, OrderReturnRejected and OrderReturnCompleted are sent by order service on return steps
+type OrderReturnRequested struct {
+	ReturnID   uuid.UUID `json:"return_id"`
+	OrderID    uuid.UUID `json:"order_id"`
+	CustomerID uuid.UUID `json:"customer_id"`
+	Reason     string    `json:"reason"`
+}
+
+func (e OrderReturnRequested) Type() string {
+	return "OrderReturnRequested"
+}
+
+type OrderReturnApproved struct {
+	ReturnID   uuid.UUID `json:"return_id"`
+	OrderID    uuid.UUID `json:"order_id"`
+	CustomerID uuid.UUID `json:"customer_id"`
+}
+
+func (e OrderReturnApproved) Type() string {
+	return "OrderReturnApproved"
+}
+
+type OrderReturnRejected struct {
+	ReturnID   uuid.UUID `json:"return_id"`
+	OrderID    uuid.UUID `json:"order_id"`
+	CustomerID uuid.UUID `json:"customer_id"`
+	Reason     string    `json:"reason"`
+}
+
+func (e OrderReturnRejected) Type() string {
+	return "OrderReturnRejected"
+}
+
+type OrderReturnCompleted struct {
+	ReturnID     uuid.UUID `json:"return_id"`
+	OrderID      uuid.UUID `json:"order_id"`
+	CustomerID   uuid.UUID `json:"customer_id"`
+	RefundAmount float64   `json:"refund_amount"`
+}
+
+func (e OrderReturnCompleted) Type() string {
+	return "OrderReturnCompleted"
+}
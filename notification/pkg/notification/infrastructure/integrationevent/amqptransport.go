@@ -63,6 +63,50 @@ func (t *amqpTransport) handle(ctx context.Context, delivery amqp.Delivery) erro
 		fmt.Printf("NOTIFICATION SERVICE: Received Order %s status change to %d\n", e.OrderID, e.To)
 		return nil
 
+	case model.ScheduledOrderFailed{}.Type():
+		var e model.ScheduledOrderFailed
+		err := json.Unmarshal(delivery.Body, &e)
+		if err != nil {
+			return err
+		}
+		return t.workflowService.RunScheduledOrderFailedWorkflow(ctx, delivery.CorrelationID, e)
+
+	case model.OrderReturnRequested{}.Type():
+		var e model.OrderReturnRequested
+		err := json.Unmarshal(delivery.Body, &e)
+		if err != nil {
+			return err
+		}
+		return t.workflowService.RunOrderReturnWorkflow(ctx, delivery.CorrelationID, e.CustomerID,
+			fmt.Sprintf("Return of order %s is requested and waits for approval", e.OrderID))
+
+	case model.OrderReturnApproved{}.Type():
+		var e model.OrderReturnApproved
+		err := json.Unmarshal(delivery.Body, &e)
+		if err != nil {
+			return err
+		}
+		return t.workflowService.RunOrderReturnWorkflow(ctx, delivery.CorrelationID, e.CustomerID,
+			fmt.Sprintf("Return of order %s is approved", e.OrderID))
+
+	case model.OrderReturnRejected{}.Type():
+		var e model.OrderReturnRejected
+		err := json.Unmarshal(delivery.Body, &e)
+		if err != nil {
+			return err
+		}
+		return t.workflowService.RunOrderReturnWorkflow(ctx, delivery.CorrelationID, e.CustomerID,
+			fmt.Sprintf("Return of order %s is rejected: %s", e.OrderID, e.Reason))
+
+	case model.OrderReturnCompleted{}.Type():
+		var e model.OrderReturnCompleted
+		err := json.Unmarshal(delivery.Body, &e)
+		if err != nil {
+			return err
+		}
+		return t.workflowService.RunOrderReturnWorkflow(ctx, delivery.CorrelationID, e.CustomerID,
+			fmt.Sprintf("Return of order %s is completed, %.2f is refunded to your wallet", e.OrderID, e.RefundAmount))
+
 	default:
 		return errUnhandledDelivery
 	}
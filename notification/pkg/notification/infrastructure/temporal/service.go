@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/google/uuid"
 	"go.temporal.io/sdk/client"
 
 	"notification/pkg/notification/domain/model"
@@ -15,6 +16,8 @@ const TaskQueue = "notification_task_queue"
 type WorkflowService interface {
 	RunCreateUserWorkflow(ctx context.Context, id string, event model.UserCreated) error
 	RunUpdateUserWorkflow(ctx context.Context, id string, event model.UserUpdated) error
+	RunScheduledOrderFailedWorkflow(ctx context.Context, id string, event model.ScheduledOrderFailed) error
+	RunOrderReturnWorkflow(ctx context.Context, id string, customerID uuid.UUID, message string) error
 }
 
 func NewWorkflowService(temporalClient client.Client) WorkflowService {
@@ -52,3 +55,27 @@ func (s *workflowService) RunUpdateUserWorkflow(ctx context.Context, id string,
 	)
 	return err
 }
+
+func (s *workflowService) RunScheduledOrderFailedWorkflow(ctx context.Context, id string, event model.ScheduledOrderFailed) error {
+	_, err := s.temporalClient.ExecuteWorkflow(
+		ctx,
+		client.StartWorkflowOptions{
+			ID:        id,
+			TaskQueue: TaskQueue,
+		},
+		workflows.ScheduledOrderFailedWorkflow, event,
+	)
+	return err
+}
+
+func (s *workflowService) RunOrderReturnWorkflow(ctx context.Context, id string, customerID uuid.UUID, message string) error {
+	_, err := s.temporalClient.ExecuteWorkflow(
+		ctx,
+		client.StartWorkflowOptions{
+			ID:        id,
+			TaskQueue: TaskQueue,
+		},
+		workflows.OrderReturnWorkflow, customerID, message,
+	)
+	return err
+}
@@ -24,5 +24,7 @@ func NewWorker(
 	w.RegisterActivity(activity.NewUserActivities(userService))
 	w.RegisterWorkflow(workflows.CreateUserWorkflow)
 	w.RegisterWorkflow(workflows.UserUpdatedWorkflow)
+	w.RegisterWorkflow(workflows.ScheduledOrderFailedWorkflow)
+	w.RegisterWorkflow(workflows.OrderReturnWorkflow)
 	return w
 }
@@ -0,0 +1,38 @@
+package workflows
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.temporal.io/sdk/workflow"
+
+	appdata "notification/pkg/notification/app/data"
+	"notification/pkg/notification/domain/model"
+)
+
+// OrderReturnWorkflow notifies the customer about a step of the order return
+func OrderReturnWorkflow(ctx workflow.Context, customerID uuid.UUID, message string) error {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+	})
+
+	var user appdata.User
+	err := workflow.ExecuteActivity(ctx, userActivities.FindUser, customerID).Get(ctx, &user)
+	if err != nil {
+		if errors.Is(err, model.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if user.Email == nil {
+		return nil
+	}
+
+	payload := appdata.NotificationPayload{
+		Email:   *user.Email,
+		Message: message,
+	}
+	return workflow.ExecuteActivity(ctx, notificationActivities.CreateNotification, payload).Get(ctx, nil)
+}
@@ -0,0 +1,36 @@
+package workflows
+
+import (
+	"errors"
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+
+	appdata "notification/pkg/notification/app/data"
+	"notification/pkg/notification/domain/model"
+)
+
+func ScheduledOrderFailedWorkflow(ctx workflow.Context, event model.ScheduledOrderFailed) error {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+	})
+
+	var user appdata.User
+	err := workflow.ExecuteActivity(ctx, userActivities.FindUser, event.CustomerID).Get(ctx, &user)
+	if err != nil {
+		if errors.Is(err, model.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if user.Email == nil {
+		return nil
+	}
+
+	payload := appdata.NotificationPayload{
+		Email:   *user.Email,
+		Message: "Scheduled order failed: " + event.Reason,
+	}
+	return workflow.ExecuteActivity(ctx, notificationActivities.CreateNotification, payload).Get(ctx, nil)
+}
@@ -50,23 +50,23 @@ func main() {
 	orderID := resp.OrderID
 	fmt.Printf("Order Created with ID: %s\n", orderID)
 
-	fmt.Println("--- Polling Order Status ---")
+	fmt.Println("--- Polling Saga Status ---")
 	for i := 0; i < 30; i++ {
-		r, err := client.FindOrder(ctx, &pb.FindOrderRequest{OrderID: orderID})
+		r, err := client.GetOrderSagaStatus(ctx, &pb.GetOrderSagaStatusRequest{OrderID: orderID})
 		if err != nil {
-			log.Printf("Waiting for order... (%v)", err)
+			log.Printf("Waiting for saga... (%v)", err)
 		} else {
-			fmt.Printf("Attempt %d: Status = %s\n", i+1, r.Status)
+			fmt.Printf("Attempt %d: Step = %s, Item = %d, Reserved = %d\n", i+1, r.Step, r.CurrentItem, len(r.ReservedItems))
 
-			if r.Status == pb.OrderStatus_Paid {
+			switch r.Step {
+			case pb.OrderSagaStep_SagaCompleted:
 				fmt.Println("🎉 SUCCESS: Order is PAID! Saga finished successfully.")
 				return
-			}
-			if r.Status == pb.OrderStatus_Cancelled {
-				log.Fatalf("❌ FAILURE: Order was CANCELLED. Saga compensation triggered.")
+			case pb.OrderSagaStep_SagaCancelled:
+				log.Fatalf("❌ FAILURE: Order was CANCELLED. Saga compensation triggered: %s", r.GetLastError())
 			}
 		}
 		time.Sleep(1 * time.Second)
 	}
-	log.Fatalf("❌ TIMEOUT: Order status stuck.")
+	log.Fatalf("❌ TIMEOUT: Saga status stuck.")
 }
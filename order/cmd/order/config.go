@@ -18,6 +18,10 @@ type Service struct {
 
 	GRPCAddress string `envconfig:"grpc_address" default:":8081"`
 	HTTPAddress string `envconfig:"http_address" default:":8082"`
+
+	IdempotencyKeyRetention time.Duration `envconfig:"idempotency_key_retention" default:"24h"`
+	// CartTTL is inactivity time after which the cart expires
+	CartTTL time.Duration `envconfig:"cart_ttl" default:"72h"`
 }
 
 type Database struct {
@@ -39,4 +43,22 @@ type AMQP struct {
 
 type Temporal struct {
 	Host string `envconfig:"host" required:"true"`
+	// OrderPaymentTTL is time after which OrderExpiryWorkflow cancels an unpaid order, zero disables expiry
+	OrderPaymentTTL time.Duration `envconfig:"order_payment_ttl" default:"30m"`
+	// OrderPaymentHoldTTL is how long the payment of an order asking for a hold waits for capture, zero disables holds
+	OrderPaymentHoldTTL time.Duration `envconfig:"order_payment_hold_ttl"`
+}
+
+type Product struct {
+	GRPCAddress string `envconfig:"grpc_address" default:"product:8081"`
+}
+
+type User struct {
+	GRPCAddress string `envconfig:"grpc_address" default:"user:8081"`
+}
+
+// Delivery holds fees of delivery methods, Pickup is free
+type Delivery struct {
+	CourierFee float64 `envconfig:"courier_fee" default:"300"`
+	PostFee    float64 `envconfig:"post_fee" default:"200"`
 }
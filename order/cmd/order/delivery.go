@@ -0,0 +1,13 @@
+package main
+
+import (
+	appdata "order/pkg/order/app/data"
+	appservice "order/pkg/order/app/service"
+)
+
+func newDeliveryFees(config Delivery) appservice.DeliveryFees {
+	return appservice.DeliveryFees{
+		appdata.Courier: config.CourierFee,
+		appdata.Post:    config.PostFee,
+	}
+}
@@ -64,7 +64,12 @@ func messageHandler(logger logging.Logger) *cli.Command {
 			bindConfig := &amqp.BindConfig{
 				QueueName:    integrationevent.QueueName,
 				ExchangeName: integrationevent.ExchangeName,
-				RoutingKeys:  []string{integrationevent.RoutingKeyPrefix + "#"},
+				RoutingKeys: []string{
+					integrationevent.RoutingKeyPrefix + "#",
+					"user.#",
+					"product.#",
+					"payment.#",
+				},
 			}
 			amqpEventProducer := amqpConnection.Producer(
 				&amqp.ExchangeConfig{
@@ -19,9 +19,11 @@ import (
 
 	internalapi "order/api/server/orderinternalapi"
 	appservice "order/pkg/order/app/service"
+	"order/pkg/order/infrastructure/addressbook"
 	"order/pkg/order/infrastructure/integrationevent"
 	inframysql "order/pkg/order/infrastructure/mysql"
 	"order/pkg/order/infrastructure/mysql/query"
+	"order/pkg/order/infrastructure/productcatalog"
 	"order/pkg/order/infrastructure/transport"
 	"order/pkg/order/infrastructure/transport/middlewares"
 )
@@ -30,6 +32,9 @@ type serviceConfig struct {
 	Service  Service  `envconfig:"service"`
 	Database Database `envconfig:"database" required:"true"`
 	Temporal Temporal `envconfig:"temporal" required:"true"`
+	Product  Product  `envconfig:"product"`
+	User     User     `envconfig:"user"`
+	Delivery Delivery `envconfig:"delivery"`
 }
 
 func service(logger logging.Logger) *cli.Command {
@@ -63,15 +68,34 @@ func service(logger logging.Logger) *cli.Command {
 				return nil
 			}))
 
+			productServiceConnection, err := newProductServiceConnection(cnf.Product)
+			if err != nil {
+				return err
+			}
+			closer.AddCloser(productServiceConnection)
+			productCatalog := productcatalog.NewProductCatalog(productServiceConnection)
+
+			userServiceConnection, err := newUserServiceConnection(cnf.User)
+			if err != nil {
+				return err
+			}
+			closer.AddCloser(userServiceConnection)
+			addressBook := addressbook.NewAddressBook(userServiceConnection)
+
 			libUoW := mysql.NewUnitOfWork(databaseConnectionPool, inframysql.NewRepositoryProvider)
 			libLUow := mysql.NewLockableUnitOfWork(libUoW, mysql.NewLocker(databaseConnectionPool))
 			uow := inframysql.NewUnitOfWork(libUoW)
 			luow := inframysql.NewLockableUnitOfWork(libLUow)
 			eventDispatcher := outbox.NewEventDispatcher(appID, integrationevent.TransportName, integrationevent.NewEventSerializer(), libUoW)
 
+			orderService := appservice.NewOrderService(uow, luow, eventDispatcher, temporalClient, productCatalog, addressBook, newDeliveryFees(cnf.Delivery), cnf.Service.IdempotencyKeyRetention, cnf.Temporal.OrderPaymentTTL, cnf.Temporal.OrderPaymentHoldTTL)
 			userPublicAPIServer := transport.NewOrderInternalAPI(
 				query.NewOrderQueryService(databaseConnector.TransactionalClient()),
-				appservice.NewOrderService(uow, luow, eventDispatcher, temporalClient),
+				orderService,
+				appservice.NewCartService(uow, luow, orderService, productCatalog, cnf.Service.CartTTL),
+				appservice.NewPromotionService(uow, luow, eventDispatcher),
+				appservice.NewOrderTemplateService(uow, luow, eventDispatcher, temporalClient, orderService),
+				appservice.NewOrderReturnService(uow, luow, eventDispatcher, temporalClient),
 			)
 
 			errGroup := errgroup.Group{}
@@ -82,6 +106,7 @@ func service(logger logging.Logger) *cli.Command {
 				}
 				grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(
 					middlewares.NewGRPCLoggingMiddleware(logger),
+					middlewares.NewGRPCActorMiddleware(),
 				))
 				internalapi.RegisterOrderInternalAPIServer(grpcServer, userPublicAPIServer)
 				graceCallback(c.Context, logger, cnf.Service.GracePeriod, func(_ context.Context) error {
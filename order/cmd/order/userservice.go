@@ -0,0 +1,12 @@
+package main
+
+import (
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func newUserServiceConnection(config User) (*grpc.ClientConn, error) {
+	conn, err := grpc.NewClient(config.GRPCAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	return conn, errors.WithStack(err)
+}
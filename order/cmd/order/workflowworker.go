@@ -13,8 +13,11 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	appservice "order/pkg/order/app/service"
+	"order/pkg/order/infrastructure/addressbook"
 	"order/pkg/order/infrastructure/integrationevent"
 	inframysql "order/pkg/order/infrastructure/mysql"
+	"order/pkg/order/infrastructure/mysql/query"
+	"order/pkg/order/infrastructure/productcatalog"
 	"order/pkg/order/infrastructure/temporal"
 	"order/pkg/order/infrastructure/temporal/worker"
 )
@@ -23,6 +26,9 @@ type workflowWorkerConfig struct {
 	Service  Service  `envconfig:"service"`
 	Database Database `envconfig:"database" required:"true"`
 	Temporal Temporal `envconfig:"temporal" required:"true"`
+	Product  Product  `envconfig:"product"`
+	User     User     `envconfig:"user"`
+	Delivery Delivery `envconfig:"delivery"`
 }
 
 func workflowWorker(logger logging.Logger) *cli.Command {
@@ -56,15 +62,43 @@ func workflowWorker(logger logging.Logger) *cli.Command {
 				return nil
 			}))
 
+			productServiceConnection, err := newProductServiceConnection(cnf.Product)
+			if err != nil {
+				return err
+			}
+			closer.AddCloser(productServiceConnection)
+			productCatalog := productcatalog.NewProductCatalog(productServiceConnection)
+
+			userServiceConnection, err := newUserServiceConnection(cnf.User)
+			if err != nil {
+				return err
+			}
+			closer.AddCloser(userServiceConnection)
+			addressBook := addressbook.NewAddressBook(userServiceConnection)
+
 			libUoW := mysql.NewUnitOfWork(databaseConnectionPool, inframysql.NewRepositoryProvider)
 			libLUow := mysql.NewLockableUnitOfWork(libUoW, mysql.NewLocker(databaseConnectionPool))
 			uow := inframysql.NewUnitOfWork(libUoW)
 			luow := inframysql.NewLockableUnitOfWork(libLUow)
 			eventDispatcher := outbox.NewEventDispatcher(appID, integrationevent.TransportName, integrationevent.NewEventSerializer(), libUoW)
 
+			err = temporal.NewWorkflowService(temporalClient).StartRemoveExpiredCartsWorkflow(c.Context)
+			if err != nil {
+				return err
+			}
+
+			orderService := appservice.NewOrderService(uow, luow, eventDispatcher, temporalClient, productCatalog, addressBook, newDeliveryFees(cnf.Delivery), cnf.Service.IdempotencyKeyRetention, cnf.Temporal.OrderPaymentTTL, cnf.Temporal.OrderPaymentHoldTTL)
 			errGroup := errgroup.Group{}
 			errGroup.Go(func() error {
-				w := worker.NewWorker(temporalClient, appservice.NewOrderService(uow, luow, eventDispatcher, temporalClient))
+				w := worker.NewWorker(
+					temporalClient,
+					orderService,
+					query.NewOrderQueryService(databaseConnector.TransactionalClient()),
+					appservice.NewCartService(uow, luow, orderService, productCatalog, cnf.Service.CartTTL),
+					appservice.NewPromotionService(uow, luow, eventDispatcher),
+					appservice.NewOrderTemplateService(uow, luow, eventDispatcher, temporalClient, orderService),
+					appservice.NewOrderReturnService(uow, luow, eventDispatcher, temporalClient),
+				)
 				return w.Run(worker.InterruptChannel())
 			})
 
@@ -0,0 +1,72 @@
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrRecordNotFound = errors.New("idempotency record not found")
+	ErrKeyConflict    = errors.New("idempotency key is already used with another payload")
+)
+
+// Record keeps the response of the first request made with the key
+type Record struct {
+	Scope       string
+	Key         string
+	Fingerprint string
+	Response    string
+	CreatedAt   time.Time
+}
+
+type Repository interface {
+	Find(scope, key string) (*Record, error)
+	Store(record Record) error
+}
+
+// Fingerprint returns a stable hash of the request payload
+func Fingerprint(payload any) (string, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Replay returns the response stored for the key, ok is false when the request has not been handled within retention yet
+func Replay(repo Repository, scope, key, fingerprint string, retention time.Duration) (response string, ok bool, err error) {
+	record, err := repo.Find(scope, key)
+	if err != nil {
+		if errors.Is(err, ErrRecordNotFound) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	if time.Since(record.CreatedAt) > retention {
+		return "", false, nil
+	}
+	if record.Fingerprint != fingerprint {
+		return "", false, errors.WithStack(ErrKeyConflict)
+	}
+	return record.Response, true, nil
+}
+
+// Remember stores the response for the key replacing an expired record if any
+func Remember(repo Repository, scope, key, fingerprint, response string) error {
+	return repo.Store(Record{
+		Scope:       scope,
+		Key:         key,
+		Fingerprint: fingerprint,
+		Response:    response,
+		CreatedAt:   time.Now(),
+	})
+}
+
+func Lock(scope, key string) string {
+	return "idempotency_" + scope + "_" + key
+}
@@ -0,0 +1,20 @@
+package data
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Cart struct {
+	ID         uuid.UUID
+	CustomerID uuid.UUID
+	Items      []CartItem
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type CartItem struct {
+	ProductID uuid.UUID
+	Count     int
+}
@@ -13,6 +13,13 @@ const (
 	Pending
 	Paid
 	Cancelled
+	Processing
+	Shipped
+	Delivered
+	ReturnRequested
+	Returned
+	Refunded
+	PartiallyReturned
 )
 
 type Order struct {
@@ -23,11 +30,124 @@ type Order struct {
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
 	DeletedAt  *time.Time
+	// PromotionID is set by applied promo code
+	PromotionID *uuid.UUID
+	Discount    float64
+	// AllowPartial lets the order be paid for items in stock only, SplitBackorder moves the rest into a backorder
+	AllowPartial     bool
+	SplitBackorder   bool
+	UnfulfilledItems []UnfulfilledItem
+	// HoldPayment makes the order payment held on the wallet until it is captured
+	HoldPayment   bool
+	ParentOrderID *uuid.UUID
+	BackorderID   *uuid.UUID
+	// AddressID selects the saved customer address taken as ShippingAddress when the order is stored
+	AddressID       *uuid.UUID
+	ShippingAddress *ShippingAddress
+	DeliveryMethod  DeliveryMethod
+	DeliveryFee     float64
+	// PaymentID is set once CreateOrderSaga creates the payment of the order
+	PaymentID *uuid.UUID
+}
+
+type DeliveryMethod int
+
+const (
+	Pickup DeliveryMethod = iota
+	Courier
+	Post
+)
+
+type ShippingAddress struct {
+	Recipient  string
+	Phone      string
+	Country    string
+	City       string
+	Street     string
+	PostalCode string
+}
+
+// Address is the customer address saved in the user service
+type Address struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Recipient  string
+	Phone      string
+	Country    string
+	City       string
+	Street     string
+	PostalCode string
 }
 
 type OrderItem struct {
 	OrderID    uuid.UUID
 	ProductID  uuid.UUID
 	Count      int
+	Price      float64
 	TotalPrice float64
 }
+
+type UnfulfilledItem struct {
+	ProductID uuid.UUID
+	Count     int
+	Price     float64
+	Reason    string
+}
+
+type Product struct {
+	ID        uuid.UUID
+	Name      string
+	Price     float64
+	DeletedAt *time.Time
+}
+
+type ListOrdersSpec struct {
+	CustomerID *uuid.UUID
+	// ProductID selects orders containing the product
+	ProductID      *uuid.UUID
+	Statuses       []OrderStatus
+	CreatedFrom    *time.Time
+	CreatedTo      *time.Time
+	UpdatedFrom    *time.Time
+	UpdatedTo      *time.Time
+	IncludeDeleted bool
+	Limit          int
+	// Cursor is an opaque token returned as OrderPage.NextCursor, empty for the first page
+	Cursor string
+}
+
+type OrderPage struct {
+	Orders []Order
+	// NextCursor is empty when there are no more orders
+	NextCursor string
+}
+
+type OrderSagaStep int
+
+const (
+	OrderSagaStarting OrderSagaStep = iota
+	OrderSagaReserving
+	OrderSagaCharging
+	OrderSagaCompensating
+	OrderSagaCompleted
+	OrderSagaCancelled
+	OrderSagaAwaitingCapture
+)
+
+type OrderSagaStatus struct {
+	OrderID       uuid.UUID
+	Step          OrderSagaStep
+	CurrentItem   int
+	ReservedItems []OrderSagaItem
+	// UnfulfilledItems are items which were not reserved for the order allowing partial fulfilment
+	UnfulfilledItems []OrderSagaItem
+	BackorderID      *uuid.UUID
+	// HoldID is set once the wallet is authorized for the order paid through a hold
+	HoldID    *uuid.UUID
+	LastError *string
+}
+
+type OrderSagaItem struct {
+	ProductID uuid.UUID
+	Quantity  int
+}
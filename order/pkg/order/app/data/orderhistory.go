@@ -0,0 +1,19 @@
+package data
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OrderHistoryEntry struct {
+	OrderID   uuid.UUID
+	EventType string
+	Actor     string
+	// FromStatus and ToStatus are set only for events changing order status
+	FromStatus *OrderStatus
+	ToStatus   *OrderStatus
+	// Details is a JSON encoded domain event
+	Details   string
+	CreatedAt time.Time
+}
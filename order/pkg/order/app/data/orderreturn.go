@@ -0,0 +1,8 @@
+package data
+
+import "github.com/google/uuid"
+
+type OrderReturnItem struct {
+	ProductID uuid.UUID
+	Count     int
+}
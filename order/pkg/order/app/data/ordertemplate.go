@@ -0,0 +1,28 @@
+package data
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OrderTemplate struct {
+	ID         uuid.UUID
+	CustomerID uuid.UUID
+	Items      []OrderTemplateItem
+	Schedule   *OrderSchedule
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type OrderTemplateItem struct {
+	ProductID uuid.UUID
+	Count     int
+}
+
+// OrderSchedule has either CronExpression or Interval set
+type OrderSchedule struct {
+	CronExpression string
+	Interval       time.Duration
+	Paused         bool
+}
@@ -0,0 +1,25 @@
+package data
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PromotionKind int
+
+const (
+	PercentageDiscount PromotionKind = iota
+	FixedAmountDiscount
+)
+
+type Promotion struct {
+	ID               uuid.UUID
+	Code             string
+	Kind             PromotionKind
+	Value            float64
+	MinOrderTotal    float64
+	PerCustomerLimit int
+	ValidFrom        *time.Time
+	ValidTo          *time.Time
+}
@@ -2,12 +2,20 @@ package query
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
 
 	"order/pkg/order/app/data"
 )
 
+var ErrInvalidCursor = errors.New("invalid cursor")
+
 type OrderQueryService interface {
-	FindUser(ctx context.Context, orderID uuid.UUID) (*data.Order, error)
+	FindOrder(ctx context.Context, orderID uuid.UUID) (*data.Order, error)
+	ListOrders(ctx context.Context, spec data.ListOrdersSpec) (*data.OrderPage, error)
+	// GetOrderHistory returns order events from the oldest one
+	GetOrderHistory(ctx context.Context, orderID uuid.UUID) ([]data.OrderHistoryEntry, error)
+	// ListOrderSchedules returns scheduled order templates of the customer
+	ListOrderSchedules(ctx context.Context, customerID uuid.UUID) ([]data.OrderTemplate, error)
 }
@@ -0,0 +1,28 @@
+package service
+
+import "context"
+
+const (
+	ActorSystem    = "system"
+	ActorOrderSaga = "order-saga"
+	// ActorOrderSchedule is used for orders placed by recurring order schedules
+	ActorOrderSchedule = "order-schedule"
+	ActorOrderReturn   = "order-return"
+	ActorOrderExpiry   = "order-expiry"
+	// ActorIntegrationEvent is used for changes made in reaction to events of other services
+	ActorIntegrationEvent = "integration-event"
+)
+
+type actorKey struct{}
+
+// WithActor sets who makes changes within ctx, the actor is stored in order history
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func actorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return ActorSystem
+}
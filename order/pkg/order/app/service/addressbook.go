@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	appdata "order/pkg/order/app/data"
+)
+
+var ErrAddressNotFound = errors.New("address not found")
+
+// AddressBook provides addresses saved by customers in the user service
+type AddressBook interface {
+	FindAddress(ctx context.Context, addressID uuid.UUID) (appdata.Address, error)
+}
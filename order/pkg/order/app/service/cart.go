@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	appdata "order/pkg/order/app/data"
+	"order/pkg/order/domain/model"
+	"order/pkg/order/domain/service"
+)
+
+var ErrEmptyCart = errors.New("cart is empty")
+
+type CartService interface {
+	FindCart(ctx context.Context, customerID uuid.UUID) (appdata.Cart, error)
+	AddCartItem(ctx context.Context, customerID, productID uuid.UUID, count int) error
+	RemoveCartItem(ctx context.Context, customerID, productID uuid.UUID) error
+	SetCartItemQuantity(ctx context.Context, customerID, productID uuid.UUID, count int) error
+	// Checkout converts the cart into an order, starts CreateOrderSaga and removes the cart
+	Checkout(ctx context.Context, customerID uuid.UUID) (uuid.UUID, error)
+	// RemoveExpiredCarts removes carts not changed for longer than cart TTL
+	RemoveExpiredCarts(ctx context.Context) error
+}
+
+func NewCartService(
+	uow UnitOfWork,
+	luow LockableUnitOfWork,
+	orderService OrderService,
+	productCatalog ProductCatalog,
+	cartTTL time.Duration,
+) CartService {
+	return &cartService{
+		uow:            uow,
+		luow:           luow,
+		orderService:   orderService,
+		productCatalog: productCatalog,
+		cartTTL:        cartTTL,
+	}
+}
+
+type cartService struct {
+	uow            UnitOfWork
+	luow           LockableUnitOfWork
+	orderService   OrderService
+	productCatalog ProductCatalog
+	cartTTL        time.Duration
+}
+
+func (s *cartService) FindCart(ctx context.Context, customerID uuid.UUID) (appdata.Cart, error) {
+	var cart appdata.Cart
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		domainCart, err := s.domainService(ctx, provider).FindCart(customerID)
+		if err != nil {
+			return err
+		}
+		cart = appdata.Cart{
+			ID:         domainCart.ID,
+			CustomerID: domainCart.CustomerID,
+			Items:      make([]appdata.CartItem, len(domainCart.Items)),
+			CreatedAt:  domainCart.CreatedAt,
+			UpdatedAt:  domainCart.UpdatedAt,
+		}
+		for i, item := range domainCart.Items {
+			cart.Items[i] = appdata.CartItem{
+				ProductID: item.ProductID,
+				Count:     item.Count,
+			}
+		}
+		return nil
+	})
+	return cart, err
+}
+
+func (s *cartService) AddCartItem(ctx context.Context, customerID, productID uuid.UUID, count int) error {
+	err := s.checkProduct(ctx, productID)
+	if err != nil {
+		return err
+	}
+	return s.luow.Execute(ctx, []string{cartLockByCustomer(customerID)}, func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider).AddItem(customerID, productID, count)
+	})
+}
+
+func (s *cartService) RemoveCartItem(ctx context.Context, customerID, productID uuid.UUID) error {
+	return s.luow.Execute(ctx, []string{cartLockByCustomer(customerID)}, func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider).RemoveItem(customerID, productID)
+	})
+}
+
+func (s *cartService) SetCartItemQuantity(ctx context.Context, customerID, productID uuid.UUID, count int) error {
+	if count > 0 {
+		err := s.checkProduct(ctx, productID)
+		if err != nil {
+			return err
+		}
+	}
+	return s.luow.Execute(ctx, []string{cartLockByCustomer(customerID)}, func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider).SetItemQuantity(customerID, productID, count)
+	})
+}
+
+func (s *cartService) Checkout(ctx context.Context, customerID uuid.UUID) (uuid.UUID, error) {
+	var orderID uuid.UUID
+	err := s.luow.Execute(ctx, []string{cartLockByCustomer(customerID)}, func(provider RepositoryProvider) error {
+		cart, err := s.domainService(ctx, provider).FindCart(customerID)
+		if err != nil {
+			return err
+		}
+		if len(cart.Items) == 0 {
+			return errors.WithStack(ErrEmptyCart)
+		}
+
+		order := appdata.Order{
+			CustomerID: customerID,
+			Status:     appdata.Open,
+			Items:      make([]appdata.OrderItem, len(cart.Items)),
+		}
+		for i, item := range cart.Items {
+			order.Items[i] = appdata.OrderItem{
+				ProductID: item.ProductID,
+				Count:     item.Count,
+			}
+		}
+		// Order is committed separately, so retried checkout of the same cart version replays it instead of creating another one
+		orderID, err = s.orderService.StoreOrder(ctx, order, checkoutIdempotencyKey(cart))
+		if err != nil {
+			return err
+		}
+		return provider.CartRepository(ctx).Remove(cart.ID)
+	})
+	return orderID, err
+}
+
+func (s *cartService) RemoveExpiredCarts(ctx context.Context) error {
+	if s.cartTTL <= 0 {
+		return nil
+	}
+	return s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		return provider.CartRepository(ctx).RemoveInactive(time.Now().Add(-s.cartTTL))
+	})
+}
+
+func (s *cartService) checkProduct(ctx context.Context, productID uuid.UUID) error {
+	product, err := s.productCatalog.FindProduct(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if product.DeletedAt != nil {
+		return errors.WithStack(ErrProductRemoved)
+	}
+	return nil
+}
+
+func (s *cartService) domainService(ctx context.Context, provider RepositoryProvider) service.CartService {
+	return service.NewCartService(provider.CartRepository(ctx), s.cartTTL)
+}
+
+const baseCartLock = "cart_"
+
+func cartLockByCustomer(customerID uuid.UUID) string {
+	return baseCartLock + "customer_" + customerID.String()
+}
+
+func checkoutIdempotencyKey(cart *model.Cart) string {
+	return "checkout_" + cart.ID.String() + "_" + strconv.Itoa(cart.Version)
+}
@@ -2,17 +2,106 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+	"github.com/pkg/errors"
 
 	commonevent "order/pkg/common/event"
+	appdata "order/pkg/order/app/data"
+	"order/pkg/order/domain/model"
 )
 
+type OrderHistoryRepository interface {
+	Append(entry appdata.OrderHistoryEntry) error
+}
+
+func newDomainEventDispatcher(
+	ctx context.Context,
+	eventDispatcher outbox.EventDispatcher[outbox.Event],
+	provider RepositoryProvider,
+) commonevent.Dispatcher {
+	return &domainEventDispatcher{
+		ctx:               ctx,
+		eventDispatcher:   eventDispatcher,
+		historyRepository: provider.OrderHistoryRepository(ctx),
+	}
+}
+
 type domainEventDispatcher struct {
+	ctx               context.Context
+	eventDispatcher   outbox.EventDispatcher[outbox.Event]
+	historyRepository OrderHistoryRepository
+}
+
+func (d *domainEventDispatcher) Dispatch(event commonevent.Event) error {
+	entry, err := d.historyEntry(event)
+	if err != nil {
+		return err
+	}
+	// History is written within the same transaction as the change itself
+	if err = d.historyRepository.Append(entry); err != nil {
+		return err
+	}
+	return d.eventDispatcher.Dispatch(d.ctx, event)
+}
+
+func (d *domainEventDispatcher) historyEntry(event commonevent.Event) (appdata.OrderHistoryEntry, error) {
+	details, err := json.Marshal(event)
+	if err != nil {
+		return appdata.OrderHistoryEntry{}, errors.WithStack(err)
+	}
+	entry := appdata.OrderHistoryEntry{
+		EventType: event.Type(),
+		Actor:     actorFromContext(d.ctx),
+		Details:   string(details),
+		CreatedAt: time.Now(),
+	}
+
+	switch e := event.(type) {
+	case model.OrderCreated:
+		entry.OrderID = e.OrderID
+		entry.ToStatus = statusPtr(model.Open)
+	case model.OrderItemsChanged:
+		entry.OrderID = e.OrderID
+	case model.OrderRemoved:
+		entry.OrderID = e.OrderID
+	case model.OrderStatusChanged:
+		entry.OrderID = e.OrderID
+		entry.FromStatus = statusPtr(e.From)
+		entry.ToStatus = statusPtr(e.To)
+	case model.OrderPromoCodeApplied:
+		entry.OrderID = e.OrderID
+	case model.OrderItemsUnfulfilled:
+		entry.OrderID = e.OrderID
+	case model.OrderBackorderCreated:
+		entry.OrderID = e.OrderID
+	case model.OrderReturnRequested:
+		entry.OrderID = e.OrderID
+	case model.OrderReturnApproved:
+		entry.OrderID = e.OrderID
+	case model.OrderReturnRejected:
+		entry.OrderID = e.OrderID
+	case model.OrderReturnCompleted:
+		entry.OrderID = e.OrderID
+	default:
+		return appdata.OrderHistoryEntry{}, errors.Errorf("unknown event %q", event.Type())
+	}
+	return entry, nil
+}
+
+func statusPtr(status model.OrderStatus) *appdata.OrderStatus {
+	s := appdata.OrderStatus(status)
+	return &s
+}
+
+// integrationEventDispatcher dispatches events which do not belong to order history
+type integrationEventDispatcher struct {
 	ctx             context.Context
 	eventDispatcher outbox.EventDispatcher[outbox.Event]
 }
 
-func (d *domainEventDispatcher) Dispatch(event commonevent.Event) error {
+func (d *integrationEventDispatcher) Dispatch(event commonevent.Event) error {
 	return d.eventDispatcher.Dispatch(d.ctx, event)
 }
@@ -3,21 +3,60 @@ package service
 import (
 	"context"
 	"order/pkg/order/infrastructure/temporal/workflows"
+	"slices"
+	"time"
 
 	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.temporal.io/api/serviceerror"
 	"go.temporal.io/sdk/client" // Не забываем импорт
 
 	commonevent "order/pkg/common/event"
+	"order/pkg/common/idempotency"
 	appdata "order/pkg/order/app/data"
 	"order/pkg/order/domain/model"
 	"order/pkg/order/domain/service"
 )
 
+var (
+	ErrOrderSagaNotFound = errors.New("order saga not found")
+	ErrNotBackorder      = errors.New("order is not a backorder")
+	// ErrOrderSagaAlreadyStarted means the backorder is submitted already
+	ErrOrderSagaAlreadyStarted = errors.New("order saga already started")
+	ErrInvalidCaptureAmount    = errors.New("invalid capture amount")
+	// ErrOrderNotAwaitingCapture means the saga of the order does not hold a payment to capture
+	ErrOrderNotAwaitingCapture = errors.New("order is not awaiting payment capture")
+	// ErrPaymentHoldDisabled means the order asks to hold its payment while no hold TTL is configured
+	ErrPaymentHoldDisabled = errors.New("payment hold is disabled")
+)
+
 type OrderService interface {
-	StoreOrder(ctx context.Context, order appdata.Order) (uuid.UUID, error)
+	// StoreOrder returns ID of the order created by the first call with the same non-empty idempotencyKey
+	StoreOrder(ctx context.Context, order appdata.Order, idempotencyKey string) (uuid.UUID, error)
+	// PrepareOrder stores Open order like StoreOrder, but returns CreateOrderSaga params instead of starting it
+	PrepareOrder(ctx context.Context, order appdata.Order, idempotencyKey string) (workflows.OrderSagaParams, error)
 	SetOrderStatus(ctx context.Context, orderID uuid.UUID, status int) error
+	// UpdateOrderStatus moves order through fulfilment statuses, statuses up to Paid are driven by CreateOrderSaga and CancelOrder only,
+	// Returned and PartiallyReturned are set by OrderReturnWorkflow only and Refunded is set by the payment refund only
+	UpdateOrderStatus(ctx context.Context, orderID uuid.UUID, status appdata.OrderStatus) error
 	FindOrder(ctx context.Context, orderID uuid.UUID) (appdata.Order, error)
+	CancelOrder(ctx context.Context, orderID uuid.UUID) error
+	// ExpireOrder cancels the order which is still not paid, it is called by OrderExpiryWorkflow once orderPaymentTTL passes
+	ExpireOrder(ctx context.Context, orderID uuid.UUID) error
+	// CaptureOrderPayment captures the payment held by CreateOrderSaga, zero amount captures the whole hold
+	CaptureOrderPayment(ctx context.Context, orderID uuid.UUID, amount float64) error
+	// RemoveOrderProduct removes the product from the order, only Open order can be changed
+	RemoveOrderProduct(ctx context.Context, orderID, productID uuid.UUID) error
+	// ApplyPaymentStatus moves the order according to status of its payment, other statuses are ignored
+	ApplyPaymentStatus(ctx context.Context, orderID uuid.UUID, status model.PaymentStatus) error
+	GetOrderSagaStatus(ctx context.Context, orderID uuid.UUID) (appdata.OrderSagaStatus, error)
+	// MarkUnfulfilledItems takes items which were not reserved off the order and returns its new total
+	MarkUnfulfilledItems(ctx context.Context, orderID uuid.UUID, items []appdata.UnfulfilledItem) (float64, error)
+	CreateBackorder(ctx context.Context, orderID uuid.UUID) (uuid.UUID, error)
+	SetOrderPayment(ctx context.Context, orderID, paymentID uuid.UUID) error
+	// SubmitBackorder starts CreateOrderSaga for the Open backorder once its items are back in stock
+	SubmitBackorder(ctx context.Context, orderID uuid.UUID) error
 }
 
 func NewOrderService(
@@ -25,26 +64,192 @@ func NewOrderService(
 	luow LockableUnitOfWork,
 	eventDispatcher outbox.EventDispatcher[outbox.Event],
 	temporalClient client.Client, // Добавляем в параметры
+	productCatalog ProductCatalog,
+	addressBook AddressBook,
+	deliveryFees DeliveryFees,
+	idempotencyKeyRetention time.Duration,
+	orderPaymentTTL time.Duration,
+	orderPaymentHoldTTL time.Duration,
 ) OrderService {
 	return &orderService{
-		uow:             uow,
-		luow:            luow,
-		eventDispatcher: eventDispatcher,
-		temporalClient:  temporalClient, // Сохраняем клиента
+		uow:                     uow,
+		luow:                    luow,
+		eventDispatcher:         eventDispatcher,
+		temporalClient:          temporalClient, // Сохраняем клиента
+		productCatalog:          productCatalog,
+		addressBook:             addressBook,
+		deliveryFees:            deliveryFees,
+		idempotencyKeyRetention: idempotencyKeyRetention,
+		orderPaymentTTL:         orderPaymentTTL,
+		orderPaymentHoldTTL:     orderPaymentHoldTTL,
 	}
 }
 
 type orderService struct {
-	uow             UnitOfWork
-	luow            LockableUnitOfWork
-	eventDispatcher outbox.EventDispatcher[outbox.Event]
-	temporalClient  client.Client // Добавляем поле в структуру
+	uow                     UnitOfWork
+	luow                    LockableUnitOfWork
+	eventDispatcher         outbox.EventDispatcher[outbox.Event]
+	temporalClient          client.Client // Добавляем поле в структуру
+	productCatalog          ProductCatalog
+	addressBook             AddressBook
+	deliveryFees            DeliveryFees
+	idempotencyKeyRetention time.Duration
+	orderPaymentTTL         time.Duration
+	orderPaymentHoldTTL     time.Duration
+}
+
+// DeliveryFees maps delivery method to its fee, methods missing here are free
+type DeliveryFees map[appdata.DeliveryMethod]float64
+
+const storeOrderIdempotencyScope = "store_order"
+
+func (s *orderService) StoreOrder(ctx context.Context, order appdata.Order, idempotencyKey string) (uuid.UUID, error) {
+	if order.HoldPayment && s.orderPaymentHoldTTL <= 0 {
+		return uuid.Nil, ErrPaymentHoldDisabled
+	}
+	orderID, replayed, err := s.storeOrder(ctx, &order, idempotencyKey)
+	if err != nil {
+		return orderID, err
+	}
+	if replayed {
+		// The saga may have failed to start after the order was committed, so replay starts it again for the Open order
+		order, err = s.FindOrder(ctx, orderID)
+		if err != nil {
+			return orderID, err
+		}
+	}
+
+	if order.Status == appdata.Open {
+		err = s.startOrderExpiry(ctx, orderID)
+		if err != nil {
+			return orderID, err
+		}
+		sagaErr := s.startOrderSaga(ctx, orderID, order)
+		var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+		if sagaErr != nil && !errors.As(sagaErr, &alreadyStarted) {
+			return orderID, sagaErr
+		}
+	}
+
+	return orderID, nil
+}
+
+func (s *orderService) startOrderSaga(ctx context.Context, orderID uuid.UUID, order appdata.Order) error {
+	_, err := s.temporalClient.ExecuteWorkflow(ctx, client.StartWorkflowOptions{
+		ID:        workflows.OrderSagaWorkflowID(orderID.String()),
+		TaskQueue: "order_task_queue",
+	}, workflows.CreateOrderSagaName, s.orderSagaParams(orderID, order))
+	return err
+}
+
+// startOrderExpiry starts OrderExpiryWorkflow apart from the saga, so the order expires even if its saga never starts
+func (s *orderService) startOrderExpiry(ctx context.Context, orderID uuid.UUID) error {
+	if s.orderPaymentTTL <= 0 {
+		return nil
+	}
+	_, err := s.temporalClient.ExecuteWorkflow(ctx, client.StartWorkflowOptions{
+		ID:        workflows.OrderExpiryWorkflowID(orderID.String()),
+		TaskQueue: "order_task_queue",
+	}, workflows.OrderExpiryWorkflow, orderID.String(), s.orderPaymentTTL)
+	var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+	if errors.As(err, &alreadyStarted) {
+		return nil
+	}
+	return errors.WithStack(err)
+}
+
+func (s *orderService) PrepareOrder(ctx context.Context, order appdata.Order, idempotencyKey string) (workflows.OrderSagaParams, error) {
+	if order.Status != appdata.Open {
+		return workflows.OrderSagaParams{}, errors.WithStack(service.ErrInvalidOrderStatus)
+	}
+	orderID, replayed, err := s.storeOrder(ctx, &order, idempotencyKey)
+	if err != nil {
+		return workflows.OrderSagaParams{}, err
+	}
+	if replayed {
+		// Replayed order is not priced again, so its stored items are used
+		order, err = s.FindOrder(ctx, orderID)
+		if err != nil {
+			return workflows.OrderSagaParams{}, err
+		}
+	}
+	if err = s.startOrderExpiry(ctx, orderID); err != nil {
+		return workflows.OrderSagaParams{}, err
+	}
+	return s.orderSagaParams(orderID, order), nil
 }
 
-func (s *orderService) StoreOrder(ctx context.Context, order appdata.Order) (uuid.UUID, error) {
-	orderID := order.ID
-	err := s.luow.Execute(ctx, []string{orderLock(orderID)}, func(provider RepositoryProvider) error {
-		domainService := s.domainService(ctx, provider.OrderRepository(ctx))
+func (s *orderService) orderSagaParams(orderID uuid.UUID, order appdata.Order) workflows.OrderSagaParams {
+	items := make([]workflows.OrderItemParam, len(order.Items))
+	var total float64
+	for i, it := range order.Items {
+		items[i] = workflows.OrderItemParam{ProductID: it.ProductID.String(), Quantity: it.Count}
+		total += it.TotalPrice
+	}
+	params := workflows.OrderSagaParams{
+		OrderID:        orderID.String(),
+		UserID:         order.CustomerID.String(),
+		Items:          items,
+		TotalPrice:     total,
+		DeliveryFee:    order.DeliveryFee,
+		AllowPartial:   order.AllowPartial,
+		SplitBackorder: order.SplitBackorder,
+	}
+	if order.HoldPayment {
+		params.PaymentHoldTTL = s.orderPaymentHoldTTL
+	}
+	return params
+}
+
+// storeOrder prices items of the order and stores it, replayed is true when idempotencyKey was used before
+func (s *orderService) storeOrder(ctx context.Context, order *appdata.Order, idempotencyKey string) (uuid.UUID, bool, error) {
+	var (
+		fingerprint string
+		orderID     uuid.UUID
+		replayed    bool
+		err         error
+	)
+	if idempotencyKey != "" {
+		fingerprint, err = idempotency.Fingerprint(order)
+		if err != nil {
+			return uuid.Nil, false, err
+		}
+		// Cheap check before going to the product catalog, it is repeated under the lock below
+		err = s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+			orderID, replayed, err = s.replayStoreOrder(ctx, provider, idempotencyKey, fingerprint)
+			return err
+		})
+		if err != nil || replayed {
+			return orderID, replayed, err
+		}
+	}
+
+	// Prices are never taken from the client, every item is priced by the product catalog
+	items, err := mergeItems(order.Items)
+	if err != nil {
+		return uuid.Nil, false, err
+	}
+	order.Items, err = s.priceItems(ctx, items)
+	if err != nil {
+		return uuid.Nil, false, err
+	}
+	shippingAddress, err := s.shippingAddress(ctx, *order)
+	if err != nil {
+		return uuid.Nil, false, err
+	}
+	order.DeliveryFee = s.deliveryFees[order.DeliveryMethod]
+
+	storeOrder := func(provider RepositoryProvider) error {
+		if idempotencyKey != "" {
+			var err error
+			orderID, replayed, err = s.replayStoreOrder(ctx, provider, idempotencyKey, fingerprint)
+			if err != nil || replayed {
+				return err
+			}
+		}
+		orderID = order.ID
+
+		domainService := s.domainService(ctx, provider)
 		if order.ID == uuid.Nil {
 			oID, err := domainService.CreateOrder(order.CustomerID)
 			if err != nil {
@@ -53,74 +258,471 @@ func (s *orderService) StoreOrder(ctx context.Context, order appdata.Order) (uui
 			orderID = oID
 		}
 
+		if order.AllowPartial {
+			err := domainService.AllowPartialFulfilment(orderID, order.SplitBackorder)
+			if err != nil {
+				return err
+			}
+		}
+
+		if order.HoldPayment {
+			err := domainService.HoldPayment(orderID)
+			if err != nil {
+				return err
+			}
+		}
+
+		if order.DeliveryMethod != appdata.Pickup || shippingAddress != nil {
+			err := domainService.SetDelivery(orderID, model.DeliveryMethod(order.DeliveryMethod), shippingAddress, order.DeliveryFee)
+			if err != nil {
+				return err
+			}
+		}
+
 		err := domainService.SetStatus(orderID, model.OrderStatus(order.Status))
 		if err != nil {
 			return err
 		}
 
 		for _, item := range order.Items {
-			err := domainService.AddItem(orderID, item.ProductID, item.TotalPrice)
+			err := domainService.AddItem(orderID, item.ProductID, item.Count, item.Price)
 			if err != nil {
 				return err
 			}
 		}
 
+		if idempotencyKey != "" {
+			return idempotency.Remember(provider.IdempotencyRepository(ctx), storeOrderIdempotencyScope, idempotencyKey, fingerprint, orderID.String())
+		}
 		return nil
+	}
+	err = retryOnConflict(func() error {
+		if idempotencyKey != "" {
+			// Lock makes concurrent requests with the same key wait for the first one and replay its result
+			return s.luow.Execute(ctx, []string{idempotency.Lock(storeOrderIdempotencyScope, idempotencyKey)}, storeOrder)
+		}
+		return s.uow.Execute(ctx, storeOrder)
 	})
+	return orderID, replayed, err
+}
 
-	if err == nil && order.Status == appdata.Open {
-		items := make([]workflows.OrderItemParam, len(order.Items))
-		var total float64
-		for i, it := range order.Items {
-			items[i] = workflows.OrderItemParam{ProductID: it.ProductID.String(), Quantity: it.Count}
-			total += it.TotalPrice
-		}
-
-		_, sagaErr := s.temporalClient.ExecuteWorkflow(ctx, client.StartWorkflowOptions{
-			ID:        "order-saga-" + orderID.String(),
-			TaskQueue: "order_task_queue",
-		}, workflows.CreateOrderSaga, workflows.OrderSagaParams{
-			OrderID:    orderID.String(),
-			UserID:     order.CustomerID.String(),
-			Items:      items,
-			TotalPrice: total,
-		})
+func (s *orderService) replayStoreOrder(ctx context.Context, provider RepositoryProvider, idempotencyKey, fingerprint string) (uuid.UUID, bool, error) {
+	response, ok, err := idempotency.Replay(
+		provider.IdempotencyRepository(ctx),
+		storeOrderIdempotencyScope,
+		idempotencyKey,
+		fingerprint,
+		s.idempotencyKeyRetention,
+	)
+	if err != nil || !ok {
+		return uuid.Nil, false, err
+	}
+	orderID, err := uuid.Parse(response)
+	return orderID, err == nil, errors.WithStack(err)
+}
 
-		if sagaErr != nil {
-			return orderID, sagaErr
+// shippingAddress takes a snapshot of the customer address selected by the order
+func (s *orderService) shippingAddress(ctx context.Context, order appdata.Order) (*model.ShippingAddress, error) {
+	if order.AddressID == nil {
+		return nil, nil
+	}
+	address, err := s.addressBook.FindAddress(ctx, *order.AddressID)
+	if err != nil {
+		return nil, err
+	}
+	if address.UserID != order.CustomerID {
+		return nil, errors.WithStack(ErrAddressNotFound)
+	}
+	return &model.ShippingAddress{
+		Recipient:  address.Recipient,
+		Phone:      address.Phone,
+		Country:    address.Country,
+		City:       address.City,
+		Street:     address.Street,
+		PostalCode: address.PostalCode,
+	}, nil
+}
+
+// mergeItems sums up counts of lines with the same product, the order is priced, reserved and stored by product
+func mergeItems(items []appdata.OrderItem) ([]appdata.OrderItem, error) {
+	merged := make([]appdata.OrderItem, 0, len(items))
+	for _, item := range items {
+		// Counts are checked before summing, so a negative line can not hide in a merged one
+		if item.Count <= 0 {
+			return nil, errors.WithStack(service.ErrInvalidItemCount)
+		}
+		i := slices.IndexFunc(merged, func(m appdata.OrderItem) bool {
+			return m.ProductID == item.ProductID
+		})
+		if i >= 0 {
+			merged[i].Count += item.Count
+			continue
 		}
+		merged = append(merged, item)
 	}
+	return merged, nil
+}
 
-	return orderID, err
+func (s *orderService) priceItems(ctx context.Context, items []appdata.OrderItem) ([]appdata.OrderItem, error) {
+	pricedItems := make([]appdata.OrderItem, len(items))
+	for i, item := range items {
+		product, err := s.productCatalog.FindProduct(ctx, item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if product.DeletedAt != nil {
+			return nil, errors.WithStack(ErrProductRemoved)
+		}
+
+		item.Price = product.Price
+		item.TotalPrice = product.Price * float64(item.Count)
+		pricedItems[i] = item
+	}
+	return pricedItems, nil
 }
 
 func (s *orderService) SetOrderStatus(ctx context.Context, orderID uuid.UUID, status int) error {
-	return s.luow.Execute(ctx, []string{orderLock(orderID)}, func(provider RepositoryProvider) error {
-		return s.domainService(ctx, provider.OrderRepository(ctx)).SetStatus(orderID, model.OrderStatus(status))
+	return s.updateOrder(ctx, func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider).SetStatus(orderID, model.OrderStatus(status))
+	})
+}
+
+func (s *orderService) UpdateOrderStatus(ctx context.Context, orderID uuid.UUID, status appdata.OrderStatus) error {
+	switch status {
+	case appdata.Open, appdata.Pending, appdata.Paid, appdata.Cancelled,
+		appdata.Returned, appdata.PartiallyReturned, appdata.Refunded:
+		return errors.WithStack(service.ErrInvalidOrderStatus)
+	default:
+	}
+
+	return s.updateOrder(ctx, func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider).SetStatus(orderID, model.OrderStatus(status))
 	})
 }
 
+func (s *orderService) CancelOrder(ctx context.Context, orderID uuid.UUID) error {
+	return s.cancelOrder(ctx, orderID, "")
+}
+
+func (s *orderService) ExpireOrder(ctx context.Context, orderID uuid.UUID) error {
+	order, err := s.FindOrder(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if order.Status != appdata.Open && order.Status != appdata.Pending {
+		return nil
+	}
+	sagaStatus, err := s.queryOrderSaga(ctx, orderID)
+	if err == nil && sagaStatus.Step == workflows.OrderSagaAwaitingCapture {
+		// Held payment has its own expiry, the saga cancels the order once the hold is not captured in time
+		return nil
+	}
+	if err != nil && !errors.Is(err, ErrOrderSagaNotFound) {
+		return err
+	}
+
+	err = s.cancelOrder(ctx, orderID, "order is not paid within "+s.orderPaymentTTL.String())
+	// Order has been paid since it was found
+	if errors.Is(err, service.ErrInvalidOrderStatus) {
+		return nil
+	}
+	return err
+}
+
+// cancelOrder signals the running saga to compensate and cancel the order, reason is reported as LastError of the saga
+func (s *orderService) cancelOrder(ctx context.Context, orderID uuid.UUID, reason string) error {
+	sagaStatus, err := s.queryOrderSaga(ctx, orderID)
+	switch {
+	case err == nil:
+		switch sagaStatus.Step {
+		case workflows.OrderSagaCompleted:
+			// Cancel signal would be dropped by the saga which has paid the order
+			return errors.WithStack(service.ErrInvalidOrderStatus)
+		case workflows.OrderSagaCompensating, workflows.OrderSagaCancelled:
+			return nil
+		default:
+		}
+		// Running saga owns the order, so it has to release reserved products and refund the wallet by itself
+		err = s.temporalClient.SignalWorkflow(ctx, workflows.OrderSagaWorkflowID(orderID.String()), "", workflows.CancelOrderSignal, reason)
+		if err == nil {
+			return nil
+		}
+		// Saga closed after the query, so the order is cancelled by the guarded transition below
+		var notFound *serviceerror.NotFound
+		if !errors.As(err, &notFound) {
+			return errors.WithStack(err)
+		}
+	case !errors.Is(err, ErrOrderSagaNotFound):
+		return err
+	}
+
+	return s.updateOrder(ctx, func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider).SetStatus(orderID, model.Cancelled)
+	})
+}
+
+func (s *orderService) CaptureOrderPayment(ctx context.Context, orderID uuid.UUID, amount float64) error {
+	if amount < 0 {
+		return errors.WithStack(ErrInvalidCaptureAmount)
+	}
+	// Signal sent to a saga which does not wait for capture would be dropped silently
+	sagaStatus, err := s.queryOrderSaga(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if sagaStatus.Step != workflows.OrderSagaAwaitingCapture {
+		return errors.WithStack(ErrOrderNotAwaitingCapture)
+	}
+	err = s.temporalClient.SignalWorkflow(ctx, workflows.OrderSagaWorkflowID(orderID.String()), "", workflows.CapturePaymentSignal, amount)
+	if err != nil {
+		var notFound *serviceerror.NotFound
+		if errors.As(err, &notFound) {
+			return errors.WithStack(ErrOrderSagaNotFound)
+		}
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func (s *orderService) RemoveOrderProduct(ctx context.Context, orderID, productID uuid.UUID) error {
+	return s.updateOrder(ctx, func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider).RemoveItem(orderID, productID)
+	})
+}
+
+func (s *orderService) ApplyPaymentStatus(ctx context.Context, orderID uuid.UUID, status model.PaymentStatus) error {
+	switch status {
+	case model.PaymentSucceeded:
+		return s.updateOrder(ctx, func(provider RepositoryProvider) error {
+			order, err := provider.OrderRepository(ctx).Find(orderID)
+			if err != nil {
+				return err
+			}
+			// Order may be already paid by CreateOrderSaga
+			if order.Status != model.Pending {
+				return nil
+			}
+			return s.domainService(ctx, provider).SetStatus(orderID, model.Paid)
+		})
+	case model.PaymentRefunded:
+		// Payment refunded in full refunds the order which is paid but not shipped or is returned in full,
+		// shipped and partially returned orders keep their status
+		return s.updateOrder(ctx, func(provider RepositoryProvider) error {
+			order, err := provider.OrderRepository(ctx).Find(orderID)
+			if err != nil {
+				return err
+			}
+			switch order.Status {
+			case model.Paid, model.Processing, model.Returned:
+				return s.domainService(ctx, provider).SetStatus(orderID, model.Refunded)
+			default:
+				return nil
+			}
+		})
+	case model.PaymentFailed, model.PaymentCancelled:
+		var orderStatus model.OrderStatus
+		err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+			order, err := provider.OrderRepository(ctx).Find(orderID)
+			if err != nil {
+				return err
+			}
+			orderStatus = order.Status
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if orderStatus != model.Open && orderStatus != model.Pending {
+			return nil
+		}
+		return s.CancelOrder(ctx, orderID)
+	default:
+		return nil
+	}
+}
+
+func (s *orderService) GetOrderSagaStatus(ctx context.Context, orderID uuid.UUID) (appdata.OrderSagaStatus, error) {
+	sagaStatus, err := s.queryOrderSaga(ctx, orderID)
+	if err != nil {
+		return appdata.OrderSagaStatus{}, err
+	}
+
+	result := appdata.OrderSagaStatus{
+		OrderID:       orderID,
+		Step:          appdata.OrderSagaStep(sagaStatus.Step),
+		CurrentItem:   sagaStatus.CurrentItem,
+		ReservedItems: make([]appdata.OrderSagaItem, len(sagaStatus.ReservedItems)),
+	}
+	for i, item := range sagaStatus.ReservedItems {
+		productID, err := uuid.Parse(item.ProductID)
+		if err != nil {
+			return appdata.OrderSagaStatus{}, errors.WithStack(err)
+		}
+		result.ReservedItems[i] = appdata.OrderSagaItem{
+			ProductID: productID,
+			Quantity:  item.Quantity,
+		}
+	}
+	for _, item := range sagaStatus.UnfulfilledItems {
+		productID, err := uuid.Parse(item.ProductID)
+		if err != nil {
+			return appdata.OrderSagaStatus{}, errors.WithStack(err)
+		}
+		result.UnfulfilledItems = append(result.UnfulfilledItems, appdata.OrderSagaItem{
+			ProductID: productID,
+			Quantity:  item.Quantity,
+		})
+	}
+	if sagaStatus.BackorderID != "" {
+		backorderID, err := uuid.Parse(sagaStatus.BackorderID)
+		if err != nil {
+			return appdata.OrderSagaStatus{}, errors.WithStack(err)
+		}
+		result.BackorderID = &backorderID
+	}
+	if sagaStatus.HoldID != "" {
+		holdID, err := uuid.Parse(sagaStatus.HoldID)
+		if err != nil {
+			return appdata.OrderSagaStatus{}, errors.WithStack(err)
+		}
+		result.HoldID = &holdID
+	}
+	if sagaStatus.LastError != "" {
+		result.LastError = &sagaStatus.LastError
+	}
+	return result, nil
+}
+
+// queryOrderSaga returns status of the running or closed saga of the order
+func (s *orderService) queryOrderSaga(ctx context.Context, orderID uuid.UUID) (workflows.OrderSagaStatus, error) {
+	value, err := s.temporalClient.QueryWorkflow(ctx, workflows.OrderSagaWorkflowID(orderID.String()), "", workflows.OrderSagaStatusQuery)
+	if err != nil {
+		var notFound *serviceerror.NotFound
+		if errors.As(err, &notFound) {
+			return workflows.OrderSagaStatus{}, errors.WithStack(ErrOrderSagaNotFound)
+		}
+		return workflows.OrderSagaStatus{}, errors.WithStack(err)
+	}
+
+	var sagaStatus workflows.OrderSagaStatus
+	if err = value.Get(&sagaStatus); err != nil {
+		return workflows.OrderSagaStatus{}, errors.WithStack(err)
+	}
+	return sagaStatus, nil
+}
+
+func (s *orderService) MarkUnfulfilledItems(ctx context.Context, orderID uuid.UUID, items []appdata.UnfulfilledItem) (float64, error) {
+	unfulfilledItems := make([]model.UnfulfilledItem, len(items))
+	for i, item := range items {
+		unfulfilledItems[i] = model.UnfulfilledItem{
+			ProductID: item.ProductID,
+			Count:     item.Count,
+			Reason:    item.Reason,
+		}
+	}
+
+	var total float64
+	err := s.updateOrder(ctx, func(provider RepositoryProvider) error {
+		err := s.domainService(ctx, provider).MarkUnfulfilled(orderID, unfulfilledItems)
+		if err != nil {
+			return err
+		}
+		order, err := provider.OrderRepository(ctx).Find(orderID)
+		if err != nil {
+			return err
+		}
+		total = 0
+		for _, item := range order.Items {
+			total += item.TotalPrice
+		}
+		return nil
+	})
+	return total, err
+}
+
+func (s *orderService) CreateBackorder(ctx context.Context, orderID uuid.UUID) (uuid.UUID, error) {
+	var backorderID uuid.UUID
+	err := s.updateOrder(ctx, func(provider RepositoryProvider) error {
+		var err error
+		backorderID, err = s.domainService(ctx, provider).CreateBackorder(orderID)
+		return err
+	})
+	return backorderID, err
+}
+
+func (s *orderService) SubmitBackorder(ctx context.Context, orderID uuid.UUID) error {
+	order, err := s.FindOrder(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if order.ParentOrderID == nil {
+		return errors.WithStack(ErrNotBackorder)
+	}
+	if order.Status != appdata.Open {
+		return errors.WithStack(service.ErrInvalidOrderStatus)
+	}
+
+	if err = s.startOrderExpiry(ctx, orderID); err != nil {
+		return err
+	}
+	err = s.startOrderSaga(ctx, orderID, order)
+	var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+	if errors.As(err, &alreadyStarted) {
+		return errors.WithStack(ErrOrderSagaAlreadyStarted)
+	}
+	return errors.WithStack(err)
+}
+
 func (s *orderService) FindOrder(ctx context.Context, orderID uuid.UUID) (appdata.Order, error) {
 	var order appdata.Order
-	err := s.luow.Execute(ctx, []string{orderLock(orderID)}, func(provider RepositoryProvider) error {
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
 		domainOrder, err := provider.OrderRepository(ctx).Find(orderID)
 		if err != nil {
 			return err
 		}
 		order = appdata.Order{
-			ID:         domainOrder.ID,
-			CustomerID: domainOrder.CustomerID,
-			Status:     appdata.OrderStatus(domainOrder.Status),
-			Items:      make([]appdata.OrderItem, len(domainOrder.Items)),
-			CreatedAt:  domainOrder.CreatedAt,
-			UpdatedAt:  domainOrder.UpdatedAt,
-			DeletedAt:  domainOrder.DeletedAt,
+			ID:             domainOrder.ID,
+			CustomerID:     domainOrder.CustomerID,
+			Status:         appdata.OrderStatus(domainOrder.Status),
+			Items:          make([]appdata.OrderItem, len(domainOrder.Items)),
+			CreatedAt:      domainOrder.CreatedAt,
+			UpdatedAt:      domainOrder.UpdatedAt,
+			DeletedAt:      domainOrder.DeletedAt,
+			PromotionID:    domainOrder.PromotionID,
+			Discount:       domainOrder.Discount,
+			AllowPartial:   domainOrder.AllowPartial,
+			SplitBackorder: domainOrder.SplitBackorder,
+			HoldPayment:    domainOrder.HoldPayment,
+			ParentOrderID:  domainOrder.ParentOrderID,
+			BackorderID:    domainOrder.BackorderID,
+			DeliveryMethod: appdata.DeliveryMethod(domainOrder.DeliveryMethod),
+			DeliveryFee:    domainOrder.DeliveryFee,
+			PaymentID:      domainOrder.PaymentID,
+		}
+		if address := domainOrder.ShippingAddress; address != nil {
+			order.ShippingAddress = &appdata.ShippingAddress{
+				Recipient:  address.Recipient,
+				Phone:      address.Phone,
+				Country:    address.Country,
+				City:       address.City,
+				Street:     address.Street,
+				PostalCode: address.PostalCode,
+			}
+		}
+		for _, item := range domainOrder.UnfulfilledItems {
+			order.UnfulfilledItems = append(order.UnfulfilledItems, appdata.UnfulfilledItem{
+				ProductID: item.ProductID,
+				Count:     item.Count,
+				Price:     item.Price,
+				Reason:    item.Reason,
+			})
 		}
 		for i, item := range domainOrder.Items {
 			order.Items[i] = appdata.OrderItem{
 				OrderID:    item.OrderID,
 				ProductID:  item.ProductID,
 				Count:      item.Count,
+				Price:      item.Price,
 				TotalPrice: item.TotalPrice,
 			}
 		}
@@ -129,19 +731,37 @@ func (s *orderService) FindOrder(ctx context.Context, orderID uuid.UUID) (appdat
 	return order, err
 }
 
-func (s *orderService) domainService(ctx context.Context, repository model.OrderRepository) service.OrderService {
-	return service.NewOrderService(repository, s.domainEventDispatcher(ctx))
+func (s *orderService) domainService(ctx context.Context, provider RepositoryProvider) service.OrderService {
+	return service.NewOrderService(provider.OrderRepository(ctx), s.domainEventDispatcher(ctx, provider))
 }
 
-func (s *orderService) domainEventDispatcher(ctx context.Context) commonevent.Dispatcher {
-	return &domainEventDispatcher{
-		ctx:             ctx,
-		eventDispatcher: s.eventDispatcher,
-	}
+func (s *orderService) domainEventDispatcher(ctx context.Context, provider RepositoryProvider) commonevent.Dispatcher {
+	return newDomainEventDispatcher(ctx, s.eventDispatcher, provider)
 }
 
-const baseOrderLock = "order_"
+// SetOrderPayment links the payment created by CreateOrderSaga to the order
+func (s *orderService) SetOrderPayment(ctx context.Context, orderID, paymentID uuid.UUID) error {
+	return s.updateOrder(ctx, func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider).SetPayment(orderID, paymentID)
+	})
+}
 
-func orderLock(id uuid.UUID) string {
-	return baseOrderLock + id.String()
+// updateOrder runs f in a new transaction again when the order is changed concurrently
+func (s *orderService) updateOrder(ctx context.Context, f func(provider RepositoryProvider) error) error {
+	return retryOnConflict(func() error {
+		return s.uow.Execute(ctx, f)
+	})
+}
+
+const maxConflictRetries = 3
+
+func retryOnConflict(f func() error) error {
+	var err error
+	for range maxConflictRetries {
+		err = f()
+		if !errors.Is(err, model.ErrOrderVersionConflict) {
+			return err
+		}
+	}
+	return err
 }
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/client"
+
+	appdata "order/pkg/order/app/data"
+	"order/pkg/order/domain/model"
+	"order/pkg/order/domain/service"
+	"order/pkg/order/infrastructure/temporal/workflows"
+)
+
+type OrderReturnService interface {
+	// RequestReturn stores the return of order items and starts OrderReturnWorkflow waiting for the decision
+	RequestReturn(ctx context.Context, orderID uuid.UUID, items []appdata.OrderReturnItem, reason string) (uuid.UUID, error)
+	// DecideReturn stores the decision on the requested return and signals it to OrderReturnWorkflow, the workflow is started when it is not running
+	DecideReturn(ctx context.Context, returnID uuid.UUID, approved bool, reason string) error
+
+	MarkReturnApproved(ctx context.Context, returnID uuid.UUID) error
+	MarkReturnRejected(ctx context.Context, returnID uuid.UUID, reason string) error
+	// CompleteReturn is called once returned products are restocked and refunded
+	CompleteReturn(ctx context.Context, returnID uuid.UUID) error
+}
+
+func NewOrderReturnService(
+	uow UnitOfWork,
+	luow LockableUnitOfWork,
+	eventDispatcher outbox.EventDispatcher[outbox.Event],
+	temporalClient client.Client,
+) OrderReturnService {
+	return &orderReturnService{
+		uow:             uow,
+		luow:            luow,
+		eventDispatcher: eventDispatcher,
+		temporalClient:  temporalClient,
+	}
+}
+
+type orderReturnService struct {
+	uow             UnitOfWork
+	luow            LockableUnitOfWork
+	eventDispatcher outbox.EventDispatcher[outbox.Event]
+	temporalClient  client.Client
+}
+
+func (s *orderReturnService) RequestReturn(ctx context.Context, orderID uuid.UUID, items []appdata.OrderReturnItem, reason string) (uuid.UUID, error) {
+	returnItems := make([]model.OrderReturnItem, len(items))
+	for i, item := range items {
+		returnItems[i] = model.OrderReturnItem{
+			ProductID: item.ProductID,
+			Count:     item.Count,
+		}
+	}
+
+	var orderReturn *model.OrderReturn
+	// Lock keeps concurrent requests from starting two returns of the order
+	err := s.luow.Execute(ctx, []string{orderReturnLock(orderID)}, func(provider RepositoryProvider) error {
+		returnID, err := s.domainService(ctx, provider).RequestReturn(orderID, returnItems, reason)
+		if errors.Is(err, service.ErrOrderReturnInProgress) {
+			orderReturn, err = findRequestedReturn(ctx, provider, orderID)
+			if err != nil {
+				return err
+			}
+			return errors.WithStack(service.ErrOrderReturnInProgress)
+		}
+		if err != nil {
+			return err
+		}
+		orderReturn, err = provider.OrderReturnRepository(ctx).Find(returnID)
+		return err
+	})
+	if errors.Is(err, service.ErrOrderReturnInProgress) && orderReturn != nil {
+		// Workflow of the requested return may have failed to start after the return was stored, so retry starts it
+		if startErr := s.startReturnWorkflow(ctx, orderReturn); startErr != nil {
+			return uuid.Nil, startErr
+		}
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return orderReturn.ID, s.startReturnWorkflow(ctx, orderReturn)
+}
+
+// startReturnWorkflow starts OrderReturnWorkflow of the return, workflow started already is not an error
+func (s *orderReturnService) startReturnWorkflow(ctx context.Context, orderReturn *model.OrderReturn) error {
+	_, err := s.temporalClient.ExecuteWorkflow(ctx, client.StartWorkflowOptions{
+		ID:        workflows.OrderReturnWorkflowID(orderReturn.ID.String()),
+		TaskQueue: "order_task_queue",
+	}, workflows.OrderReturnWorkflow, orderReturnParams(orderReturn))
+	var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+	if errors.As(err, &alreadyStarted) {
+		return nil
+	}
+	return errors.WithStack(err)
+}
+
+// findRequestedReturn returns the return of the order waiting for the decision, nil is returned when the return in progress is approved
+func findRequestedReturn(ctx context.Context, provider RepositoryProvider, orderID uuid.UUID) (*model.OrderReturn, error) {
+	returns, err := provider.OrderReturnRepository(ctx).FindByOrderID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range returns {
+		if returns[i].Status == model.ReturnStatusRequested {
+			return &returns[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func orderReturnParams(orderReturn *model.OrderReturn) workflows.OrderReturnParams {
+	params := workflows.OrderReturnParams{
+		ReturnID:     orderReturn.ID.String(),
+		OrderID:      orderReturn.OrderID.String(),
+		CustomerID:   orderReturn.CustomerID.String(),
+		Items:        make([]workflows.OrderItemParam, len(orderReturn.Items)),
+		RefundAmount: orderReturn.RefundAmount,
+	}
+	for i, item := range orderReturn.Items {
+		params.Items[i] = workflows.OrderItemParam{ProductID: item.ProductID.String(), Quantity: item.Count}
+	}
+	return params
+}
+
+func (s *orderReturnService) DecideReturn(ctx context.Context, returnID uuid.UUID, approved bool, reason string) error {
+	var orderReturn *model.OrderReturn
+	// Decision is stored before the signal, so of concurrent opposite decisions only the first one is signalled,
+	// repeated decision is stored already and is signalled again
+	err := retryOnConflict(func() error {
+		return s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+			var err error
+			if approved {
+				err = s.domainService(ctx, provider).Approve(returnID)
+			} else {
+				err = s.domainService(ctx, provider).Reject(returnID, reason)
+			}
+			if err != nil {
+				return err
+			}
+			orderReturn, err = provider.OrderReturnRepository(ctx).Find(returnID)
+			return err
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	// Workflow is started along with the signal when it failed to start on request
+	_, err = s.temporalClient.SignalWithStartWorkflow(
+		ctx,
+		workflows.OrderReturnWorkflowID(returnID.String()),
+		workflows.ReturnDecisionSignal,
+		workflows.ReturnDecision{Approved: approved, Reason: reason},
+		client.StartWorkflowOptions{
+			ID:        workflows.OrderReturnWorkflowID(returnID.String()),
+			TaskQueue: "order_task_queue",
+		},
+		workflows.OrderReturnWorkflow,
+		orderReturnParams(orderReturn),
+	)
+	return errors.WithStack(err)
+}
+
+func (s *orderReturnService) MarkReturnApproved(ctx context.Context, returnID uuid.UUID) error {
+	return retryOnConflict(func() error {
+		return s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+			return s.domainService(ctx, provider).Approve(returnID)
+		})
+	})
+}
+
+func (s *orderReturnService) MarkReturnRejected(ctx context.Context, returnID uuid.UUID, reason string) error {
+	return retryOnConflict(func() error {
+		return s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+			return s.domainService(ctx, provider).Reject(returnID, reason)
+		})
+	})
+}
+
+func (s *orderReturnService) CompleteReturn(ctx context.Context, returnID uuid.UUID) error {
+	return retryOnConflict(func() error {
+		return s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+			return s.domainService(ctx, provider).Complete(returnID)
+		})
+	})
+}
+
+func (s *orderReturnService) domainService(ctx context.Context, provider RepositoryProvider) service.OrderReturnService {
+	dispatcher := newDomainEventDispatcher(ctx, s.eventDispatcher, provider)
+	return service.NewOrderReturnService(
+		provider.OrderReturnRepository(ctx),
+		provider.OrderRepository(ctx),
+		service.NewOrderService(provider.OrderRepository(ctx), dispatcher),
+		dispatcher,
+	)
+}
+
+const baseOrderReturnLock = "order_return_"
+
+func orderReturnLock(orderID uuid.UUID) string {
+	return baseOrderReturnLock + orderID.String()
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.temporal.io/sdk/client"
+
+	appdata "order/pkg/order/app/data"
+	"order/pkg/order/domain/model"
+	"order/pkg/order/domain/service"
+	"order/pkg/order/infrastructure/temporal/workflows"
+)
+
+type OrderTemplateService interface {
+	CreateOrderTemplate(ctx context.Context, customerID uuid.UUID, items []appdata.OrderTemplateItem) (uuid.UUID, error)
+	// ScheduleOrderTemplate creates Temporal Schedule which places an order from the template on every tick
+	ScheduleOrderTemplate(ctx context.Context, templateID uuid.UUID, schedule appdata.OrderSchedule) error
+	PauseOrderSchedule(ctx context.Context, templateID uuid.UUID) error
+	ResumeOrderSchedule(ctx context.Context, templateID uuid.UUID) error
+	// PrepareScheduledOrder stores an order from the template once per scheduled run and returns params of its saga
+	PrepareScheduledOrder(ctx context.Context, templateID uuid.UUID, runID string) (workflows.OrderSagaParams, error)
+	ReportScheduledOrderFailure(ctx context.Context, templateID uuid.UUID, orderID *uuid.UUID, reason string) error
+}
+
+func NewOrderTemplateService(
+	uow UnitOfWork,
+	luow LockableUnitOfWork,
+	eventDispatcher outbox.EventDispatcher[outbox.Event],
+	temporalClient client.Client,
+	orderService OrderService,
+) OrderTemplateService {
+	return &orderTemplateService{
+		uow:             uow,
+		luow:            luow,
+		eventDispatcher: eventDispatcher,
+		temporalClient:  temporalClient,
+		orderService:    orderService,
+	}
+}
+
+type orderTemplateService struct {
+	uow             UnitOfWork
+	luow            LockableUnitOfWork
+	eventDispatcher outbox.EventDispatcher[outbox.Event]
+	temporalClient  client.Client
+	orderService    OrderService
+}
+
+func (s *orderTemplateService) CreateOrderTemplate(ctx context.Context, customerID uuid.UUID, items []appdata.OrderTemplateItem) (uuid.UUID, error) {
+	templateItems := make([]model.OrderTemplateItem, len(items))
+	for i, item := range items {
+		templateItems[i] = model.OrderTemplateItem{
+			ProductID: item.ProductID,
+			Count:     item.Count,
+		}
+	}
+
+	var templateID uuid.UUID
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		var err error
+		templateID, err = s.domainService(ctx, provider).CreateTemplate(customerID, templateItems)
+		return err
+	})
+	return templateID, err
+}
+
+func (s *orderTemplateService) ScheduleOrderTemplate(ctx context.Context, templateID uuid.UUID, schedule appdata.OrderSchedule) error {
+	return s.luow.Execute(ctx, []string{orderTemplateLock(templateID)}, func(provider RepositoryProvider) error {
+		err := s.domainService(ctx, provider).Schedule(templateID, model.OrderSchedule{
+			CronExpression: schedule.CronExpression,
+			Interval:       schedule.Interval,
+		})
+		if err != nil {
+			return err
+		}
+
+		spec := client.ScheduleSpec{}
+		if schedule.CronExpression != "" {
+			spec.CronExpressions = []string{schedule.CronExpression}
+		} else {
+			spec.Intervals = []client.ScheduleIntervalSpec{{Every: schedule.Interval}}
+		}
+		// Schedule is created within the transaction, so the template is not stored as scheduled when it fails
+		_, err = s.temporalClient.ScheduleClient().Create(ctx, client.ScheduleOptions{
+			ID:   workflows.OrderScheduleID(templateID.String()),
+			Spec: spec,
+			Action: &client.ScheduleWorkflowAction{
+				ID:        "scheduled-order-" + templateID.String(),
+				Workflow:  workflows.ScheduledOrderWorkflow,
+				Args:      []interface{}{templateID.String()},
+				TaskQueue: "order_task_queue",
+			},
+		})
+		return errors.WithStack(err)
+	})
+}
+
+func (s *orderTemplateService) PauseOrderSchedule(ctx context.Context, templateID uuid.UUID) error {
+	return s.setSchedulePaused(ctx, templateID, true)
+}
+
+func (s *orderTemplateService) ResumeOrderSchedule(ctx context.Context, templateID uuid.UUID) error {
+	return s.setSchedulePaused(ctx, templateID, false)
+}
+
+func (s *orderTemplateService) setSchedulePaused(ctx context.Context, templateID uuid.UUID, paused bool) error {
+	return s.luow.Execute(ctx, []string{orderTemplateLock(templateID)}, func(provider RepositoryProvider) error {
+		err := s.domainService(ctx, provider).SetSchedulePaused(templateID, paused)
+		if err != nil {
+			return err
+		}
+
+		handle := s.temporalClient.ScheduleClient().GetHandle(ctx, workflows.OrderScheduleID(templateID.String()))
+		if paused {
+			return errors.WithStack(handle.Pause(ctx, client.SchedulePauseOptions{Note: "paused by customer"}))
+		}
+		return errors.WithStack(handle.Unpause(ctx, client.ScheduleUnpauseOptions{Note: "resumed by customer"}))
+	})
+}
+
+func (s *orderTemplateService) PrepareScheduledOrder(ctx context.Context, templateID uuid.UUID, runID string) (workflows.OrderSagaParams, error) {
+	var template *model.OrderTemplate
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		var err error
+		template, err = provider.OrderTemplateRepository(ctx).Find(templateID)
+		return err
+	})
+	if err != nil {
+		return workflows.OrderSagaParams{}, err
+	}
+
+	order := appdata.Order{
+		CustomerID: template.CustomerID,
+		Status:     appdata.Open,
+		Items:      make([]appdata.OrderItem, len(template.Items)),
+	}
+	for i, item := range template.Items {
+		order.Items[i] = appdata.OrderItem{
+			ProductID: item.ProductID,
+			Count:     item.Count,
+		}
+	}
+	return s.orderService.PrepareOrder(ctx, order, "schedule_"+runID)
+}
+
+func (s *orderTemplateService) ReportScheduledOrderFailure(ctx context.Context, templateID uuid.UUID, orderID *uuid.UUID, reason string) error {
+	return s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider).ReportFailedRun(templateID, orderID, reason)
+	})
+}
+
+func (s *orderTemplateService) domainService(ctx context.Context, provider RepositoryProvider) service.OrderTemplateService {
+	return service.NewOrderTemplateService(provider.OrderTemplateRepository(ctx), &integrationEventDispatcher{
+		ctx:             ctx,
+		eventDispatcher: s.eventDispatcher,
+	})
+}
+
+const baseOrderTemplateLock = "order_template_"
+
+func orderTemplateLock(templateID uuid.UUID) string {
+	return baseOrderTemplateLock + templateID.String()
+}
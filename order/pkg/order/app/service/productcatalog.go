@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	appdata "order/pkg/order/app/data"
+)
+
+var (
+	ErrProductNotFound = errors.New("product not found")
+	ErrProductRemoved  = errors.New("product removed")
+)
+
+// ProductCatalog provides current products data from the product service
+type ProductCatalog interface {
+	FindProduct(ctx context.Context, productID uuid.UUID) (appdata.Product, error)
+}
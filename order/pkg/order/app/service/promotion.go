@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+	"github.com/google/uuid"
+
+	appdata "order/pkg/order/app/data"
+	"order/pkg/order/domain/model"
+	"order/pkg/order/domain/service"
+)
+
+type PromotionService interface {
+	CreatePromotion(ctx context.Context, promotion appdata.Promotion) (uuid.UUID, error)
+	ApplyPromoCode(ctx context.Context, orderID uuid.UUID, code string) error
+	// RedeemPromoCode counts redemption of the promo code applied to the order and returns discount to take off the charged amount
+	RedeemPromoCode(ctx context.Context, orderID uuid.UUID) (float64, error)
+	ReleasePromoCode(ctx context.Context, orderID uuid.UUID) error
+}
+
+func NewPromotionService(
+	uow UnitOfWork,
+	luow LockableUnitOfWork,
+	eventDispatcher outbox.EventDispatcher[outbox.Event],
+) PromotionService {
+	return &promotionService{
+		uow:             uow,
+		luow:            luow,
+		eventDispatcher: eventDispatcher,
+	}
+}
+
+type promotionService struct {
+	uow             UnitOfWork
+	luow            LockableUnitOfWork
+	eventDispatcher outbox.EventDispatcher[outbox.Event]
+}
+
+func (s *promotionService) CreatePromotion(ctx context.Context, promotion appdata.Promotion) (uuid.UUID, error) {
+	var promotionID uuid.UUID
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		var err error
+		promotionID, err = s.domainService(ctx, provider).CreatePromotion(model.Promotion{
+			Code:             promotion.Code,
+			Kind:             model.PromotionKind(promotion.Kind),
+			Value:            promotion.Value,
+			MinOrderTotal:    promotion.MinOrderTotal,
+			PerCustomerLimit: promotion.PerCustomerLimit,
+			ValidFrom:        promotion.ValidFrom,
+			ValidTo:          promotion.ValidTo,
+		})
+		return err
+	})
+	return promotionID, err
+}
+
+func (s *promotionService) ApplyPromoCode(ctx context.Context, orderID uuid.UUID, code string) error {
+	return retryOnConflict(func() error {
+		return s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+			return s.domainService(ctx, provider).ApplyPromoCode(orderID, code)
+		})
+	})
+}
+
+func (s *promotionService) RedeemPromoCode(ctx context.Context, orderID uuid.UUID) (float64, error) {
+	var order *model.Order
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		var err error
+		order, err = provider.OrderRepository(ctx).Find(orderID)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if order.PromotionID == nil {
+		return 0, nil
+	}
+
+	var discount float64
+	err = retryOnConflict(func() error {
+		// Lock makes concurrent orders of the customer count redemptions one by one
+		return s.luow.Execute(ctx, []string{promotionLockByCustomer(*order.PromotionID, order.CustomerID)}, func(provider RepositoryProvider) error {
+			var err error
+			discount, err = s.domainService(ctx, provider).RedeemPromoCode(orderID)
+			return err
+		})
+	})
+	return discount, err
+}
+
+func (s *promotionService) ReleasePromoCode(ctx context.Context, orderID uuid.UUID) error {
+	return retryOnConflict(func() error {
+		return s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+			return s.domainService(ctx, provider).ReleasePromoCode(orderID)
+		})
+	})
+}
+
+func (s *promotionService) domainService(ctx context.Context, provider RepositoryProvider) service.PromotionService {
+	return service.NewPromotionService(
+		provider.PromotionRepository(ctx),
+		provider.OrderRepository(ctx),
+		newDomainEventDispatcher(ctx, s.eventDispatcher, provider),
+	)
+}
+
+const basePromotionLock = "promotion_"
+
+func promotionLockByCustomer(promotionID, customerID uuid.UUID) string {
+	return basePromotionLock + promotionID.String() + "_customer_" + customerID.String()
+}
@@ -3,11 +3,18 @@ package service
 import (
 	"context"
 
+	"order/pkg/common/idempotency"
 	"order/pkg/order/domain/model"
 )
 
 type RepositoryProvider interface {
 	OrderRepository(ctx context.Context) model.OrderRepository
+	IdempotencyRepository(ctx context.Context) idempotency.Repository
+	OrderHistoryRepository(ctx context.Context) OrderHistoryRepository
+	CartRepository(ctx context.Context) model.CartRepository
+	PromotionRepository(ctx context.Context) model.PromotionRepository
+	OrderTemplateRepository(ctx context.Context) model.OrderTemplateRepository
+	OrderReturnRepository(ctx context.Context) model.OrderReturnRepository
 }
 
 type LockableUnitOfWork interface {
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCartNotFound = errors.New("cart not found")
+	// ErrCartVersionConflict means the cart was changed concurrently since it was found
+	ErrCartVersionConflict = errors.New("cart version conflict")
+)
+
+// Cart collects products of the customer before checkout, every customer has at most one cart
+type Cart struct {
+	ID         uuid.UUID
+	CustomerID uuid.UUID
+	Items      []CartItem
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	// Version is zero for a new cart and is incremented by every Store
+	Version int
+}
+
+type CartItem struct {
+	ProductID uuid.UUID
+	Count     int
+}
+
+// Expired reports whether the cart was not changed for longer than ttl
+func (c *Cart) Expired(ttl time.Duration, now time.Time) bool {
+	return ttl > 0 && c.UpdatedAt.Add(ttl).Before(now)
+}
+
+type CartRepository interface {
+	NextID() (uuid.UUID, error)
+	Store(cart *Cart) error
+	FindByCustomerID(customerID uuid.UUID) (*Cart, error)
+	Remove(id uuid.UUID) error
+	// RemoveInactive removes carts not changed since updatedBefore
+	RemoveInactive(updatedBefore time.Time) error
+}
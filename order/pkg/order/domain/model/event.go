@@ -30,11 +30,98 @@ func (e OrderRemoved) Type() string {
 }
 
 type OrderStatusChanged struct {
-	OrderID uuid.UUID
-	From    OrderStatus
-	To      OrderStatus
+	OrderID    uuid.UUID
+	CustomerID uuid.UUID
+	From       OrderStatus
+	To         OrderStatus
 }
 
 func (e OrderStatusChanged) Type() string {
 	return "OrderStatusChanged"
 }
+
+type OrderPromoCodeApplied struct {
+	OrderID     uuid.UUID
+	PromotionID uuid.UUID
+	Code        string
+	Discount    float64
+}
+
+func (e OrderPromoCodeApplied) Type() string {
+	return "OrderPromoCodeApplied"
+}
+
+type OrderItemsUnfulfilled struct {
+	OrderID uuid.UUID
+	Items   []UnfulfilledItem
+}
+
+func (e OrderItemsUnfulfilled) Type() string {
+	return "OrderItemsUnfulfilled"
+}
+
+type OrderBackorderCreated struct {
+	OrderID     uuid.UUID
+	BackorderID uuid.UUID
+}
+
+func (e OrderBackorderCreated) Type() string {
+	return "OrderBackorderCreated"
+}
+
+type OrderReturnRequested struct {
+	ReturnID   uuid.UUID
+	OrderID    uuid.UUID
+	CustomerID uuid.UUID
+	Items      []OrderReturnItem
+	Reason     string
+}
+
+func (e OrderReturnRequested) Type() string {
+	return "OrderReturnRequested"
+}
+
+type OrderReturnApproved struct {
+	ReturnID   uuid.UUID
+	OrderID    uuid.UUID
+	CustomerID uuid.UUID
+}
+
+func (e OrderReturnApproved) Type() string {
+	return "OrderReturnApproved"
+}
+
+type OrderReturnRejected struct {
+	ReturnID   uuid.UUID
+	OrderID    uuid.UUID
+	CustomerID uuid.UUID
+	Reason     string
+}
+
+func (e OrderReturnRejected) Type() string {
+	return "OrderReturnRejected"
+}
+
+type OrderReturnCompleted struct {
+	ReturnID     uuid.UUID
+	OrderID      uuid.UUID
+	CustomerID   uuid.UUID
+	RefundAmount float64
+}
+
+func (e OrderReturnCompleted) Type() string {
+	return "OrderReturnCompleted"
+}
+
+// ScheduledOrderFailed is dispatched when a run of the order schedule does not produce a paid order
+type ScheduledOrderFailed struct {
+	TemplateID uuid.UUID
+	CustomerID uuid.UUID
+	// OrderID is nil when the order was not created
+	OrderID *uuid.UUID
+	Reason  string
+}
+
+func (e ScheduledOrderFailed) Type() string {
+	return "ScheduledOrderFailed"
+}
@@ -0,0 +1,44 @@
+package model
+
+// Events of other services consumed by order service
+
+type UserDeleted struct {
+	UserID    string `json:"user_id"`
+	Status    int    `json:"status"`
+	DeletedAt int64  `json:"deleted_at"`
+	Hard      bool   `json:"hard"`
+}
+
+func (u UserDeleted) Type() string {
+	return "user_deleted"
+}
+
+type ProductRemoved struct {
+	ProductID string `json:"product_id"`
+}
+
+func (e ProductRemoved) Type() string {
+	return "ProductRemoved"
+}
+
+// PaymentStatus mirrors payment statuses of payment service
+type PaymentStatus int
+
+const (
+	PaymentPending PaymentStatus = iota
+	PaymentProcessing
+	PaymentSucceeded
+	PaymentFailed
+	PaymentCancelled
+	PaymentRefunded
+)
+
+type PaymentStatusChanged struct {
+	PaymentID string        `json:"payment_id"`
+	OrderID   string        `json:"order_id"`
+	Status    PaymentStatus `json:"status"`
+}
+
+func (e PaymentStatusChanged) Type() string {
+	return "PaymentStatusChanged"
+}
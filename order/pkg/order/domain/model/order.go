@@ -7,7 +7,11 @@ import (
 	"github.com/google/uuid"
 )
 
-var ErrOrderNotFound = errors.New("order not found")
+var (
+	ErrOrderNotFound = errors.New("order not found")
+	// ErrOrderVersionConflict means the order was changed concurrently since it was found
+	ErrOrderVersionConflict = errors.New("order version conflict")
+)
 
 type OrderStatus int
 
@@ -16,6 +20,14 @@ const (
 	Pending
 	Paid
 	Cancelled
+	Processing
+	Shipped
+	Delivered
+	ReturnRequested
+	Returned
+	Refunded
+	// PartiallyReturned means some items of the order are returned
+	PartiallyReturned
 )
 
 type Order struct {
@@ -26,15 +38,67 @@ type Order struct {
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
 	DeletedAt  *time.Time
+	// PromotionID is set by applied promo code, Discount is taken off the total charged by CreateOrderSaga
+	PromotionID *uuid.UUID
+	Discount    float64
+	// PromotionRedeemed is set when CreateOrderSaga counts the redemption, the promo code can not be changed after it
+	PromotionRedeemed bool
+	// AllowPartial lets CreateOrderSaga pay for reserved items only, items out of stock are moved to UnfulfilledItems
+	AllowPartial bool
+	// SplitBackorder makes CreateOrderSaga place UnfulfilledItems into a backorder once the order is paid
+	SplitBackorder   bool
+	UnfulfilledItems []UnfulfilledItem
+	// HoldPayment makes CreateOrderSaga hold the payment on the wallet until it is captured instead of charging at once
+	HoldPayment bool
+	// ParentOrderID is set for a backorder, BackorderID is set for the order it is split from
+	ParentOrderID *uuid.UUID
+	BackorderID   *uuid.UUID
+	// ShippingAddress is a snapshot of the customer address, it is nil for Pickup
+	ShippingAddress *ShippingAddress
+	DeliveryMethod  DeliveryMethod
+	// DeliveryFee is added to the total charged by CreateOrderSaga
+	DeliveryFee float64
+	// PaymentID is set by CreateOrderSaga once the payment of the order is created
+	PaymentID *uuid.UUID
+	// Version is zero for a new order and is incremented by every Store
+	Version int
+}
+
+type DeliveryMethod int
+
+const (
+	Pickup DeliveryMethod = iota
+	Courier
+	Post
+)
+
+type ShippingAddress struct {
+	Recipient  string
+	Phone      string
+	Country    string
+	City       string
+	Street     string
+	PostalCode string
 }
 
 type OrderItem struct {
-	OrderID    uuid.UUID
-	ProductID  uuid.UUID
-	Count      int
+	OrderID   uuid.UUID
+	ProductID uuid.UUID
+	Count     int
+	// Price is a snapshot of the product price at the moment the item was added
+	Price      float64
 	TotalPrice float64
 }
 
+// UnfulfilledItem is a part of the order item which could not be reserved
+type UnfulfilledItem struct {
+	ProductID uuid.UUID
+	Count     int
+	// Price is the price snapshot of the order item
+	Price  float64
+	Reason string
+}
+
 type OrderRepository interface {
 	NextID() (uuid.UUID, error)
 	Store(order *Order) error
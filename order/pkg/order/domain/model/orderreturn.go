@@ -0,0 +1,49 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrOrderReturnNotFound = errors.New("order return not found")
+
+type OrderReturnStatus int
+
+const (
+	ReturnStatusRequested OrderReturnStatus = iota
+	ReturnStatusApproved
+	ReturnStatusRejected
+	// ReturnStatusCompleted means products are restocked and the order status is set, the refund is paid next
+	ReturnStatusCompleted
+)
+
+type OrderReturn struct {
+	ID         uuid.UUID
+	OrderID    uuid.UUID
+	CustomerID uuid.UUID
+	Status     OrderReturnStatus
+	Items      []OrderReturnItem
+	Reason     string
+	// RejectionReason is set when the return is rejected
+	RejectionReason string
+	// RefundAmount is the returned items total with the order discount taken off proportionally
+	RefundAmount float64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type OrderReturnItem struct {
+	ProductID uuid.UUID
+	Count     int
+	// Price is the price snapshot of the order item
+	Price float64
+}
+
+type OrderReturnRepository interface {
+	NextID() (uuid.UUID, error)
+	Store(orderReturn *OrderReturn) error
+	Find(id uuid.UUID) (*OrderReturn, error)
+	FindByOrderID(orderID uuid.UUID) ([]OrderReturn, error)
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrOrderTemplateNotFound = errors.New("order template not found")
+
+// OrderTemplate is a basket the customer reorders by its schedule
+type OrderTemplate struct {
+	ID         uuid.UUID
+	CustomerID uuid.UUID
+	Items      []OrderTemplateItem
+	// Schedule is nil until the template is scheduled
+	Schedule  *OrderSchedule
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type OrderTemplateItem struct {
+	ProductID uuid.UUID
+	Count     int
+}
+
+// OrderSchedule has either CronExpression or Interval set
+type OrderSchedule struct {
+	CronExpression string
+	Interval       time.Duration
+	Paused         bool
+}
+
+type OrderTemplateRepository interface {
+	NextID() (uuid.UUID, error)
+	Store(template *OrderTemplate) error
+	Find(id uuid.UUID) (*OrderTemplate, error)
+}
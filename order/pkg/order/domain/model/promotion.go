@@ -0,0 +1,75 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrPromotionNotFound = errors.New("promotion not found")
+
+type PromotionKind int
+
+const (
+	// PercentageDiscount takes Value percent off the order total
+	PercentageDiscount PromotionKind = iota
+	// FixedAmountDiscount takes Value off the order total
+	FixedAmountDiscount
+)
+
+type Promotion struct {
+	ID            uuid.UUID
+	Code          string
+	Kind          PromotionKind
+	Value         float64
+	MinOrderTotal float64
+	// PerCustomerLimit is how many orders of a customer may redeem the promotion, zero means unlimited
+	PerCustomerLimit int
+	// ValidFrom and ValidTo bound the validity window, nil means unbounded
+	ValidFrom *time.Time
+	ValidTo   *time.Time
+	CreatedAt time.Time
+}
+
+// Active reports whether now is within the validity window
+func (p *Promotion) Active(now time.Time) bool {
+	if p.ValidFrom != nil && now.Before(*p.ValidFrom) {
+		return false
+	}
+	if p.ValidTo != nil && !now.Before(*p.ValidTo) {
+		return false
+	}
+	return true
+}
+
+// Discount returns the amount taken off the order total, it never exceeds the total
+func (p *Promotion) Discount(orderTotal float64) float64 {
+	var discount float64
+	switch p.Kind {
+	case PercentageDiscount:
+		discount = math.Round(orderTotal*p.Value) / 100
+	case FixedAmountDiscount:
+		discount = p.Value
+	}
+	return min(discount, orderTotal)
+}
+
+// PromotionRedemption is a use of the promotion by an order, it is counted against PerCustomerLimit
+type PromotionRedemption struct {
+	PromotionID uuid.UUID
+	OrderID     uuid.UUID
+	CustomerID  uuid.UUID
+	CreatedAt   time.Time
+}
+
+type PromotionRepository interface {
+	NextID() (uuid.UUID, error)
+	Store(promotion *Promotion) error
+	Find(id uuid.UUID) (*Promotion, error)
+	FindByCode(code string) (*Promotion, error)
+	AddRedemption(redemption PromotionRedemption) error
+	RemoveRedemption(promotionID, orderID uuid.UUID) error
+	CountRedemptions(promotionID, customerID uuid.UUID) (int, error)
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"order/pkg/order/domain/model"
+)
+
+type CartService interface {
+	// FindCart returns ErrCartNotFound when the customer has no cart or it is expired
+	FindCart(customerID uuid.UUID) (*model.Cart, error)
+	// AddItem merges the product into existing line of the cart
+	AddItem(customerID, productID uuid.UUID, count int) error
+	RemoveItem(customerID, productID uuid.UUID) error
+	// SetItemQuantity replaces count of the product, zero count removes it
+	SetItemQuantity(customerID, productID uuid.UUID, count int) error
+}
+
+func NewCartService(repo model.CartRepository, cartTTL time.Duration) CartService {
+	return &cartService{
+		repo:    repo,
+		cartTTL: cartTTL,
+	}
+}
+
+type cartService struct {
+	repo    model.CartRepository
+	cartTTL time.Duration
+}
+
+func (c cartService) FindCart(customerID uuid.UUID) (*model.Cart, error) {
+	cart, err := c.repo.FindByCustomerID(customerID)
+	if err != nil {
+		return nil, err
+	}
+	if cart.Expired(c.cartTTL, time.Now()) {
+		return nil, model.ErrCartNotFound
+	}
+	return cart, nil
+}
+
+func (c cartService) AddItem(customerID, productID uuid.UUID, count int) error {
+	if count <= 0 {
+		return ErrInvalidItemCount
+	}
+
+	cart, err := c.findOrCreateCart(customerID)
+	if err != nil {
+		return err
+	}
+
+	for i, item := range cart.Items {
+		if item.ProductID == productID {
+			cart.Items[i].Count += count
+			return c.store(cart)
+		}
+	}
+	cart.Items = append(cart.Items, model.CartItem{
+		ProductID: productID,
+		Count:     count,
+	})
+	return c.store(cart)
+}
+
+func (c cartService) RemoveItem(customerID, productID uuid.UUID) error {
+	cart, err := c.FindCart(customerID)
+	if err != nil {
+		if errors.Is(err, model.ErrCartNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	var newItems []model.CartItem
+	for _, item := range cart.Items {
+		if item.ProductID != productID {
+			newItems = append(newItems, item)
+		}
+	}
+	if len(newItems) == len(cart.Items) {
+		return nil
+	}
+
+	cart.Items = newItems
+	return c.store(cart)
+}
+
+func (c cartService) SetItemQuantity(customerID, productID uuid.UUID, count int) error {
+	if count < 0 {
+		return ErrInvalidItemCount
+	}
+	if count == 0 {
+		return c.RemoveItem(customerID, productID)
+	}
+
+	cart, err := c.findOrCreateCart(customerID)
+	if err != nil {
+		return err
+	}
+
+	for i, item := range cart.Items {
+		if item.ProductID == productID {
+			if item.Count == count {
+				return nil
+			}
+			cart.Items[i].Count = count
+			return c.store(cart)
+		}
+	}
+	cart.Items = append(cart.Items, model.CartItem{
+		ProductID: productID,
+		Count:     count,
+	})
+	return c.store(cart)
+}
+
+// findOrCreateCart returns existing cart of the customer, expired cart is reused as an empty one
+func (c cartService) findOrCreateCart(customerID uuid.UUID) (*model.Cart, error) {
+	now := time.Now()
+	cart, err := c.repo.FindByCustomerID(customerID)
+	if err == nil {
+		if cart.Expired(c.cartTTL, now) {
+			cart.Items = nil
+			cart.CreatedAt = now
+		}
+		return cart, nil
+	}
+	if !errors.Is(err, model.ErrCartNotFound) {
+		return nil, err
+	}
+
+	cartID, err := c.repo.NextID()
+	if err != nil {
+		return nil, err
+	}
+	return &model.Cart{
+		ID:         cartID,
+		CustomerID: customerID,
+		CreatedAt:  now,
+	}, nil
+}
+
+func (c cartService) store(cart *model.Cart) error {
+	cart.UpdatedAt = time.Now()
+	return c.repo.Store(cart)
+}
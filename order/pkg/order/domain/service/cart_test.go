@@ -0,0 +1,230 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"order/pkg/order/domain/model"
+)
+
+const testCartTTL = time.Hour
+
+type MockCartRepository struct {
+	mock.Mock
+}
+
+func (m *MockCartRepository) NextID() (uuid.UUID, error) {
+	args := m.Called()
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockCartRepository) Store(cart *model.Cart) error {
+	args := m.Called(cart)
+	return args.Error(0)
+}
+
+func (m *MockCartRepository) FindByCustomerID(customerID uuid.UUID) (*model.Cart, error) {
+	args := m.Called(customerID)
+	if cart, ok := args.Get(0).(*model.Cart); ok {
+		return cart, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCartRepository) Remove(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockCartRepository) RemoveInactive(updatedBefore time.Time) error {
+	args := m.Called(updatedBefore)
+	return args.Error(0)
+}
+
+func newCart(customerID uuid.UUID, items ...model.CartItem) *model.Cart {
+	now := time.Now()
+	return &model.Cart{
+		ID:         uuid.New(),
+		CustomerID: customerID,
+		Items:      items,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		Version:    1,
+	}
+}
+
+func TestCartAddItem_CreatesCart(t *testing.T) {
+	cartRepo := new(MockCartRepository)
+
+	customerID := uuid.New()
+	productID := uuid.New()
+	cartID := uuid.New()
+
+	cartRepo.On("FindByCustomerID", customerID).Return(nil, model.ErrCartNotFound)
+	cartRepo.On("NextID").Return(cartID, nil)
+	cartRepo.On("Store", mock.MatchedBy(func(c *model.Cart) bool {
+		return c.ID == cartID &&
+			c.CustomerID == customerID &&
+			c.Version == 0 &&
+			len(c.Items) == 1 &&
+			c.Items[0].ProductID == productID &&
+			c.Items[0].Count == 2
+	})).Return(nil)
+
+	svc := NewCartService(cartRepo, testCartTTL)
+
+	err := svc.AddItem(customerID, productID, 2)
+	assert.NoError(t, err)
+	cartRepo.AssertExpectations(t)
+}
+
+func TestCartAddItem_MergesSameProduct(t *testing.T) {
+	cartRepo := new(MockCartRepository)
+
+	customerID := uuid.New()
+	productID := uuid.New()
+	cart := newCart(customerID, model.CartItem{ProductID: productID, Count: 2})
+
+	cartRepo.On("FindByCustomerID", customerID).Return(cart, nil)
+	cartRepo.On("Store", mock.MatchedBy(func(c *model.Cart) bool {
+		return len(c.Items) == 1 && c.Items[0].ProductID == productID && c.Items[0].Count == 5
+	})).Return(nil)
+
+	svc := NewCartService(cartRepo, testCartTTL)
+
+	err := svc.AddItem(customerID, productID, 3)
+	assert.NoError(t, err)
+	cartRepo.AssertExpectations(t)
+}
+
+func TestCartAddItem_InvalidCount(t *testing.T) {
+	cartRepo := new(MockCartRepository)
+
+	svc := NewCartService(cartRepo, testCartTTL)
+
+	err := svc.AddItem(uuid.New(), uuid.New(), 0)
+	assert.ErrorIs(t, err, ErrInvalidItemCount)
+	cartRepo.AssertNotCalled(t, "FindByCustomerID", mock.Anything)
+}
+
+func TestCartAddItem_ExpiredCartIsEmptied(t *testing.T) {
+	cartRepo := new(MockCartRepository)
+
+	customerID := uuid.New()
+	oldProductID := uuid.New()
+	productID := uuid.New()
+	cart := newCart(customerID, model.CartItem{ProductID: oldProductID, Count: 1})
+	cart.UpdatedAt = time.Now().Add(-2 * testCartTTL)
+
+	cartRepo.On("FindByCustomerID", customerID).Return(cart, nil)
+	cartRepo.On("Store", mock.MatchedBy(func(c *model.Cart) bool {
+		return len(c.Items) == 1 && c.Items[0].ProductID == productID && c.Items[0].Count == 1
+	})).Return(nil)
+
+	svc := NewCartService(cartRepo, testCartTTL)
+
+	err := svc.AddItem(customerID, productID, 1)
+	assert.NoError(t, err)
+	cartRepo.AssertExpectations(t)
+}
+
+func TestCartFindCart_Expired(t *testing.T) {
+	cartRepo := new(MockCartRepository)
+
+	customerID := uuid.New()
+	cart := newCart(customerID, model.CartItem{ProductID: uuid.New(), Count: 1})
+	cart.UpdatedAt = time.Now().Add(-2 * testCartTTL)
+	cartRepo.On("FindByCustomerID", customerID).Return(cart, nil)
+
+	svc := NewCartService(cartRepo, testCartTTL)
+
+	_, err := svc.FindCart(customerID)
+	assert.ErrorIs(t, err, model.ErrCartNotFound)
+}
+
+func TestCartRemoveItem_Success(t *testing.T) {
+	cartRepo := new(MockCartRepository)
+
+	customerID := uuid.New()
+	productID := uuid.New()
+	otherProductID := uuid.New()
+	cart := newCart(customerID,
+		model.CartItem{ProductID: productID, Count: 1},
+		model.CartItem{ProductID: otherProductID, Count: 2},
+	)
+
+	cartRepo.On("FindByCustomerID", customerID).Return(cart, nil)
+	cartRepo.On("Store", mock.MatchedBy(func(c *model.Cart) bool {
+		return len(c.Items) == 1 && c.Items[0].ProductID == otherProductID
+	})).Return(nil)
+
+	svc := NewCartService(cartRepo, testCartTTL)
+
+	err := svc.RemoveItem(customerID, productID)
+	assert.NoError(t, err)
+	cartRepo.AssertExpectations(t)
+}
+
+func TestCartRemoveItem_CartNotFound_Idempotent(t *testing.T) {
+	cartRepo := new(MockCartRepository)
+
+	customerID := uuid.New()
+	cartRepo.On("FindByCustomerID", customerID).Return(nil, model.ErrCartNotFound)
+
+	svc := NewCartService(cartRepo, testCartTTL)
+
+	err := svc.RemoveItem(customerID, uuid.New())
+	assert.NoError(t, err)
+	cartRepo.AssertNotCalled(t, "Store", mock.Anything)
+}
+
+func TestCartSetItemQuantity_ReplacesCount(t *testing.T) {
+	cartRepo := new(MockCartRepository)
+
+	customerID := uuid.New()
+	productID := uuid.New()
+	cart := newCart(customerID, model.CartItem{ProductID: productID, Count: 2})
+
+	cartRepo.On("FindByCustomerID", customerID).Return(cart, nil)
+	cartRepo.On("Store", mock.MatchedBy(func(c *model.Cart) bool {
+		return len(c.Items) == 1 && c.Items[0].Count == 7
+	})).Return(nil)
+
+	svc := NewCartService(cartRepo, testCartTTL)
+
+	err := svc.SetItemQuantity(customerID, productID, 7)
+	assert.NoError(t, err)
+	cartRepo.AssertExpectations(t)
+}
+
+func TestCartSetItemQuantity_ZeroRemovesItem(t *testing.T) {
+	cartRepo := new(MockCartRepository)
+
+	customerID := uuid.New()
+	productID := uuid.New()
+	cart := newCart(customerID, model.CartItem{ProductID: productID, Count: 2})
+
+	cartRepo.On("FindByCustomerID", customerID).Return(cart, nil)
+	cartRepo.On("Store", mock.MatchedBy(func(c *model.Cart) bool {
+		return len(c.Items) == 0
+	})).Return(nil)
+
+	svc := NewCartService(cartRepo, testCartTTL)
+
+	err := svc.SetItemQuantity(customerID, productID, 0)
+	assert.NoError(t, err)
+	cartRepo.AssertExpectations(t)
+}
+
+func TestCartSetItemQuantity_NegativeCount(t *testing.T) {
+	cartRepo := new(MockCartRepository)
+
+	svc := NewCartService(cartRepo, testCartTTL)
+
+	err := svc.SetItemQuantity(uuid.New(), uuid.New(), -1)
+	assert.ErrorIs(t, err, ErrInvalidItemCount)
+}
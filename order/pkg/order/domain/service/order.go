@@ -2,6 +2,7 @@ package service
 
 import (
 	"errors"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,14 +13,34 @@ import (
 
 var (
 	ErrInvalidOrderStatus = errors.New("invalid order status")
+	ErrInvalidItemCount   = errors.New("invalid item count")
+	// ErrPartialFulfilmentNotAllowed means the order has to be fulfilled completely or cancelled
+	ErrPartialFulfilmentNotAllowed = errors.New("partial fulfilment is not allowed")
+	ErrNoUnfulfilledItems          = errors.New("order has no unfulfilled items")
+	ErrInvalidDeliveryMethod       = errors.New("invalid delivery method")
+	ErrShippingAddressRequired     = errors.New("shipping address is required for delivery")
+	// ErrOrderPaymentExists means another payment is set for the order already
+	ErrOrderPaymentExists = errors.New("order has another payment")
 )
 
 type OrderService interface {
 	CreateOrder(customerID uuid.UUID) (uuid.UUID, error)
 	RemoveOrder(orderID uuid.UUID) error
 	SetStatus(orderID uuid.UUID, status model.OrderStatus) error
-	AddItem(orderID, productID uuid.UUID, price float64) error
+	AddItem(orderID, productID uuid.UUID, count int, price float64) error
 	RemoveItem(orderID, itemID uuid.UUID) error
+	// AllowPartialFulfilment is set while the order is Open, splitBackorder requests a backorder for unfulfilled items
+	AllowPartialFulfilment(orderID uuid.UUID, splitBackorder bool) error
+	// HoldPayment is set while the order is Open, the payment of the order is held until it is captured
+	HoldPayment(orderID uuid.UUID) error
+	// MarkUnfulfilled takes items which could not be reserved off the Pending order, repeated call does nothing
+	MarkUnfulfilled(orderID uuid.UUID, items []model.UnfulfilledItem) error
+	// CreateBackorder places unfulfilled items of the paid order into a new Open order, repeated call returns the same one
+	CreateBackorder(orderID uuid.UUID) (uuid.UUID, error)
+	// SetDelivery is set while the order is Open, every method except Pickup requires the shipping address
+	SetDelivery(orderID uuid.UUID, method model.DeliveryMethod, address *model.ShippingAddress, fee float64) error
+	// SetPayment sets the payment created for the Pending order, repeated call with the same payment does nothing
+	SetPayment(orderID, paymentID uuid.UUID) error
 }
 
 func NewOrderService(repo model.OrderRepository, dispatcher commonevent.Dispatcher) OrderService {
@@ -87,6 +108,9 @@ func (o orderService) SetStatus(orderID uuid.UUID, status model.OrderStatus) err
 	}
 
 	oldStatus := order.Status
+	if oldStatus == status {
+		return nil
+	}
 
 	if !o.isValidStatusTransition(order.Status, status) {
 		return ErrInvalidOrderStatus
@@ -100,13 +124,18 @@ func (o orderService) SetStatus(orderID uuid.UUID, status model.OrderStatus) err
 	}
 
 	return o.dispatcher.Dispatch(model.OrderStatusChanged{
-		OrderID: orderID,
-		From:    oldStatus,
-		To:      status,
+		OrderID:    orderID,
+		CustomerID: order.CustomerID,
+		From:       oldStatus,
+		To:         status,
 	})
 }
 
-func (o orderService) AddItem(orderID, productID uuid.UUID, price float64) error {
+func (o orderService) AddItem(orderID, productID uuid.UUID, count int, price float64) error {
+	if count <= 0 {
+		return ErrInvalidItemCount
+	}
+
 	order, err := o.repo.Find(orderID)
 	if err != nil {
 		return err
@@ -116,11 +145,23 @@ func (o orderService) AddItem(orderID, productID uuid.UUID, price float64) error
 		return ErrInvalidOrderStatus
 	}
 
-	order.Items = append(order.Items, model.OrderItem{
-		OrderID:    orderID,
-		ProductID:  productID,
-		TotalPrice: price,
+	// Order keeps one line per product, so the count of a product added again is summed up
+	i := slices.IndexFunc(order.Items, func(item model.OrderItem) bool {
+		return item.ProductID == productID
 	})
+	if i >= 0 {
+		order.Items[i].Count += count
+		order.Items[i].Price = price
+		order.Items[i].TotalPrice = price * float64(order.Items[i].Count)
+	} else {
+		order.Items = append(order.Items, model.OrderItem{
+			OrderID:    orderID,
+			ProductID:  productID,
+			Count:      count,
+			Price:      price,
+			TotalPrice: price * float64(count),
+		})
+	}
 
 	err = o.repo.Store(order)
 	if err != nil {
@@ -170,15 +211,216 @@ func (o orderService) RemoveItem(orderID, itemID uuid.UUID) error {
 	})
 }
 
-func (o orderService) isValidStatusTransition(from, to model.OrderStatus) bool {
-	switch from {
-	case model.Open:
-		return to == model.Open || to == model.Pending || to == model.Paid || to == model.Cancelled
-	case model.Pending:
-		return to == model.Paid || to == model.Cancelled
-	case model.Paid, model.Cancelled:
-		return false
-	default:
-		return false
+func (o orderService) AllowPartialFulfilment(orderID uuid.UUID, splitBackorder bool) error {
+	order, err := o.repo.Find(orderID)
+	if err != nil {
+		return err
+	}
+
+	if order.Status != model.Open {
+		return ErrInvalidOrderStatus
+	}
+
+	order.AllowPartial = true
+	order.SplitBackorder = splitBackorder
+	order.UpdatedAt = time.Now()
+	return o.repo.Store(order)
+}
+
+func (o orderService) HoldPayment(orderID uuid.UUID) error {
+	order, err := o.repo.Find(orderID)
+	if err != nil {
+		return err
+	}
+
+	if order.Status != model.Open {
+		return ErrInvalidOrderStatus
+	}
+
+	order.HoldPayment = true
+	order.UpdatedAt = time.Now()
+	return o.repo.Store(order)
+}
+
+func (o orderService) MarkUnfulfilled(orderID uuid.UUID, items []model.UnfulfilledItem) error {
+	order, err := o.repo.Find(orderID)
+	if err != nil {
+		return err
+	}
+
+	if !order.AllowPartial {
+		return ErrPartialFulfilmentNotAllowed
+	}
+	if len(order.UnfulfilledItems) > 0 || len(items) == 0 {
+		return nil
+	}
+	if order.Status != model.Pending {
+		return ErrInvalidOrderStatus
+	}
+
+	unfulfilledItems := make([]model.UnfulfilledItem, 0, len(items))
+	for _, unfulfilled := range items {
+		i := slices.IndexFunc(order.Items, func(item model.OrderItem) bool {
+			return item.ProductID == unfulfilled.ProductID
+		})
+		if i < 0 || unfulfilled.Count <= 0 || unfulfilled.Count > order.Items[i].Count {
+			return ErrInvalidItemCount
+		}
+
+		item := &order.Items[i]
+		item.Count -= unfulfilled.Count
+		item.TotalPrice = item.Price * float64(item.Count)
+		unfulfilled.Price = item.Price
+		unfulfilledItems = append(unfulfilledItems, unfulfilled)
 	}
+	order.Items = slices.DeleteFunc(order.Items, func(item model.OrderItem) bool {
+		return item.Count == 0
+	})
+	order.UnfulfilledItems = unfulfilledItems
+	order.UpdatedAt = time.Now()
+
+	if err = o.repo.Store(order); err != nil {
+		return err
+	}
+
+	return o.dispatcher.Dispatch(model.OrderItemsUnfulfilled{
+		OrderID: orderID,
+		Items:   unfulfilledItems,
+	})
+}
+
+func (o orderService) CreateBackorder(orderID uuid.UUID) (uuid.UUID, error) {
+	order, err := o.repo.Find(orderID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if order.BackorderID != nil {
+		return *order.BackorderID, nil
+	}
+	if !order.SplitBackorder {
+		return uuid.Nil, ErrPartialFulfilmentNotAllowed
+	}
+	if len(order.UnfulfilledItems) == 0 {
+		return uuid.Nil, ErrNoUnfulfilledItems
+	}
+	if order.Status != model.Paid {
+		return uuid.Nil, ErrInvalidOrderStatus
+	}
+
+	backorderID, err := o.repo.NextID()
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	now := time.Now()
+	backorder := &model.Order{
+		ID:             backorderID,
+		CustomerID:     order.CustomerID,
+		Status:         model.Open,
+		Items:          make([]model.OrderItem, len(order.UnfulfilledItems)),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		AllowPartial:   order.AllowPartial,
+		SplitBackorder: order.SplitBackorder,
+		HoldPayment:    order.HoldPayment,
+		ParentOrderID:  &order.ID,
+		// Delivery fee is charged once by the order the backorder is split from
+		ShippingAddress: order.ShippingAddress,
+		DeliveryMethod:  order.DeliveryMethod,
+	}
+	for i, item := range order.UnfulfilledItems {
+		backorder.Items[i] = model.OrderItem{
+			OrderID:    backorderID,
+			ProductID:  item.ProductID,
+			Count:      item.Count,
+			Price:      item.Price,
+			TotalPrice: item.Price * float64(item.Count),
+		}
+	}
+	if err = o.repo.Store(backorder); err != nil {
+		return uuid.Nil, err
+	}
+
+	order.BackorderID = &backorderID
+	order.UpdatedAt = now
+	if err = o.repo.Store(order); err != nil {
+		return uuid.Nil, err
+	}
+
+	err = o.dispatcher.Dispatch(model.OrderCreated{
+		OrderID:    backorderID,
+		CustomerID: order.CustomerID,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return backorderID, o.dispatcher.Dispatch(model.OrderBackorderCreated{
+		OrderID:     orderID,
+		BackorderID: backorderID,
+	})
+}
+
+func (o orderService) SetDelivery(orderID uuid.UUID, method model.DeliveryMethod, address *model.ShippingAddress, fee float64) error {
+	if !slices.Contains(deliveryMethods, method) {
+		return ErrInvalidDeliveryMethod
+	}
+	if method != model.Pickup && address == nil {
+		return ErrShippingAddressRequired
+	}
+
+	order, err := o.repo.Find(orderID)
+	if err != nil {
+		return err
+	}
+
+	if order.Status != model.Open {
+		return ErrInvalidOrderStatus
+	}
+
+	order.DeliveryMethod = method
+	order.ShippingAddress = address
+	order.DeliveryFee = fee
+	order.UpdatedAt = time.Now()
+	return o.repo.Store(order)
+}
+
+var deliveryMethods = []model.DeliveryMethod{model.Pickup, model.Courier, model.Post}
+
+func (o orderService) SetPayment(orderID, paymentID uuid.UUID) error {
+	order, err := o.repo.Find(orderID)
+	if err != nil {
+		return err
+	}
+
+	if order.PaymentID != nil {
+		if *order.PaymentID == paymentID {
+			return nil
+		}
+		return ErrOrderPaymentExists
+	}
+	if order.Status != model.Pending {
+		return ErrInvalidOrderStatus
+	}
+
+	order.PaymentID = &paymentID
+	order.UpdatedAt = time.Now()
+	return o.repo.Store(order)
+}
+
+func (o orderService) isValidStatusTransition(from, to model.OrderStatus) bool {
+	return slices.Contains(statusTransitions[from], to)
+}
+
+// statusTransitions lists allowed next statuses for every order status, statuses missing here are terminal
+var statusTransitions = map[model.OrderStatus][]model.OrderStatus{
+	model.Open:              {model.Pending, model.Cancelled},
+	model.Pending:           {model.Paid, model.Cancelled},
+	model.Paid:              {model.Processing, model.Refunded, model.PartiallyReturned, model.Returned},
+	model.Processing:        {model.Shipped, model.Refunded, model.PartiallyReturned, model.Returned},
+	model.Shipped:           {model.Delivered, model.PartiallyReturned, model.Returned},
+	model.Delivered:         {model.ReturnRequested, model.PartiallyReturned, model.Returned},
+	model.ReturnRequested:   {model.Returned, model.Delivered},
+	model.Returned:          {model.Refunded},
+	model.PartiallyReturned: {model.Returned},
 }
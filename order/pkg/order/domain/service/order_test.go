@@ -245,6 +245,24 @@ func TestSetStatus_OrderNotFound(t *testing.T) {
 	assert.ErrorIs(t, err, model.ErrOrderNotFound)
 }
 
+func TestSetStatus_SameStatus_Idempotent(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	orderID := uuid.New()
+	customerID := uuid.New()
+	order := newPendingOrder(orderID, customerID)
+
+	orderRepo.On("Find", orderID).Return(order, nil)
+
+	svc := NewOrderService(orderRepo, eventDisp)
+
+	err := svc.SetStatus(orderID, model.Pending)
+	assert.NoError(t, err)
+	orderRepo.AssertNotCalled(t, "Store", mock.Anything)
+	eventDisp.AssertNotCalled(t, "Dispatch", mock.Anything)
+}
+
 func TestAddItem_Success(t *testing.T) {
 	orderRepo := new(MockOrderRepository)
 	eventDisp := new(MockEventDispatcher)
@@ -253,6 +271,7 @@ func TestAddItem_Success(t *testing.T) {
 	customerID := uuid.New()
 	productID := uuid.New()
 	price := 19.99
+	count := 3
 
 	order := newOpenOrder(orderID, customerID)
 
@@ -262,7 +281,11 @@ func TestAddItem_Success(t *testing.T) {
 			return false
 		}
 		item := o.Items[0]
-		return item.ProductID == productID && item.TotalPrice == price && item.OrderID == orderID
+		return item.ProductID == productID &&
+			item.Count == count &&
+			item.Price == price &&
+			item.TotalPrice == price*float64(count) &&
+			item.OrderID == orderID
 	})).Return(nil)
 
 	eventDisp.On("Dispatch", mock.MatchedBy(func(e model.OrderItemsChanged) bool {
@@ -271,8 +294,45 @@ func TestAddItem_Success(t *testing.T) {
 
 	svc := NewOrderService(orderRepo, eventDisp)
 
-	err := svc.AddItem(orderID, productID, price)
+	err := svc.AddItem(orderID, productID, count, price)
+	assert.NoError(t, err)
+}
+
+func TestAddItem_SameProduct(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	orderID := uuid.New()
+	productID := uuid.New()
+
+	order := newOpenOrder(orderID, uuid.New())
+	order.Items = []model.OrderItem{{OrderID: orderID, ProductID: productID, Count: 2, Price: 10, TotalPrice: 20}}
+
+	orderRepo.On("Find", orderID).Return(order, nil)
+	orderRepo.On("Store", mock.MatchedBy(func(o *model.Order) bool {
+		return len(o.Items) == 1 &&
+			o.Items[0].ProductID == productID &&
+			o.Items[0].Count == 5 &&
+			o.Items[0].TotalPrice == 50
+	})).Return(nil)
+	eventDisp.On("Dispatch", mock.Anything).Return(nil)
+
+	svc := NewOrderService(orderRepo, eventDisp)
+
+	err := svc.AddItem(orderID, productID, 3, 10)
 	assert.NoError(t, err)
+	orderRepo.AssertExpectations(t)
+}
+
+func TestAddItem_InvalidCount(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	svc := NewOrderService(orderRepo, eventDisp)
+
+	err := svc.AddItem(uuid.New(), uuid.New(), 0, 10.0)
+	assert.ErrorIs(t, err, ErrInvalidItemCount)
+	orderRepo.AssertNotCalled(t, "Find", mock.Anything)
 }
 
 func TestAddItem_OrderNotOpen(t *testing.T) {
@@ -288,7 +348,7 @@ func TestAddItem_OrderNotOpen(t *testing.T) {
 
 	svc := NewOrderService(orderRepo, eventDisp)
 
-	err := svc.AddItem(orderID, productID, 10.0)
+	err := svc.AddItem(orderID, productID, 1, 10.0)
 	assert.ErrorIs(t, err, ErrInvalidOrderStatus)
 }
 
@@ -302,7 +362,7 @@ func TestAddItem_OrderNotFound(t *testing.T) {
 
 	svc := NewOrderService(orderRepo, eventDisp)
 
-	err := svc.AddItem(orderID, productID, 10.0)
+	err := svc.AddItem(orderID, productID, 1, 10.0)
 	assert.ErrorIs(t, err, model.ErrOrderNotFound)
 }
 
@@ -399,7 +459,28 @@ func TestIsValidStatusTransition_Matrix(t *testing.T) {
 
 		{model.Paid, model.Cancelled, false, "Paid → Cancelled"},
 		{model.Paid, model.Open, false, "Paid → Open"},
+		{model.Paid, model.Processing, true, "Paid → Processing"},
+		{model.Paid, model.Refunded, true, "Paid → Refunded"},
+		{model.Paid, model.Shipped, false, "Paid → Shipped"},
 		{model.Cancelled, model.Paid, false, "Cancelled → Paid"},
+
+		{model.Processing, model.Shipped, true, "Processing → Shipped"},
+		{model.Processing, model.Refunded, true, "Processing → Refunded"},
+		{model.Processing, model.Delivered, false, "Processing → Delivered"},
+		{model.Shipped, model.Delivered, true, "Shipped → Delivered"},
+		{model.Shipped, model.Refunded, false, "Shipped → Refunded"},
+		{model.Delivered, model.ReturnRequested, true, "Delivered → ReturnRequested"},
+		{model.Delivered, model.Returned, true, "Delivered → Returned (return workflow)"},
+		{model.Paid, model.PartiallyReturned, true, "Paid → PartiallyReturned"},
+		{model.Shipped, model.Returned, true, "Shipped → Returned"},
+		{model.PartiallyReturned, model.Returned, true, "PartiallyReturned → Returned"},
+		{model.PartiallyReturned, model.Refunded, false, "PartiallyReturned → Refunded"},
+		{model.Returned, model.PartiallyReturned, false, "Returned → PartiallyReturned"},
+		{model.ReturnRequested, model.Returned, true, "ReturnRequested → Returned"},
+		{model.ReturnRequested, model.Delivered, true, "ReturnRequested → Delivered (rejected)"},
+		{model.Returned, model.Refunded, true, "Returned → Refunded"},
+		{model.Returned, model.Delivered, false, "Returned → Delivered"},
+		{model.Refunded, model.Processing, false, "Refunded → Processing"},
 	}
 
 	for _, tt := range tests {
@@ -408,3 +489,283 @@ func TestIsValidStatusTransition_Matrix(t *testing.T) {
 		})
 	}
 }
+
+func newPartialOrder(id uuid.UUID, status model.OrderStatus, items ...model.OrderItem) *model.Order {
+	order := newOpenOrder(id, uuid.New())
+	order.Status = status
+	order.Items = items
+	order.AllowPartial = true
+	order.SplitBackorder = true
+	return order
+}
+
+func TestAllowPartialFulfilment_NotOpen(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+
+	orderID := uuid.New()
+	orderRepo.On("Find", orderID).Return(newPendingOrder(orderID, uuid.New()), nil)
+
+	svc := NewOrderService(orderRepo, new(MockEventDispatcher))
+
+	err := svc.AllowPartialFulfilment(orderID, true)
+	assert.ErrorIs(t, err, ErrInvalidOrderStatus)
+	orderRepo.AssertNotCalled(t, "Store", mock.Anything)
+}
+
+func TestHoldPayment_NotOpen(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+
+	orderID := uuid.New()
+	orderRepo.On("Find", orderID).Return(newPendingOrder(orderID, uuid.New()), nil)
+
+	svc := NewOrderService(orderRepo, new(MockEventDispatcher))
+
+	err := svc.HoldPayment(orderID)
+	assert.ErrorIs(t, err, ErrInvalidOrderStatus)
+	orderRepo.AssertNotCalled(t, "Store", mock.Anything)
+}
+
+func TestMarkUnfulfilled_Success(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	orderID := uuid.New()
+	keptProduct := uuid.New()
+	outOfStockProduct := uuid.New()
+	order := newPartialOrder(orderID, model.Pending,
+		model.OrderItem{OrderID: orderID, ProductID: keptProduct, Count: 3, Price: 10, TotalPrice: 30},
+		model.OrderItem{OrderID: orderID, ProductID: outOfStockProduct, Count: 2, Price: 5, TotalPrice: 10},
+	)
+
+	orderRepo.On("Find", orderID).Return(order, nil)
+	orderRepo.On("Store", mock.MatchedBy(func(o *model.Order) bool {
+		return len(o.Items) == 1 &&
+			o.Items[0].ProductID == keptProduct &&
+			o.Items[0].Count == 2 &&
+			o.Items[0].TotalPrice == 20 &&
+			len(o.UnfulfilledItems) == 2 &&
+			o.UnfulfilledItems[1].ProductID == outOfStockProduct &&
+			o.UnfulfilledItems[1].Price == 5
+	})).Return(nil)
+	eventDisp.On("Dispatch", mock.AnythingOfType("model.OrderItemsUnfulfilled")).Return(nil)
+
+	svc := NewOrderService(orderRepo, eventDisp)
+
+	err := svc.MarkUnfulfilled(orderID, []model.UnfulfilledItem{
+		{ProductID: keptProduct, Count: 1, Reason: "out of stock"},
+		{ProductID: outOfStockProduct, Count: 2, Reason: "out of stock"},
+	})
+	assert.NoError(t, err)
+	orderRepo.AssertExpectations(t)
+	eventDisp.AssertExpectations(t)
+}
+
+func TestMarkUnfulfilled_NotAllowed(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+
+	orderID := uuid.New()
+	productID := uuid.New()
+	order := newPendingOrder(orderID, uuid.New())
+	order.Items = []model.OrderItem{{OrderID: orderID, ProductID: productID, Count: 1}}
+	orderRepo.On("Find", orderID).Return(order, nil)
+
+	svc := NewOrderService(orderRepo, new(MockEventDispatcher))
+
+	err := svc.MarkUnfulfilled(orderID, []model.UnfulfilledItem{{ProductID: productID, Count: 1}})
+	assert.ErrorIs(t, err, ErrPartialFulfilmentNotAllowed)
+}
+
+func TestMarkUnfulfilled_CountExceedsItem(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+
+	orderID := uuid.New()
+	productID := uuid.New()
+	order := newPartialOrder(orderID, model.Pending, model.OrderItem{OrderID: orderID, ProductID: productID, Count: 1})
+	orderRepo.On("Find", orderID).Return(order, nil)
+
+	svc := NewOrderService(orderRepo, new(MockEventDispatcher))
+
+	err := svc.MarkUnfulfilled(orderID, []model.UnfulfilledItem{{ProductID: productID, Count: 2}})
+	assert.ErrorIs(t, err, ErrInvalidItemCount)
+	orderRepo.AssertNotCalled(t, "Store", mock.Anything)
+}
+
+func TestMarkUnfulfilled_Repeated(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+
+	orderID := uuid.New()
+	productID := uuid.New()
+	order := newPartialOrder(orderID, model.Pending, model.OrderItem{OrderID: orderID, ProductID: productID, Count: 1})
+	order.UnfulfilledItems = []model.UnfulfilledItem{{ProductID: productID, Count: 1}}
+	orderRepo.On("Find", orderID).Return(order, nil)
+
+	svc := NewOrderService(orderRepo, new(MockEventDispatcher))
+
+	err := svc.MarkUnfulfilled(orderID, []model.UnfulfilledItem{{ProductID: productID, Count: 1}})
+	assert.NoError(t, err)
+	orderRepo.AssertNotCalled(t, "Store", mock.Anything)
+}
+
+func TestCreateBackorder_Success(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	orderID := uuid.New()
+	backorderID := uuid.New()
+	productID := uuid.New()
+	order := newPartialOrder(orderID, model.Paid)
+	order.UnfulfilledItems = []model.UnfulfilledItem{{ProductID: productID, Count: 2, Price: 5, Reason: "out of stock"}}
+
+	orderRepo.On("Find", orderID).Return(order, nil)
+	orderRepo.On("NextID").Return(backorderID, nil)
+	orderRepo.On("Store", mock.MatchedBy(func(o *model.Order) bool {
+		return o.ID == backorderID &&
+			o.Status == model.Open &&
+			o.ParentOrderID != nil && *o.ParentOrderID == orderID &&
+			len(o.Items) == 1 &&
+			o.Items[0].Count == 2 &&
+			o.Items[0].TotalPrice == 10
+	})).Return(nil)
+	orderRepo.On("Store", mock.MatchedBy(func(o *model.Order) bool {
+		return o.ID == orderID && o.BackorderID != nil && *o.BackorderID == backorderID
+	})).Return(nil)
+	eventDisp.On("Dispatch", mock.AnythingOfType("model.OrderCreated")).Return(nil)
+	eventDisp.On("Dispatch", mock.AnythingOfType("model.OrderBackorderCreated")).Return(nil)
+
+	svc := NewOrderService(orderRepo, eventDisp)
+
+	id, err := svc.CreateBackorder(orderID)
+	assert.NoError(t, err)
+	assert.Equal(t, backorderID, id)
+	orderRepo.AssertExpectations(t)
+	eventDisp.AssertExpectations(t)
+}
+
+func TestCreateBackorder_Repeated(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+
+	orderID := uuid.New()
+	backorderID := uuid.New()
+	order := newPartialOrder(orderID, model.Paid)
+	order.BackorderID = &backorderID
+	orderRepo.On("Find", orderID).Return(order, nil)
+
+	svc := NewOrderService(orderRepo, new(MockEventDispatcher))
+
+	id, err := svc.CreateBackorder(orderID)
+	assert.NoError(t, err)
+	assert.Equal(t, backorderID, id)
+	orderRepo.AssertNotCalled(t, "Store", mock.Anything)
+}
+
+func TestCreateBackorder_NoUnfulfilledItems(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+
+	orderID := uuid.New()
+	orderRepo.On("Find", orderID).Return(newPartialOrder(orderID, model.Paid), nil)
+
+	svc := NewOrderService(orderRepo, new(MockEventDispatcher))
+
+	_, err := svc.CreateBackorder(orderID)
+	assert.ErrorIs(t, err, ErrNoUnfulfilledItems)
+}
+
+func TestSetDelivery(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+
+	orderID := uuid.New()
+	address := &model.ShippingAddress{Country: "Russia", City: "Moscow", Street: "Tverskaya 1"}
+
+	orderRepo.On("Find", orderID).Return(newOpenOrder(orderID, uuid.New()), nil)
+	orderRepo.On("Store", mock.MatchedBy(func(o *model.Order) bool {
+		return o.ID == orderID &&
+			o.DeliveryMethod == model.Courier &&
+			o.ShippingAddress == address &&
+			o.DeliveryFee == 300
+	})).Return(nil)
+
+	svc := NewOrderService(orderRepo, new(MockEventDispatcher))
+
+	assert.NoError(t, svc.SetDelivery(orderID, model.Courier, address, 300))
+	orderRepo.AssertExpectations(t)
+}
+
+func TestSetDelivery_Invalid(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+
+	orderID := uuid.New()
+	address := &model.ShippingAddress{Country: "Russia", City: "Moscow", Street: "Tverskaya 1"}
+	orderRepo.On("Find", orderID).Return(newPendingOrder(orderID, uuid.New()), nil)
+
+	svc := NewOrderService(orderRepo, new(MockEventDispatcher))
+
+	assert.ErrorIs(t, svc.SetDelivery(orderID, model.Post, nil, 200), ErrShippingAddressRequired)
+	assert.ErrorIs(t, svc.SetDelivery(orderID, model.DeliveryMethod(42), address, 0), ErrInvalidDeliveryMethod)
+	assert.ErrorIs(t, svc.SetDelivery(orderID, model.Post, address, 200), ErrInvalidOrderStatus)
+	orderRepo.AssertNotCalled(t, "Store", mock.Anything)
+}
+
+func TestCreateBackorder_CopiesDeliveryWithoutFee(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	orderID := uuid.New()
+	backorderID := uuid.New()
+	order := newPartialOrder(orderID, model.Paid)
+	order.UnfulfilledItems = []model.UnfulfilledItem{{ProductID: uuid.New(), Count: 1, Price: 5}}
+	order.DeliveryMethod = model.Courier
+	order.ShippingAddress = &model.ShippingAddress{Country: "Russia", City: "Moscow", Street: "Tverskaya 1"}
+	order.DeliveryFee = 300
+
+	orderRepo.On("Find", orderID).Return(order, nil)
+	orderRepo.On("NextID").Return(backorderID, nil)
+	orderRepo.On("Store", mock.MatchedBy(func(o *model.Order) bool {
+		return o.ID == backorderID &&
+			o.DeliveryMethod == model.Courier &&
+			o.ShippingAddress == order.ShippingAddress &&
+			o.DeliveryFee == 0
+	})).Return(nil)
+	orderRepo.On("Store", mock.MatchedBy(func(o *model.Order) bool {
+		return o.ID == orderID
+	})).Return(nil)
+	eventDisp.On("Dispatch", mock.Anything).Return(nil)
+
+	svc := NewOrderService(orderRepo, eventDisp)
+
+	_, err := svc.CreateBackorder(orderID)
+	assert.NoError(t, err)
+	orderRepo.AssertExpectations(t)
+}
+
+func TestSetPayment_Success(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+
+	orderID := uuid.New()
+	paymentID := uuid.New()
+	orderRepo.On("Find", orderID).Return(newPendingOrder(orderID, uuid.New()), nil)
+	orderRepo.On("Store", mock.MatchedBy(func(o *model.Order) bool {
+		return o.ID == orderID && o.PaymentID != nil && *o.PaymentID == paymentID
+	})).Return(nil)
+
+	svc := NewOrderService(orderRepo, new(MockEventDispatcher))
+
+	err := svc.SetPayment(orderID, paymentID)
+	assert.NoError(t, err)
+	orderRepo.AssertExpectations(t)
+}
+
+func TestSetPayment_AnotherPayment(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+
+	orderID := uuid.New()
+	paymentID := uuid.New()
+	order := newPendingOrder(orderID, uuid.New())
+	order.PaymentID = &paymentID
+	orderRepo.On("Find", orderID).Return(order, nil)
+
+	svc := NewOrderService(orderRepo, new(MockEventDispatcher))
+
+	assert.NoError(t, svc.SetPayment(orderID, paymentID))
+	assert.ErrorIs(t, svc.SetPayment(orderID, uuid.New()), ErrOrderPaymentExists)
+	orderRepo.AssertNotCalled(t, "Store", mock.Anything)
+}
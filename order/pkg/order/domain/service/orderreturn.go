@@ -0,0 +1,314 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	commonevent "order/pkg/common/event"
+	"order/pkg/order/domain/model"
+)
+
+var (
+	ErrEmptyOrderReturn = errors.New("order return has no items")
+	// ErrOrderNotReturnable means the order is not paid or is returned or refunded already
+	ErrOrderNotReturnable       = errors.New("order can not be returned")
+	ErrOrderReturnInProgress    = errors.New("order has a return in progress")
+	ErrInvalidOrderReturnStatus = errors.New("invalid order return status")
+)
+
+// returnableStatuses lists order statuses which allow a return
+var returnableStatuses = []model.OrderStatus{
+	model.Paid,
+	model.Processing,
+	model.Shipped,
+	model.Delivered,
+	model.PartiallyReturned,
+}
+
+type OrderReturnService interface {
+	// RequestReturn returns ID of the return covering items of the order, only one return of the order may be in progress
+	RequestReturn(orderID uuid.UUID, items []model.OrderReturnItem, reason string) (uuid.UUID, error)
+	// Approve and Reject store the order of the return as well, so concurrent decisions conflict on the order version
+	Approve(returnID uuid.UUID) error
+	Reject(returnID uuid.UUID, reason string) error
+	// Complete moves the order to Returned when all its items are returned and to PartiallyReturned otherwise
+	Complete(returnID uuid.UUID) error
+}
+
+func NewOrderReturnService(
+	returnRepo model.OrderReturnRepository,
+	orderRepo model.OrderRepository,
+	orderService OrderService,
+	dispatcher commonevent.Dispatcher,
+) OrderReturnService {
+	return &orderReturnService{
+		returnRepo:   returnRepo,
+		orderRepo:    orderRepo,
+		orderService: orderService,
+		dispatcher:   dispatcher,
+	}
+}
+
+type orderReturnService struct {
+	returnRepo   model.OrderReturnRepository
+	orderRepo    model.OrderRepository
+	orderService OrderService
+	dispatcher   commonevent.Dispatcher
+}
+
+func (o orderReturnService) RequestReturn(orderID uuid.UUID, items []model.OrderReturnItem, reason string) (uuid.UUID, error) {
+	if len(items) == 0 {
+		return uuid.Nil, ErrEmptyOrderReturn
+	}
+
+	order, err := o.orderRepo.Find(orderID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if !slices.Contains(returnableStatuses, order.Status) {
+		return uuid.Nil, ErrOrderNotReturnable
+	}
+
+	returns, err := o.returnRepo.FindByOrderID(orderID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	for _, r := range returns {
+		if r.Status == model.ReturnStatusRequested || r.Status == model.ReturnStatusApproved {
+			return uuid.Nil, ErrOrderReturnInProgress
+		}
+	}
+	returned := returnedCounts(returns)
+
+	returnItems := make([]model.OrderReturnItem, 0, len(items))
+	var itemsTotal float64
+	for _, item := range items {
+		if item.Count <= 0 {
+			return uuid.Nil, ErrInvalidItemCount
+		}
+		if i := slices.IndexFunc(returnItems, func(r model.OrderReturnItem) bool { return r.ProductID == item.ProductID }); i >= 0 {
+			returnItems[i].Count += item.Count
+			continue
+		}
+		returnItems = append(returnItems, model.OrderReturnItem{ProductID: item.ProductID, Count: item.Count})
+	}
+	for i, item := range returnItems {
+		j := slices.IndexFunc(order.Items, func(orderItem model.OrderItem) bool { return orderItem.ProductID == item.ProductID })
+		if j < 0 || returned[item.ProductID]+item.Count > order.Items[j].Count {
+			return uuid.Nil, ErrInvalidItemCount
+		}
+		returnItems[i].Price = order.Items[j].Price
+		itemsTotal += order.Items[j].Price * float64(item.Count)
+	}
+
+	refund := refundAmount(order, itemsTotal)
+	if returnsAll(order, returned, returnItems) {
+		// Last return takes what is left of the charged total, so rounding of prorated refunds and the delivery fee
+		// leave nothing on the payment and the order is always refunded in full
+		refund = remainingRefund(order, returns)
+	}
+
+	returnID, err := o.returnRepo.NextID()
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	now := time.Now()
+	orderReturn := &model.OrderReturn{
+		ID:           returnID,
+		OrderID:      orderID,
+		CustomerID:   order.CustomerID,
+		Status:       model.ReturnStatusRequested,
+		Items:        returnItems,
+		Reason:       reason,
+		RefundAmount: refund,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err = o.returnRepo.Store(orderReturn); err != nil {
+		return uuid.Nil, err
+	}
+
+	return returnID, o.dispatcher.Dispatch(model.OrderReturnRequested{
+		ReturnID:   returnID,
+		OrderID:    orderID,
+		CustomerID: order.CustomerID,
+		Items:      returnItems,
+		Reason:     reason,
+	})
+}
+
+func (o orderReturnService) Approve(returnID uuid.UUID) error {
+	orderReturn, err := o.returnRepo.Find(returnID)
+	if err != nil {
+		return err
+	}
+	if orderReturn.Status == model.ReturnStatusApproved {
+		return nil
+	}
+	if orderReturn.Status != model.ReturnStatusRequested {
+		return ErrInvalidOrderReturnStatus
+	}
+	if err = o.touchOrder(orderReturn.OrderID); err != nil {
+		return err
+	}
+
+	orderReturn.Status = model.ReturnStatusApproved
+	orderReturn.UpdatedAt = time.Now()
+	if err = o.returnRepo.Store(orderReturn); err != nil {
+		return err
+	}
+
+	return o.dispatcher.Dispatch(model.OrderReturnApproved{
+		ReturnID:   returnID,
+		OrderID:    orderReturn.OrderID,
+		CustomerID: orderReturn.CustomerID,
+	})
+}
+
+func (o orderReturnService) Reject(returnID uuid.UUID, reason string) error {
+	orderReturn, err := o.returnRepo.Find(returnID)
+	if err != nil {
+		return err
+	}
+	if orderReturn.Status == model.ReturnStatusRejected {
+		return nil
+	}
+	if orderReturn.Status != model.ReturnStatusRequested {
+		return ErrInvalidOrderReturnStatus
+	}
+	if err = o.touchOrder(orderReturn.OrderID); err != nil {
+		return err
+	}
+
+	orderReturn.Status = model.ReturnStatusRejected
+	orderReturn.RejectionReason = reason
+	orderReturn.UpdatedAt = time.Now()
+	if err = o.returnRepo.Store(orderReturn); err != nil {
+		return err
+	}
+
+	return o.dispatcher.Dispatch(model.OrderReturnRejected{
+		ReturnID:   returnID,
+		OrderID:    orderReturn.OrderID,
+		CustomerID: orderReturn.CustomerID,
+		Reason:     reason,
+	})
+}
+
+func (o orderReturnService) Complete(returnID uuid.UUID) error {
+	orderReturn, err := o.returnRepo.Find(returnID)
+	if err != nil {
+		return err
+	}
+	if orderReturn.Status == model.ReturnStatusCompleted {
+		return nil
+	}
+	if orderReturn.Status != model.ReturnStatusApproved {
+		return ErrInvalidOrderReturnStatus
+	}
+
+	orderReturn.Status = model.ReturnStatusCompleted
+	orderReturn.UpdatedAt = time.Now()
+	if err = o.returnRepo.Store(orderReturn); err != nil {
+		return err
+	}
+
+	order, err := o.orderRepo.Find(orderReturn.OrderID)
+	if err != nil {
+		return err
+	}
+	returns, err := o.returnRepo.FindByOrderID(orderReturn.OrderID)
+	if err != nil {
+		return err
+	}
+	returned := returnedCounts(returns)
+	status := model.Returned
+	for _, item := range order.Items {
+		if returned[item.ProductID] < item.Count {
+			status = model.PartiallyReturned
+			break
+		}
+	}
+	if err = o.orderService.SetStatus(order.ID, status); err != nil {
+		return err
+	}
+
+	return o.dispatcher.Dispatch(model.OrderReturnCompleted{
+		ReturnID:     returnID,
+		OrderID:      orderReturn.OrderID,
+		CustomerID:   orderReturn.CustomerID,
+		RefundAmount: orderReturn.RefundAmount,
+	})
+}
+
+// touchOrder stores the order again to bump its version
+func (o orderReturnService) touchOrder(orderID uuid.UUID) error {
+	order, err := o.orderRepo.Find(orderID)
+	if err != nil {
+		return err
+	}
+	order.UpdatedAt = time.Now()
+	return o.orderRepo.Store(order)
+}
+
+// returnedCounts sums items of completed returns per product
+func returnedCounts(returns []model.OrderReturn) map[uuid.UUID]int {
+	counts := make(map[uuid.UUID]int)
+	for _, r := range returns {
+		if r.Status != model.ReturnStatusCompleted {
+			continue
+		}
+		for _, item := range r.Items {
+			counts[item.ProductID] += item.Count
+		}
+	}
+	return counts
+}
+
+// returnsAll tells whether items of the return together with completed returns cover all items of the order
+func returnsAll(order *model.Order, returned map[uuid.UUID]int, items []model.OrderReturnItem) bool {
+	for _, orderItem := range order.Items {
+		count := returned[orderItem.ProductID]
+		if i := slices.IndexFunc(items, func(item model.OrderReturnItem) bool { return item.ProductID == orderItem.ProductID }); i >= 0 {
+			count += items[i].Count
+		}
+		if count < orderItem.Count {
+			return false
+		}
+	}
+	return true
+}
+
+// remainingRefund is the charged total of the order less refunds of completed returns
+func remainingRefund(order *model.Order, returns []model.OrderReturn) float64 {
+	remaining := order.DeliveryFee - order.Discount
+	for _, item := range order.Items {
+		remaining += item.TotalPrice
+	}
+	for _, r := range returns {
+		if r.Status == model.ReturnStatusCompleted {
+			remaining -= r.RefundAmount
+		}
+	}
+	return max(0, math.Round(remaining*100)/100)
+}
+
+// refundAmount takes the order discount off the returned items total in proportion to the order total
+func refundAmount(order *model.Order, itemsTotal float64) float64 {
+	if order.Discount <= 0 {
+		return itemsTotal
+	}
+	var orderTotal float64
+	for _, item := range order.Items {
+		orderTotal += item.TotalPrice
+	}
+	if orderTotal <= 0 {
+		return 0
+	}
+	return math.Round(itemsTotal*(orderTotal-order.Discount)/orderTotal*100) / 100
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"order/pkg/order/domain/model"
+)
+
+type MockOrderReturnRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderReturnRepository) NextID() (uuid.UUID, error) {
+	args := m.Called()
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockOrderReturnRepository) Store(orderReturn *model.OrderReturn) error {
+	args := m.Called(orderReturn)
+	return args.Error(0)
+}
+
+func (m *MockOrderReturnRepository) Find(id uuid.UUID) (*model.OrderReturn, error) {
+	args := m.Called(id)
+	if orderReturn, ok := args.Get(0).(*model.OrderReturn); ok {
+		return orderReturn, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOrderReturnRepository) FindByOrderID(orderID uuid.UUID) ([]model.OrderReturn, error) {
+	args := m.Called(orderID)
+	returns, _ := args.Get(0).([]model.OrderReturn)
+	return returns, args.Error(1)
+}
+
+func newPaidOrder(id uuid.UUID, items ...model.OrderItem) *model.Order {
+	order := newOpenOrder(id, uuid.New())
+	order.Status = model.Paid
+	order.Items = items
+	return order
+}
+
+func newOrderReturn(id, orderID uuid.UUID, status model.OrderReturnStatus, items ...model.OrderReturnItem) *model.OrderReturn {
+	now := time.Now()
+	return &model.OrderReturn{
+		ID:        id,
+		OrderID:   orderID,
+		Status:    status,
+		Items:     items,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func TestRequestReturn_ProratesDiscount(t *testing.T) {
+	returnRepo := new(MockOrderReturnRepository)
+	orderRepo := new(MockOrderRepository)
+	eventDispatcher := new(MockEventDispatcher)
+
+	orderID := uuid.New()
+	returnID := uuid.New()
+	productID := uuid.New()
+	order := newPaidOrder(orderID,
+		model.OrderItem{ProductID: productID, Count: 2, Price: 50, TotalPrice: 100},
+		model.OrderItem{ProductID: uuid.New(), Count: 1, Price: 100, TotalPrice: 100},
+	)
+	order.Discount = 20
+
+	orderRepo.On("Find", orderID).Return(order, nil)
+	returnRepo.On("FindByOrderID", orderID).Return(nil, nil)
+	returnRepo.On("NextID").Return(returnID, nil)
+	returnRepo.On("Store", mock.MatchedBy(func(r *model.OrderReturn) bool {
+		return r.ID == returnID &&
+			r.Status == model.ReturnStatusRequested &&
+			r.CustomerID == order.CustomerID &&
+			len(r.Items) == 1 &&
+			r.Items[0].Count == 2 &&
+			r.Items[0].Price == 50 &&
+			r.RefundAmount == 90
+	})).Return(nil)
+	eventDispatcher.On("Dispatch", mock.MatchedBy(func(e model.OrderReturnRequested) bool {
+		return e.ReturnID == returnID && e.OrderID == orderID
+	})).Return(nil)
+
+	svc := NewOrderReturnService(returnRepo, orderRepo, NewOrderService(orderRepo, eventDispatcher), eventDispatcher)
+
+	id, err := svc.RequestReturn(orderID, []model.OrderReturnItem{
+		{ProductID: productID, Count: 1},
+		{ProductID: productID, Count: 1},
+	}, "broken")
+	assert.NoError(t, err)
+	assert.Equal(t, returnID, id)
+	returnRepo.AssertExpectations(t)
+	eventDispatcher.AssertExpectations(t)
+}
+
+func TestRequestReturn_LastReturnRefundsRemainder(t *testing.T) {
+	returnRepo := new(MockOrderReturnRepository)
+	orderRepo := new(MockOrderRepository)
+	eventDispatcher := new(MockEventDispatcher)
+
+	orderID := uuid.New()
+	returnID := uuid.New()
+	productID := uuid.New()
+	otherProductID := uuid.New()
+	order := newPaidOrder(orderID,
+		model.OrderItem{ProductID: productID, Count: 1, Price: 10, TotalPrice: 10},
+		model.OrderItem{ProductID: otherProductID, Count: 2, Price: 10, TotalPrice: 20},
+	)
+	order.Discount = 10
+	order.DeliveryFee = 5
+	previous := newOrderReturn(uuid.New(), orderID, model.ReturnStatusCompleted,
+		model.OrderReturnItem{ProductID: otherProductID, Count: 1, Price: 10},
+	)
+	// Prorated refund of the previous return is rounded down, the last return refunds the cent left by it and the delivery fee
+	previous.RefundAmount = 6.66
+
+	orderRepo.On("Find", orderID).Return(order, nil)
+	returnRepo.On("FindByOrderID", orderID).Return([]model.OrderReturn{*previous}, nil)
+	returnRepo.On("NextID").Return(returnID, nil)
+	returnRepo.On("Store", mock.MatchedBy(func(r *model.OrderReturn) bool {
+		return r.ID == returnID && r.RefundAmount == 18.34
+	})).Return(nil)
+	eventDispatcher.On("Dispatch", mock.Anything).Return(nil)
+
+	svc := NewOrderReturnService(returnRepo, orderRepo, NewOrderService(orderRepo, eventDispatcher), eventDispatcher)
+
+	_, err := svc.RequestReturn(orderID, []model.OrderReturnItem{
+		{ProductID: productID, Count: 1},
+		{ProductID: otherProductID, Count: 1},
+	}, "unwanted")
+	assert.NoError(t, err)
+	returnRepo.AssertExpectations(t)
+}
+
+func TestRequestReturn_Invalid(t *testing.T) {
+	orderID := uuid.New()
+	productID := uuid.New()
+	item := model.OrderItem{ProductID: productID, Count: 2, Price: 10, TotalPrice: 20}
+
+	tests := []struct {
+		desc    string
+		order   *model.Order
+		returns []model.OrderReturn
+		items   []model.OrderReturnItem
+		err     error
+	}{
+		{
+			desc:  "no items",
+			order: newPaidOrder(orderID, item),
+			err:   ErrEmptyOrderReturn,
+		},
+		{
+			desc:  "order is not paid",
+			order: newPendingOrder(orderID, uuid.New()),
+			items: []model.OrderReturnItem{{ProductID: productID, Count: 1}},
+			err:   ErrOrderNotReturnable,
+		},
+		{
+			desc:    "return in progress",
+			order:   newPaidOrder(orderID, item),
+			returns: []model.OrderReturn{*newOrderReturn(uuid.New(), orderID, model.ReturnStatusApproved)},
+			items:   []model.OrderReturnItem{{ProductID: productID, Count: 1}},
+			err:     ErrOrderReturnInProgress,
+		},
+		{
+			desc:  "product is not in order",
+			order: newPaidOrder(orderID, item),
+			items: []model.OrderReturnItem{{ProductID: uuid.New(), Count: 1}},
+			err:   ErrInvalidItemCount,
+		},
+		{
+			desc:  "count exceeds ordered",
+			order: newPaidOrder(orderID, item),
+			items: []model.OrderReturnItem{{ProductID: productID, Count: 3}},
+			err:   ErrInvalidItemCount,
+		},
+		{
+			desc:  "count exceeds not yet returned",
+			order: newPaidOrder(orderID, item),
+			returns: []model.OrderReturn{*newOrderReturn(uuid.New(), orderID, model.ReturnStatusCompleted,
+				model.OrderReturnItem{ProductID: productID, Count: 1},
+			)},
+			items: []model.OrderReturnItem{{ProductID: productID, Count: 2}},
+			err:   ErrInvalidItemCount,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			returnRepo := new(MockOrderReturnRepository)
+			orderRepo := new(MockOrderRepository)
+			eventDispatcher := new(MockEventDispatcher)
+
+			orderRepo.On("Find", orderID).Return(tt.order, nil)
+			returnRepo.On("FindByOrderID", orderID).Return(tt.returns, nil)
+
+			svc := NewOrderReturnService(returnRepo, orderRepo, NewOrderService(orderRepo, eventDispatcher), eventDispatcher)
+
+			_, err := svc.RequestReturn(orderID, tt.items, "")
+			assert.ErrorIs(t, err, tt.err)
+			returnRepo.AssertNotCalled(t, "Store", mock.Anything)
+		})
+	}
+}
+
+func TestRejectReturn_Idempotent(t *testing.T) {
+	returnRepo := new(MockOrderReturnRepository)
+	eventDispatcher := new(MockEventDispatcher)
+
+	returnID := uuid.New()
+	returnRepo.On("Find", returnID).Return(newOrderReturn(returnID, uuid.New(), model.ReturnStatusRejected), nil)
+
+	svc := NewOrderReturnService(returnRepo, new(MockOrderRepository), nil, eventDispatcher)
+
+	assert.NoError(t, svc.Reject(returnID, "late"))
+	assert.ErrorIs(t, svc.Approve(returnID), ErrInvalidOrderReturnStatus)
+	returnRepo.AssertNotCalled(t, "Store", mock.Anything)
+	eventDispatcher.AssertNotCalled(t, "Dispatch", mock.Anything)
+}
+
+func TestApproveReturn_OrderVersionConflict(t *testing.T) {
+	returnRepo := new(MockOrderReturnRepository)
+	orderRepo := new(MockOrderRepository)
+	eventDispatcher := new(MockEventDispatcher)
+
+	returnID, orderID := uuid.New(), uuid.New()
+	returnRepo.On("Find", returnID).Return(newOrderReturn(returnID, orderID, model.ReturnStatusRequested), nil)
+	orderRepo.On("Find", orderID).Return(newPaidOrder(orderID), nil)
+	// Concurrent decision has stored the order since it was found
+	orderRepo.On("Store", mock.Anything).Return(model.ErrOrderVersionConflict)
+
+	svc := NewOrderReturnService(returnRepo, orderRepo, nil, eventDispatcher)
+
+	assert.ErrorIs(t, svc.Approve(returnID), model.ErrOrderVersionConflict)
+	returnRepo.AssertNotCalled(t, "Store", mock.Anything)
+	eventDispatcher.AssertNotCalled(t, "Dispatch", mock.Anything)
+}
+
+func TestCompleteReturn_SetsOrderStatus(t *testing.T) {
+	productID := uuid.New()
+	otherProductID := uuid.New()
+
+	tests := []struct {
+		desc     string
+		previous []model.OrderReturnItem
+		status   model.OrderStatus
+	}{
+		{
+			desc:   "some items left",
+			status: model.PartiallyReturned,
+		},
+		{
+			desc:     "all items returned",
+			previous: []model.OrderReturnItem{{ProductID: otherProductID, Count: 1}},
+			status:   model.Returned,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			returnRepo := new(MockOrderReturnRepository)
+			orderRepo := new(MockOrderRepository)
+			eventDispatcher := new(MockEventDispatcher)
+
+			orderID := uuid.New()
+			returnID := uuid.New()
+			order := newPaidOrder(orderID,
+				model.OrderItem{ProductID: productID, Count: 2, Price: 10, TotalPrice: 20},
+				model.OrderItem{ProductID: otherProductID, Count: 1, Price: 5, TotalPrice: 5},
+			)
+			orderReturn := newOrderReturn(returnID, orderID, model.ReturnStatusApproved,
+				model.OrderReturnItem{ProductID: productID, Count: 2, Price: 10},
+			)
+			returns := []model.OrderReturn{*newOrderReturn(returnID, orderID, model.ReturnStatusCompleted, orderReturn.Items...)}
+			if tt.previous != nil {
+				returns = append(returns, *newOrderReturn(uuid.New(), orderID, model.ReturnStatusCompleted, tt.previous...))
+			}
+
+			returnRepo.On("Find", returnID).Return(orderReturn, nil)
+			returnRepo.On("Store", mock.MatchedBy(func(r *model.OrderReturn) bool {
+				return r.ID == returnID && r.Status == model.ReturnStatusCompleted
+			})).Return(nil)
+			returnRepo.On("FindByOrderID", orderID).Return(returns, nil)
+			orderRepo.On("Find", orderID).Return(order, nil)
+			orderRepo.On("Store", mock.MatchedBy(func(o *model.Order) bool {
+				return o.ID == orderID && o.Status == tt.status
+			})).Return(nil)
+			eventDispatcher.On("Dispatch", mock.MatchedBy(func(e model.OrderStatusChanged) bool {
+				return e.OrderID == orderID && e.From == model.Paid && e.To == tt.status
+			})).Return(nil)
+			eventDispatcher.On("Dispatch", mock.MatchedBy(func(e model.OrderReturnCompleted) bool {
+				return e.ReturnID == returnID
+			})).Return(nil)
+
+			svc := NewOrderReturnService(returnRepo, orderRepo, NewOrderService(orderRepo, eventDispatcher), eventDispatcher)
+
+			assert.NoError(t, svc.Complete(returnID))
+			orderRepo.AssertExpectations(t)
+			eventDispatcher.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	commonevent "order/pkg/common/event"
+	"order/pkg/order/domain/model"
+)
+
+// MinScheduleInterval is the shortest interval between scheduled orders
+const MinScheduleInterval = time.Hour
+
+var (
+	ErrEmptyOrderTemplate            = errors.New("order template has no items")
+	ErrInvalidOrderSchedule          = errors.New("invalid order schedule")
+	ErrOrderTemplateAlreadyScheduled = errors.New("order template is already scheduled")
+	ErrOrderTemplateNotScheduled     = errors.New("order template is not scheduled")
+)
+
+type OrderTemplateService interface {
+	CreateTemplate(customerID uuid.UUID, items []model.OrderTemplateItem) (uuid.UUID, error)
+	Schedule(templateID uuid.UUID, schedule model.OrderSchedule) error
+	SetSchedulePaused(templateID uuid.UUID, paused bool) error
+	// ReportFailedRun notifies the customer that a scheduled run did not produce a paid order
+	ReportFailedRun(templateID uuid.UUID, orderID *uuid.UUID, reason string) error
+}
+
+func NewOrderTemplateService(repo model.OrderTemplateRepository, dispatcher commonevent.Dispatcher) OrderTemplateService {
+	return &orderTemplateService{
+		repo:       repo,
+		dispatcher: dispatcher,
+	}
+}
+
+type orderTemplateService struct {
+	repo       model.OrderTemplateRepository
+	dispatcher commonevent.Dispatcher
+}
+
+func (o orderTemplateService) CreateTemplate(customerID uuid.UUID, items []model.OrderTemplateItem) (uuid.UUID, error) {
+	if len(items) == 0 {
+		return uuid.Nil, ErrEmptyOrderTemplate
+	}
+	merged := make([]model.OrderTemplateItem, 0, len(items))
+	indexes := make(map[uuid.UUID]int, len(items))
+	for _, item := range items {
+		if item.Count <= 0 {
+			return uuid.Nil, ErrInvalidItemCount
+		}
+		if i, ok := indexes[item.ProductID]; ok {
+			merged[i].Count += item.Count
+			continue
+		}
+		indexes[item.ProductID] = len(merged)
+		merged = append(merged, item)
+	}
+
+	templateID, err := o.repo.NextID()
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	now := time.Now()
+	return templateID, o.repo.Store(&model.OrderTemplate{
+		ID:         templateID,
+		CustomerID: customerID,
+		Items:      merged,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+}
+
+func (o orderTemplateService) Schedule(templateID uuid.UUID, schedule model.OrderSchedule) error {
+	schedule.CronExpression = strings.TrimSpace(schedule.CronExpression)
+	hasCron := schedule.CronExpression != ""
+	hasInterval := schedule.Interval != 0
+	if hasCron == hasInterval || (hasInterval && schedule.Interval < MinScheduleInterval) {
+		return ErrInvalidOrderSchedule
+	}
+
+	template, err := o.repo.Find(templateID)
+	if err != nil {
+		return err
+	}
+	if template.Schedule != nil {
+		return ErrOrderTemplateAlreadyScheduled
+	}
+
+	schedule.Paused = false
+	template.Schedule = &schedule
+	template.UpdatedAt = time.Now()
+	return o.repo.Store(template)
+}
+
+func (o orderTemplateService) SetSchedulePaused(templateID uuid.UUID, paused bool) error {
+	template, err := o.repo.Find(templateID)
+	if err != nil {
+		return err
+	}
+	if template.Schedule == nil {
+		return ErrOrderTemplateNotScheduled
+	}
+	if template.Schedule.Paused == paused {
+		return nil
+	}
+
+	template.Schedule.Paused = paused
+	template.UpdatedAt = time.Now()
+	return o.repo.Store(template)
+}
+
+func (o orderTemplateService) ReportFailedRun(templateID uuid.UUID, orderID *uuid.UUID, reason string) error {
+	template, err := o.repo.Find(templateID)
+	if err != nil {
+		return err
+	}
+
+	return o.dispatcher.Dispatch(model.ScheduledOrderFailed{
+		TemplateID: templateID,
+		CustomerID: template.CustomerID,
+		OrderID:    orderID,
+		Reason:     reason,
+	})
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"order/pkg/order/domain/model"
+)
+
+type MockOrderTemplateRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderTemplateRepository) NextID() (uuid.UUID, error) {
+	args := m.Called()
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockOrderTemplateRepository) Store(template *model.OrderTemplate) error {
+	args := m.Called(template)
+	return args.Error(0)
+}
+
+func (m *MockOrderTemplateRepository) Find(id uuid.UUID) (*model.OrderTemplate, error) {
+	args := m.Called(id)
+	if template, ok := args.Get(0).(*model.OrderTemplate); ok {
+		return template, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func newOrderTemplate(id uuid.UUID, schedule *model.OrderSchedule) *model.OrderTemplate {
+	now := time.Now()
+	return &model.OrderTemplate{
+		ID:         id,
+		CustomerID: uuid.New(),
+		Items:      []model.OrderTemplateItem{{ProductID: uuid.New(), Count: 1}},
+		Schedule:   schedule,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+func TestCreateOrderTemplate_MergesSameProduct(t *testing.T) {
+	templateRepo := new(MockOrderTemplateRepository)
+
+	customerID := uuid.New()
+	productID := uuid.New()
+	templateID := uuid.New()
+
+	templateRepo.On("NextID").Return(templateID, nil)
+	templateRepo.On("Store", mock.MatchedBy(func(tmpl *model.OrderTemplate) bool {
+		return tmpl.ID == templateID &&
+			tmpl.CustomerID == customerID &&
+			tmpl.Schedule == nil &&
+			len(tmpl.Items) == 1 &&
+			tmpl.Items[0].Count == 5
+	})).Return(nil)
+
+	svc := NewOrderTemplateService(templateRepo, new(MockEventDispatcher))
+
+	id, err := svc.CreateTemplate(customerID, []model.OrderTemplateItem{
+		{ProductID: productID, Count: 2},
+		{ProductID: productID, Count: 3},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, templateID, id)
+	templateRepo.AssertExpectations(t)
+}
+
+func TestCreateOrderTemplate_Invalid(t *testing.T) {
+	svc := NewOrderTemplateService(new(MockOrderTemplateRepository), new(MockEventDispatcher))
+
+	_, err := svc.CreateTemplate(uuid.New(), nil)
+	assert.ErrorIs(t, err, ErrEmptyOrderTemplate)
+
+	_, err = svc.CreateTemplate(uuid.New(), []model.OrderTemplateItem{{ProductID: uuid.New(), Count: 0}})
+	assert.ErrorIs(t, err, ErrInvalidItemCount)
+}
+
+func TestScheduleOrderTemplate(t *testing.T) {
+	templateRepo := new(MockOrderTemplateRepository)
+
+	templateID := uuid.New()
+	templateRepo.On("Find", templateID).Return(newOrderTemplate(templateID, nil), nil)
+	templateRepo.On("Store", mock.MatchedBy(func(tmpl *model.OrderTemplate) bool {
+		return tmpl.Schedule != nil &&
+			tmpl.Schedule.CronExpression == "0 9 * * MON" &&
+			!tmpl.Schedule.Paused
+	})).Return(nil)
+
+	svc := NewOrderTemplateService(templateRepo, new(MockEventDispatcher))
+
+	err := svc.Schedule(templateID, model.OrderSchedule{CronExpression: " 0 9 * * MON "})
+	assert.NoError(t, err)
+	templateRepo.AssertExpectations(t)
+}
+
+func TestScheduleOrderTemplate_InvalidSchedule(t *testing.T) {
+	svc := NewOrderTemplateService(new(MockOrderTemplateRepository), new(MockEventDispatcher))
+
+	err := svc.Schedule(uuid.New(), model.OrderSchedule{})
+	assert.ErrorIs(t, err, ErrInvalidOrderSchedule)
+
+	err = svc.Schedule(uuid.New(), model.OrderSchedule{CronExpression: "@daily", Interval: 24 * time.Hour})
+	assert.ErrorIs(t, err, ErrInvalidOrderSchedule)
+
+	err = svc.Schedule(uuid.New(), model.OrderSchedule{Interval: time.Minute})
+	assert.ErrorIs(t, err, ErrInvalidOrderSchedule)
+}
+
+func TestScheduleOrderTemplate_AlreadyScheduled(t *testing.T) {
+	templateRepo := new(MockOrderTemplateRepository)
+
+	templateID := uuid.New()
+	templateRepo.On("Find", templateID).Return(newOrderTemplate(templateID, &model.OrderSchedule{Interval: time.Hour}), nil)
+
+	svc := NewOrderTemplateService(templateRepo, new(MockEventDispatcher))
+
+	err := svc.Schedule(templateID, model.OrderSchedule{Interval: 2 * time.Hour})
+	assert.ErrorIs(t, err, ErrOrderTemplateAlreadyScheduled)
+	templateRepo.AssertNotCalled(t, "Store", mock.Anything)
+}
+
+func TestSetSchedulePaused(t *testing.T) {
+	templateRepo := new(MockOrderTemplateRepository)
+
+	templateID := uuid.New()
+	templateRepo.On("Find", templateID).Return(newOrderTemplate(templateID, &model.OrderSchedule{Interval: time.Hour}), nil)
+	templateRepo.On("Store", mock.MatchedBy(func(tmpl *model.OrderTemplate) bool {
+		return tmpl.Schedule.Paused
+	})).Return(nil)
+
+	svc := NewOrderTemplateService(templateRepo, new(MockEventDispatcher))
+
+	err := svc.SetSchedulePaused(templateID, true)
+	assert.NoError(t, err)
+	templateRepo.AssertExpectations(t)
+}
+
+func TestSetSchedulePaused_NotScheduled(t *testing.T) {
+	templateRepo := new(MockOrderTemplateRepository)
+
+	templateID := uuid.New()
+	templateRepo.On("Find", templateID).Return(newOrderTemplate(templateID, nil), nil)
+
+	svc := NewOrderTemplateService(templateRepo, new(MockEventDispatcher))
+
+	err := svc.SetSchedulePaused(templateID, true)
+	assert.ErrorIs(t, err, ErrOrderTemplateNotScheduled)
+}
+
+func TestReportFailedRun(t *testing.T) {
+	templateRepo := new(MockOrderTemplateRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	templateID := uuid.New()
+	orderID := uuid.New()
+	template := newOrderTemplate(templateID, &model.OrderSchedule{Interval: time.Hour})
+	templateRepo.On("Find", templateID).Return(template, nil)
+	eventDisp.On("Dispatch", mock.MatchedBy(func(e model.ScheduledOrderFailed) bool {
+		return e.TemplateID == templateID &&
+			e.CustomerID == template.CustomerID &&
+			e.OrderID != nil && *e.OrderID == orderID &&
+			e.Reason == "insufficient funds"
+	})).Return(nil)
+
+	svc := NewOrderTemplateService(templateRepo, eventDisp)
+
+	err := svc.ReportFailedRun(templateID, &orderID, "insufficient funds")
+	assert.NoError(t, err)
+	eventDisp.AssertExpectations(t)
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	commonevent "order/pkg/common/event"
+	"order/pkg/order/domain/model"
+)
+
+var (
+	ErrInvalidPromotion           = errors.New("invalid promotion")
+	ErrPromoCodeAlreadyExists     = errors.New("promo code already exists")
+	ErrPromotionNotActive         = errors.New("promotion is not active")
+	ErrOrderTotalTooLow           = errors.New("order total is below promotion minimum")
+	ErrPromotionUsageLimitReached = errors.New("promotion usage limit reached")
+	ErrPromoCodeAlreadyRedeemed   = errors.New("promo code of the order is already redeemed")
+)
+
+type PromotionService interface {
+	CreatePromotion(promotion model.Promotion) (uuid.UUID, error)
+	// ApplyPromoCode sets discount of the order, it replaces previously applied code
+	ApplyPromoCode(orderID uuid.UUID, code string) error
+	// RedeemPromoCode checks the applied promotion again, counts its redemption and returns final discount of the order
+	RedeemPromoCode(orderID uuid.UUID) (float64, error)
+	// ReleasePromoCode undoes RedeemPromoCode, so the redemption is not counted anymore
+	ReleasePromoCode(orderID uuid.UUID) error
+}
+
+func NewPromotionService(
+	promotionRepo model.PromotionRepository,
+	orderRepo model.OrderRepository,
+	dispatcher commonevent.Dispatcher,
+) PromotionService {
+	return &promotionService{
+		promotionRepo: promotionRepo,
+		orderRepo:     orderRepo,
+		dispatcher:    dispatcher,
+	}
+}
+
+type promotionService struct {
+	promotionRepo model.PromotionRepository
+	orderRepo     model.OrderRepository
+	dispatcher    commonevent.Dispatcher
+}
+
+func (p promotionService) CreatePromotion(promotion model.Promotion) (uuid.UUID, error) {
+	promotion.Code = strings.TrimSpace(promotion.Code)
+	if !isValidPromotion(promotion) {
+		return uuid.Nil, ErrInvalidPromotion
+	}
+
+	_, err := p.promotionRepo.FindByCode(promotion.Code)
+	if err == nil {
+		return uuid.Nil, ErrPromoCodeAlreadyExists
+	}
+	if !errors.Is(err, model.ErrPromotionNotFound) {
+		return uuid.Nil, err
+	}
+
+	promotion.ID, err = p.promotionRepo.NextID()
+	if err != nil {
+		return uuid.Nil, err
+	}
+	promotion.CreatedAt = time.Now()
+	return promotion.ID, p.promotionRepo.Store(&promotion)
+}
+
+func (p promotionService) ApplyPromoCode(orderID uuid.UUID, code string) error {
+	order, err := p.orderRepo.Find(orderID)
+	if err != nil {
+		return err
+	}
+	if order.Status != model.Open && order.Status != model.Pending {
+		return ErrInvalidOrderStatus
+	}
+	if order.PromotionRedeemed {
+		return ErrPromoCodeAlreadyRedeemed
+	}
+
+	promotion, err := p.promotionRepo.FindByCode(strings.TrimSpace(code))
+	if err != nil {
+		return err
+	}
+	discount, err := p.discount(promotion, order)
+	if err != nil {
+		return err
+	}
+
+	order.PromotionID = &promotion.ID
+	order.Discount = discount
+	order.UpdatedAt = time.Now()
+	if err = p.orderRepo.Store(order); err != nil {
+		return err
+	}
+
+	return p.dispatcher.Dispatch(model.OrderPromoCodeApplied{
+		OrderID:     orderID,
+		PromotionID: promotion.ID,
+		Code:        promotion.Code,
+		Discount:    discount,
+	})
+}
+
+func (p promotionService) RedeemPromoCode(orderID uuid.UUID) (float64, error) {
+	order, err := p.orderRepo.Find(orderID)
+	if err != nil {
+		return 0, err
+	}
+	if order.PromotionID == nil || order.PromotionRedeemed {
+		return order.Discount, nil
+	}
+
+	promotion, err := p.promotionRepo.Find(*order.PromotionID)
+	if err != nil {
+		return 0, err
+	}
+	// Items may be changed since the code was applied, so the discount is computed again
+	discount, err := p.discount(promotion, order)
+	if err != nil {
+		return 0, err
+	}
+
+	err = p.promotionRepo.AddRedemption(model.PromotionRedemption{
+		PromotionID: promotion.ID,
+		OrderID:     orderID,
+		CustomerID:  order.CustomerID,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	order.Discount = discount
+	order.PromotionRedeemed = true
+	order.UpdatedAt = time.Now()
+	return discount, p.orderRepo.Store(order)
+}
+
+func (p promotionService) ReleasePromoCode(orderID uuid.UUID) error {
+	order, err := p.orderRepo.Find(orderID)
+	if err != nil {
+		return err
+	}
+	if order.PromotionID == nil || !order.PromotionRedeemed {
+		return nil
+	}
+
+	err = p.promotionRepo.RemoveRedemption(*order.PromotionID, orderID)
+	if err != nil {
+		return err
+	}
+
+	order.PromotionRedeemed = false
+	order.UpdatedAt = time.Now()
+	return p.orderRepo.Store(order)
+}
+
+func (p promotionService) discount(promotion *model.Promotion, order *model.Order) (float64, error) {
+	if !promotion.Active(time.Now()) {
+		return 0, ErrPromotionNotActive
+	}
+
+	total := orderTotal(order)
+	if total < promotion.MinOrderTotal {
+		return 0, ErrOrderTotalTooLow
+	}
+
+	if promotion.PerCustomerLimit > 0 {
+		redemptions, err := p.promotionRepo.CountRedemptions(promotion.ID, order.CustomerID)
+		if err != nil {
+			return 0, err
+		}
+		if redemptions >= promotion.PerCustomerLimit {
+			return 0, ErrPromotionUsageLimitReached
+		}
+	}
+
+	return promotion.Discount(total), nil
+}
+
+func isValidPromotion(promotion model.Promotion) bool {
+	if promotion.Code == "" || promotion.Value <= 0 || promotion.MinOrderTotal < 0 || promotion.PerCustomerLimit < 0 {
+		return false
+	}
+	switch promotion.Kind {
+	case model.PercentageDiscount:
+		if promotion.Value > 100 {
+			return false
+		}
+	case model.FixedAmountDiscount:
+	default:
+		return false
+	}
+	if promotion.ValidFrom != nil && promotion.ValidTo != nil && !promotion.ValidFrom.Before(*promotion.ValidTo) {
+		return false
+	}
+	return true
+}
+
+func orderTotal(order *model.Order) float64 {
+	var total float64
+	for _, item := range order.Items {
+		total += item.TotalPrice
+	}
+	return total
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"order/pkg/order/domain/model"
+)
+
+type MockPromotionRepository struct {
+	mock.Mock
+}
+
+func (m *MockPromotionRepository) NextID() (uuid.UUID, error) {
+	args := m.Called()
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockPromotionRepository) Store(promotion *model.Promotion) error {
+	args := m.Called(promotion)
+	return args.Error(0)
+}
+
+func (m *MockPromotionRepository) Find(id uuid.UUID) (*model.Promotion, error) {
+	args := m.Called(id)
+	if promotion, ok := args.Get(0).(*model.Promotion); ok {
+		return promotion, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPromotionRepository) FindByCode(code string) (*model.Promotion, error) {
+	args := m.Called(code)
+	if promotion, ok := args.Get(0).(*model.Promotion); ok {
+		return promotion, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPromotionRepository) AddRedemption(redemption model.PromotionRedemption) error {
+	args := m.Called(redemption)
+	return args.Error(0)
+}
+
+func (m *MockPromotionRepository) RemoveRedemption(promotionID, orderID uuid.UUID) error {
+	args := m.Called(promotionID, orderID)
+	return args.Error(0)
+}
+
+func (m *MockPromotionRepository) CountRedemptions(promotionID, customerID uuid.UUID) (int, error) {
+	args := m.Called(promotionID, customerID)
+	return args.Int(0), args.Error(1)
+}
+
+func newOrderWithTotal(id, customerID uuid.UUID, total float64) *model.Order {
+	order := newOpenOrder(id, customerID)
+	order.Items = []model.OrderItem{{
+		OrderID:    id,
+		ProductID:  uuid.New(),
+		Count:      1,
+		Price:      total,
+		TotalPrice: total,
+	}}
+	return order
+}
+
+func newPromotion(kind model.PromotionKind, value float64) *model.Promotion {
+	return &model.Promotion{
+		ID:    uuid.New(),
+		Code:  "SALE",
+		Kind:  kind,
+		Value: value,
+	}
+}
+
+func TestPromotionDiscount(t *testing.T) {
+	assert.Equal(t, 12.35, newPromotion(model.PercentageDiscount, 10).Discount(123.45))
+	assert.Equal(t, 20.0, newPromotion(model.FixedAmountDiscount, 20).Discount(100))
+	assert.Equal(t, 15.0, newPromotion(model.FixedAmountDiscount, 20).Discount(15))
+}
+
+func TestCreatePromotion_Invalid(t *testing.T) {
+	promotionRepo := new(MockPromotionRepository)
+	svc := NewPromotionService(promotionRepo, new(MockOrderRepository), new(MockEventDispatcher))
+
+	_, err := svc.CreatePromotion(*newPromotion(model.PercentageDiscount, 150))
+	assert.ErrorIs(t, err, ErrInvalidPromotion)
+
+	_, err = svc.CreatePromotion(*newPromotion(model.FixedAmountDiscount, 0))
+	assert.ErrorIs(t, err, ErrInvalidPromotion)
+	promotionRepo.AssertNotCalled(t, "Store", mock.Anything)
+}
+
+func TestCreatePromotion_CodeExists(t *testing.T) {
+	promotionRepo := new(MockPromotionRepository)
+	promotionRepo.On("FindByCode", "SALE").Return(newPromotion(model.PercentageDiscount, 10), nil)
+
+	svc := NewPromotionService(promotionRepo, new(MockOrderRepository), new(MockEventDispatcher))
+
+	_, err := svc.CreatePromotion(*newPromotion(model.PercentageDiscount, 10))
+	assert.ErrorIs(t, err, ErrPromoCodeAlreadyExists)
+}
+
+func TestApplyPromoCode_Success(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+	promotionRepo := new(MockPromotionRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	orderID := uuid.New()
+	order := newOrderWithTotal(orderID, uuid.New(), 200)
+	promotion := newPromotion(model.PercentageDiscount, 10)
+
+	orderRepo.On("Find", orderID).Return(order, nil)
+	promotionRepo.On("FindByCode", "SALE").Return(promotion, nil)
+	orderRepo.On("Store", mock.MatchedBy(func(o *model.Order) bool {
+		return *o.PromotionID == promotion.ID && o.Discount == 20 && !o.PromotionRedeemed
+	})).Return(nil)
+	eventDisp.On("Dispatch", mock.MatchedBy(func(e model.OrderPromoCodeApplied) bool {
+		return e.OrderID == orderID && e.PromotionID == promotion.ID && e.Discount == 20
+	})).Return(nil)
+
+	svc := NewPromotionService(promotionRepo, orderRepo, eventDisp)
+
+	err := svc.ApplyPromoCode(orderID, " SALE ")
+	assert.NoError(t, err)
+	orderRepo.AssertExpectations(t)
+	eventDisp.AssertExpectations(t)
+}
+
+func TestApplyPromoCode_OrderTotalTooLow(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+	promotionRepo := new(MockPromotionRepository)
+
+	orderID := uuid.New()
+	promotion := newPromotion(model.FixedAmountDiscount, 10)
+	promotion.MinOrderTotal = 100
+
+	orderRepo.On("Find", orderID).Return(newOrderWithTotal(orderID, uuid.New(), 50), nil)
+	promotionRepo.On("FindByCode", "SALE").Return(promotion, nil)
+
+	svc := NewPromotionService(promotionRepo, orderRepo, new(MockEventDispatcher))
+
+	err := svc.ApplyPromoCode(orderID, "SALE")
+	assert.ErrorIs(t, err, ErrOrderTotalTooLow)
+	orderRepo.AssertNotCalled(t, "Store", mock.Anything)
+}
+
+func TestApplyPromoCode_NotActive(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+	promotionRepo := new(MockPromotionRepository)
+
+	orderID := uuid.New()
+	promotion := newPromotion(model.FixedAmountDiscount, 10)
+	validTo := time.Now().Add(-time.Hour)
+	promotion.ValidTo = &validTo
+
+	orderRepo.On("Find", orderID).Return(newOrderWithTotal(orderID, uuid.New(), 50), nil)
+	promotionRepo.On("FindByCode", "SALE").Return(promotion, nil)
+
+	svc := NewPromotionService(promotionRepo, orderRepo, new(MockEventDispatcher))
+
+	err := svc.ApplyPromoCode(orderID, "SALE")
+	assert.ErrorIs(t, err, ErrPromotionNotActive)
+}
+
+func TestApplyPromoCode_UsageLimitReached(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+	promotionRepo := new(MockPromotionRepository)
+
+	orderID := uuid.New()
+	customerID := uuid.New()
+	promotion := newPromotion(model.FixedAmountDiscount, 10)
+	promotion.PerCustomerLimit = 1
+
+	orderRepo.On("Find", orderID).Return(newOrderWithTotal(orderID, customerID, 50), nil)
+	promotionRepo.On("FindByCode", "SALE").Return(promotion, nil)
+	promotionRepo.On("CountRedemptions", promotion.ID, customerID).Return(1, nil)
+
+	svc := NewPromotionService(promotionRepo, orderRepo, new(MockEventDispatcher))
+
+	err := svc.ApplyPromoCode(orderID, "SALE")
+	assert.ErrorIs(t, err, ErrPromotionUsageLimitReached)
+}
+
+func TestApplyPromoCode_AlreadyRedeemed(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+	promotionRepo := new(MockPromotionRepository)
+
+	orderID := uuid.New()
+	order := newOrderWithTotal(orderID, uuid.New(), 50)
+	order.Status = model.Pending
+	order.PromotionRedeemed = true
+	orderRepo.On("Find", orderID).Return(order, nil)
+
+	svc := NewPromotionService(promotionRepo, orderRepo, new(MockEventDispatcher))
+
+	err := svc.ApplyPromoCode(orderID, "SALE")
+	assert.ErrorIs(t, err, ErrPromoCodeAlreadyRedeemed)
+	promotionRepo.AssertNotCalled(t, "FindByCode", mock.Anything)
+}
+
+func TestRedeemPromoCode_Success(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+	promotionRepo := new(MockPromotionRepository)
+
+	orderID := uuid.New()
+	customerID := uuid.New()
+	promotion := newPromotion(model.FixedAmountDiscount, 30)
+	order := newOrderWithTotal(orderID, customerID, 20)
+	order.Status = model.Pending
+	order.PromotionID = &promotion.ID
+	order.Discount = 30
+
+	orderRepo.On("Find", orderID).Return(order, nil)
+	promotionRepo.On("Find", promotion.ID).Return(promotion, nil)
+	promotionRepo.On("AddRedemption", mock.MatchedBy(func(r model.PromotionRedemption) bool {
+		return r.PromotionID == promotion.ID && r.OrderID == orderID && r.CustomerID == customerID
+	})).Return(nil)
+	orderRepo.On("Store", mock.MatchedBy(func(o *model.Order) bool {
+		return o.PromotionRedeemed && o.Discount == 20
+	})).Return(nil)
+
+	svc := NewPromotionService(promotionRepo, orderRepo, new(MockEventDispatcher))
+
+	discount, err := svc.RedeemPromoCode(orderID)
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, discount)
+	promotionRepo.AssertExpectations(t)
+	orderRepo.AssertExpectations(t)
+}
+
+func TestRedeemPromoCode_AlreadyRedeemed_Idempotent(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+	promotionRepo := new(MockPromotionRepository)
+
+	orderID := uuid.New()
+	promotionID := uuid.New()
+	order := newOrderWithTotal(orderID, uuid.New(), 20)
+	order.PromotionID = &promotionID
+	order.Discount = 5
+	order.PromotionRedeemed = true
+	orderRepo.On("Find", orderID).Return(order, nil)
+
+	svc := NewPromotionService(promotionRepo, orderRepo, new(MockEventDispatcher))
+
+	discount, err := svc.RedeemPromoCode(orderID)
+	assert.NoError(t, err)
+	assert.Equal(t, 5.0, discount)
+	promotionRepo.AssertNotCalled(t, "AddRedemption", mock.Anything)
+}
+
+func TestReleasePromoCode_Success(t *testing.T) {
+	orderRepo := new(MockOrderRepository)
+	promotionRepo := new(MockPromotionRepository)
+
+	orderID := uuid.New()
+	promotionID := uuid.New()
+	order := newOrderWithTotal(orderID, uuid.New(), 20)
+	order.PromotionID = &promotionID
+	order.PromotionRedeemed = true
+
+	orderRepo.On("Find", orderID).Return(order, nil)
+	promotionRepo.On("RemoveRedemption", promotionID, orderID).Return(nil)
+	orderRepo.On("Store", mock.MatchedBy(func(o *model.Order) bool {
+		return !o.PromotionRedeemed
+	})).Return(nil)
+
+	svc := NewPromotionService(promotionRepo, orderRepo, new(MockEventDispatcher))
+
+	err := svc.ReleasePromoCode(orderID)
+	assert.NoError(t, err)
+	promotionRepo.AssertExpectations(t)
+	orderRepo.AssertExpectations(t)
+}
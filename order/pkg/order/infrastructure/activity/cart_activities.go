@@ -0,0 +1,19 @@
+package activity
+
+import (
+	"context"
+
+	"order/pkg/order/app/service"
+)
+
+type CartActivities struct {
+	cartService service.CartService
+}
+
+func NewCartActivities(cs service.CartService) *CartActivities {
+	return &CartActivities{cartService: cs}
+}
+
+func (a *CartActivities) RemoveExpiredCartsActivity(ctx context.Context) error {
+	return a.cartService.RemoveExpiredCarts(ctx)
+}
@@ -5,18 +5,184 @@ import (
 	"order/pkg/order/app/service"
 
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	appdata "order/pkg/order/app/data"
+	"order/pkg/order/app/query"
+	"order/pkg/order/domain/model"
+	domainservice "order/pkg/order/domain/service"
+	"order/pkg/order/infrastructure/temporal/workflows"
 )
 
+var statusMap = map[string]appdata.OrderStatus{
+	"Open":              appdata.Open,
+	"Pending":           appdata.Pending,
+	"Paid":              appdata.Paid,
+	"Cancelled":         appdata.Cancelled,
+	"Processing":        appdata.Processing,
+	"Shipped":           appdata.Shipped,
+	"Delivered":         appdata.Delivered,
+	"ReturnRequested":   appdata.ReturnRequested,
+	"Returned":          appdata.Returned,
+	"Refunded":          appdata.Refunded,
+	"PartiallyReturned": appdata.PartiallyReturned,
+}
+
 type OrderActivities struct {
-	orderService service.OrderService
+	orderService      service.OrderService
+	orderQueryService query.OrderQueryService
 }
 
-func NewOrderActivities(os service.OrderService) *OrderActivities {
-	return &OrderActivities{orderService: os}
+func NewOrderActivities(os service.OrderService, qs query.OrderQueryService) *OrderActivities {
+	return &OrderActivities{
+		orderService:      os,
+		orderQueryService: qs,
+	}
 }
 
 func (a *OrderActivities) SetOrderStatusActivity(ctx context.Context, orderID string, status string) error {
 	uid, _ := uuid.Parse(orderID)
-	statusMap := map[string]int{"Open": 0, "Pending": 1, "Paid": 2, "Cancelled": 3}
-	return a.orderService.SetOrderStatus(ctx, uid, statusMap[status])
+	orderStatus, ok := statusMap[status]
+	if !ok {
+		return errors.Errorf("unknown order status %q", status)
+	}
+	return a.orderService.SetOrderStatus(service.WithActor(ctx, service.ActorOrderSaga), uid, int(orderStatus))
+}
+
+// FindOrdersActivity returns IDs of all orders matching params
+func (a *OrderActivities) FindOrdersActivity(ctx context.Context, params workflows.FindOrdersParams) ([]string, error) {
+	spec := appdata.ListOrdersSpec{
+		Statuses: make([]appdata.OrderStatus, len(params.Statuses)),
+	}
+	if params.CustomerID != "" {
+		customerID, err := uuid.Parse(params.CustomerID)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		spec.CustomerID = &customerID
+	}
+	if params.ProductID != "" {
+		productID, err := uuid.Parse(params.ProductID)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		spec.ProductID = &productID
+	}
+	for i, status := range params.Statuses {
+		orderStatus, ok := statusMap[status]
+		if !ok {
+			return nil, errors.Errorf("unknown order status %q", status)
+		}
+		spec.Statuses[i] = orderStatus
+	}
+
+	var orderIDs []string
+	for {
+		page, err := a.orderQueryService.ListOrders(ctx, spec)
+		if err != nil {
+			return nil, err
+		}
+		for _, order := range page.Orders {
+			orderIDs = append(orderIDs, order.ID.String())
+		}
+		if page.NextCursor == "" {
+			return orderIDs, nil
+		}
+		spec.Cursor = page.NextCursor
+	}
+}
+
+func (a *OrderActivities) CancelOrderActivity(ctx context.Context, orderID string) error {
+	uid, err := uuid.Parse(orderID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = a.orderService.CancelOrder(service.WithActor(ctx, service.ActorIntegrationEvent), uid)
+	// Order has been paid or cancelled since it was found
+	if errors.Is(err, domainservice.ErrInvalidOrderStatus) {
+		return nil
+	}
+	return err
+}
+
+func (a *OrderActivities) ExpireOrderActivity(ctx context.Context, orderID string) error {
+	uid, err := uuid.Parse(orderID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return a.orderService.ExpireOrder(service.WithActor(ctx, service.ActorOrderExpiry), uid)
+}
+
+func (a *OrderActivities) RemoveOrderProductActivity(ctx context.Context, orderID, productID string) error {
+	oid, err := uuid.Parse(orderID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	pid, err := uuid.Parse(productID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = a.orderService.RemoveOrderProduct(service.WithActor(ctx, service.ActorIntegrationEvent), oid, pid)
+	// Order is not Open anymore, so its saga fails to reserve the removed product
+	if errors.Is(err, domainservice.ErrInvalidOrderStatus) {
+		return nil
+	}
+	return err
+}
+
+func (a *OrderActivities) ApplyPaymentStatusActivity(ctx context.Context, orderID string, status int) error {
+	uid, err := uuid.Parse(orderID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = a.orderService.ApplyPaymentStatus(service.WithActor(ctx, service.ActorIntegrationEvent), uid, model.PaymentStatus(status))
+	if errors.Is(err, domainservice.ErrInvalidOrderStatus) {
+		return nil
+	}
+	return err
+}
+
+// MarkUnfulfilledItemsActivity returns total of the order without unfulfilled items
+func (a *OrderActivities) MarkUnfulfilledItemsActivity(ctx context.Context, orderID string, items []workflows.UnfulfilledItemParam) (float64, error) {
+	uid, err := uuid.Parse(orderID)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	unfulfilledItems := make([]appdata.UnfulfilledItem, len(items))
+	for i, item := range items {
+		productID, err := uuid.Parse(item.ProductID)
+		if err != nil {
+			return 0, errors.WithStack(err)
+		}
+		unfulfilledItems[i] = appdata.UnfulfilledItem{
+			ProductID: productID,
+			Count:     item.Quantity,
+			Reason:    item.Reason,
+		}
+	}
+	return a.orderService.MarkUnfulfilledItems(service.WithActor(ctx, service.ActorOrderSaga), uid, unfulfilledItems)
+}
+
+func (a *OrderActivities) CreateBackorderActivity(ctx context.Context, orderID string) (string, error) {
+	uid, err := uuid.Parse(orderID)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	backorderID, err := a.orderService.CreateBackorder(service.WithActor(ctx, service.ActorOrderSaga), uid)
+	if err != nil {
+		return "", err
+	}
+	return backorderID.String(), nil
+}
+
+func (a *OrderActivities) SetOrderPaymentActivity(ctx context.Context, orderID, paymentID string) error {
+	orderUID, err := uuid.Parse(orderID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	paymentUID, err := uuid.Parse(paymentID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return a.orderService.SetOrderPayment(service.WithActor(ctx, service.ActorOrderSaga), orderUID, paymentUID)
 }
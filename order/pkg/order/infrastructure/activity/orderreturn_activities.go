@@ -0,0 +1,42 @@
+package activity
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"order/pkg/order/app/service"
+)
+
+type OrderReturnActivities struct {
+	orderReturnService service.OrderReturnService
+}
+
+func NewOrderReturnActivities(ors service.OrderReturnService) *OrderReturnActivities {
+	return &OrderReturnActivities{orderReturnService: ors}
+}
+
+func (a *OrderReturnActivities) ApproveReturnActivity(ctx context.Context, returnID string) error {
+	uid, err := uuid.Parse(returnID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return a.orderReturnService.MarkReturnApproved(service.WithActor(ctx, service.ActorOrderReturn), uid)
+}
+
+func (a *OrderReturnActivities) RejectReturnActivity(ctx context.Context, returnID, reason string) error {
+	uid, err := uuid.Parse(returnID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return a.orderReturnService.MarkReturnRejected(service.WithActor(ctx, service.ActorOrderReturn), uid, reason)
+}
+
+func (a *OrderReturnActivities) CompleteReturnActivity(ctx context.Context, returnID string) error {
+	uid, err := uuid.Parse(returnID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return a.orderReturnService.CompleteReturn(service.WithActor(ctx, service.ActorOrderReturn), uid)
+}
@@ -0,0 +1,44 @@
+package activity
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"order/pkg/order/app/service"
+	"order/pkg/order/infrastructure/temporal/workflows"
+)
+
+type OrderTemplateActivities struct {
+	orderTemplateService service.OrderTemplateService
+}
+
+func NewOrderTemplateActivities(ots service.OrderTemplateService) *OrderTemplateActivities {
+	return &OrderTemplateActivities{orderTemplateService: ots}
+}
+
+func (a *OrderTemplateActivities) PrepareScheduledOrderActivity(ctx context.Context, templateID, runID string) (workflows.OrderSagaParams, error) {
+	uid, err := uuid.Parse(templateID)
+	if err != nil {
+		return workflows.OrderSagaParams{}, errors.WithStack(err)
+	}
+	return a.orderTemplateService.PrepareScheduledOrder(service.WithActor(ctx, service.ActorOrderSchedule), uid, runID)
+}
+
+// ReportScheduledOrderFailureActivity takes empty orderID when the order was not created
+func (a *OrderTemplateActivities) ReportScheduledOrderFailureActivity(ctx context.Context, templateID, orderID, reason string) error {
+	templateUID, err := uuid.Parse(templateID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	var orderUID *uuid.UUID
+	if orderID != "" {
+		uid, err := uuid.Parse(orderID)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		orderUID = &uid
+	}
+	return a.orderTemplateService.ReportScheduledOrderFailure(service.WithActor(ctx, service.ActorOrderSchedule), templateUID, orderUID, reason)
+}
@@ -0,0 +1,35 @@
+package activity
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"order/pkg/order/app/service"
+)
+
+type PromotionActivities struct {
+	promotionService service.PromotionService
+}
+
+func NewPromotionActivities(ps service.PromotionService) *PromotionActivities {
+	return &PromotionActivities{promotionService: ps}
+}
+
+// RedeemPromoCodeActivity returns discount of the order, it is zero when no promo code is applied
+func (a *PromotionActivities) RedeemPromoCodeActivity(ctx context.Context, orderID string) (float64, error) {
+	uid, err := uuid.Parse(orderID)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return a.promotionService.RedeemPromoCode(service.WithActor(ctx, service.ActorOrderSaga), uid)
+}
+
+func (a *PromotionActivities) ReleasePromoCodeActivity(ctx context.Context, orderID string) error {
+	uid, err := uuid.Parse(orderID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return a.promotionService.ReleasePromoCode(service.WithActor(ctx, service.ActorOrderSaga), uid)
+}
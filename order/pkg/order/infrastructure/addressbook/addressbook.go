@@ -0,0 +1,52 @@
+package addressbook
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"order/api/client/userpublicapi"
+	appdata "order/pkg/order/app/data"
+	"order/pkg/order/app/service"
+)
+
+func NewAddressBook(conn grpc.ClientConnInterface) service.AddressBook {
+	return &addressBook{
+		client: userpublicapi.NewUserPublicAPIClient(conn),
+	}
+}
+
+type addressBook struct {
+	client userpublicapi.UserPublicAPIClient
+}
+
+func (a *addressBook) FindAddress(ctx context.Context, addressID uuid.UUID) (appdata.Address, error) {
+	response, err := a.client.FindAddress(ctx, &userpublicapi.FindAddressRequest{
+		AddressID: addressID.String(),
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return appdata.Address{}, errors.WithStack(service.ErrAddressNotFound)
+		}
+		return appdata.Address{}, errors.WithStack(err)
+	}
+
+	userID, err := uuid.Parse(response.UserID)
+	if err != nil {
+		return appdata.Address{}, errors.WithStack(err)
+	}
+	return appdata.Address{
+		ID:         addressID,
+		UserID:     userID,
+		Recipient:  response.Recipient,
+		Phone:      response.Phone,
+		Country:    response.Country,
+		City:       response.City,
+		Street:     response.Street,
+		PostalCode: response.PostalCode,
+	}, nil
+}
@@ -9,6 +9,7 @@ import (
 	"gitea.xscloud.ru/xscloud/golib/pkg/application/logging"
 	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/amqp"
 
+	"order/pkg/order/domain/model"
 	"order/pkg/order/infrastructure/temporal"
 )
 
@@ -34,8 +35,32 @@ func (t *amqpTransport) Handler() amqp.Handler {
 	return t.withLog(t.handle)
 }
 
-func (t *amqpTransport) handle(_ context.Context, _ amqp.Delivery) error {
-	return nil
+func (t *amqpTransport) handle(ctx context.Context, delivery amqp.Delivery) error {
+	switch delivery.Type {
+	case model.UserDeleted{}.Type():
+		var e model.UserDeleted
+		err := json.Unmarshal(delivery.Body, &e)
+		if err != nil {
+			return err
+		}
+		return t.workflowService.RunUserDeletedWorkflow(ctx, delivery.CorrelationID, e)
+	case model.ProductRemoved{}.Type():
+		var e model.ProductRemoved
+		err := json.Unmarshal(delivery.Body, &e)
+		if err != nil {
+			return err
+		}
+		return t.workflowService.RunProductRemovedWorkflow(ctx, delivery.CorrelationID, e)
+	case model.PaymentStatusChanged{}.Type():
+		var e model.PaymentStatusChanged
+		err := json.Unmarshal(delivery.Body, &e)
+		if err != nil {
+			return err
+		}
+		return t.workflowService.RunPaymentStatusChangedWorkflow(ctx, delivery.CorrelationID, e)
+	default:
+		return errUnhandledDelivery
+	}
 }
 
 func (t *amqpTransport) withLog(handler amqp.Handler) amqp.Handler {
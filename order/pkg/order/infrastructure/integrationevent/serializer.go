@@ -1,7 +1,12 @@
 package integrationevent
 
 import (
+	"encoding/json"
+
 	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+	"github.com/pkg/errors"
+
+	"order/pkg/order/domain/model"
 )
 
 func NewEventSerializer() outbox.EventSerializer[outbox.Event] {
@@ -10,6 +15,207 @@ func NewEventSerializer() outbox.EventSerializer[outbox.Event] {
 
 type eventSerializer struct{}
 
-func (s eventSerializer) Serialize(_ outbox.Event) (string, error) {
-	return "", nil
+func (s eventSerializer) Serialize(event outbox.Event) (string, error) {
+	switch e := event.(type) {
+	case model.OrderCreated:
+		b, err := json.Marshal(OrderCreated{
+			OrderID:    e.OrderID.String(),
+			CustomerID: e.CustomerID.String(),
+		})
+		return string(b), errors.WithStack(err)
+	case model.OrderItemsChanged:
+		b, err := json.Marshal(OrderItemsChanged{
+			OrderID:      e.OrderID.String(),
+			AddedItems:   toStrings(e.AddedItems),
+			RemovedItems: toStrings(e.RemovedItems),
+		})
+		return string(b), errors.WithStack(err)
+	case model.OrderRemoved:
+		b, err := json.Marshal(OrderRemoved{
+			OrderID: e.OrderID.String(),
+		})
+		return string(b), errors.WithStack(err)
+	case model.OrderStatusChanged:
+		b, err := json.Marshal(OrderStatusChanged{
+			OrderID:    e.OrderID.String(),
+			CustomerID: e.CustomerID.String(),
+			From:       int(e.From),
+			To:         int(e.To),
+		})
+		return string(b), errors.WithStack(err)
+	case model.OrderPromoCodeApplied:
+		b, err := json.Marshal(OrderPromoCodeApplied{
+			OrderID:     e.OrderID.String(),
+			PromotionID: e.PromotionID.String(),
+			Code:        e.Code,
+			Discount:    e.Discount,
+		})
+		return string(b), errors.WithStack(err)
+	case model.OrderItemsUnfulfilled:
+		items := make([]UnfulfilledItem, len(e.Items))
+		for i, item := range e.Items {
+			items[i] = UnfulfilledItem{
+				ProductID: item.ProductID.String(),
+				Count:     item.Count,
+				Reason:    item.Reason,
+			}
+		}
+		b, err := json.Marshal(OrderItemsUnfulfilled{
+			OrderID: e.OrderID.String(),
+			Items:   items,
+		})
+		return string(b), errors.WithStack(err)
+	case model.OrderBackorderCreated:
+		b, err := json.Marshal(OrderBackorderCreated{
+			OrderID:     e.OrderID.String(),
+			BackorderID: e.BackorderID.String(),
+		})
+		return string(b), errors.WithStack(err)
+	case model.OrderReturnRequested:
+		items := make([]OrderReturnItem, len(e.Items))
+		for i, item := range e.Items {
+			items[i] = OrderReturnItem{
+				ProductID: item.ProductID.String(),
+				Count:     item.Count,
+			}
+		}
+		b, err := json.Marshal(OrderReturnRequested{
+			ReturnID:   e.ReturnID.String(),
+			OrderID:    e.OrderID.String(),
+			CustomerID: e.CustomerID.String(),
+			Items:      items,
+			Reason:     e.Reason,
+		})
+		return string(b), errors.WithStack(err)
+	case model.OrderReturnApproved:
+		b, err := json.Marshal(OrderReturnApproved{
+			ReturnID:   e.ReturnID.String(),
+			OrderID:    e.OrderID.String(),
+			CustomerID: e.CustomerID.String(),
+		})
+		return string(b), errors.WithStack(err)
+	case model.OrderReturnRejected:
+		b, err := json.Marshal(OrderReturnRejected{
+			ReturnID:   e.ReturnID.String(),
+			OrderID:    e.OrderID.String(),
+			CustomerID: e.CustomerID.String(),
+			Reason:     e.Reason,
+		})
+		return string(b), errors.WithStack(err)
+	case model.OrderReturnCompleted:
+		b, err := json.Marshal(OrderReturnCompleted{
+			ReturnID:     e.ReturnID.String(),
+			OrderID:      e.OrderID.String(),
+			CustomerID:   e.CustomerID.String(),
+			RefundAmount: e.RefundAmount,
+		})
+		return string(b), errors.WithStack(err)
+	case model.ScheduledOrderFailed:
+		event := ScheduledOrderFailed{
+			TemplateID: e.TemplateID.String(),
+			CustomerID: e.CustomerID.String(),
+			Reason:     e.Reason,
+		}
+		if e.OrderID != nil {
+			orderID := e.OrderID.String()
+			event.OrderID = &orderID
+		}
+		b, err := json.Marshal(event)
+		return string(b), errors.WithStack(err)
+	default:
+		return "", errors.Errorf("unknown event %q", event.Type())
+	}
+}
+
+type OrderCreated struct {
+	OrderID    string `json:"order_id"`
+	CustomerID string `json:"customer_id"`
+}
+
+type OrderItemsChanged struct {
+	OrderID      string   `json:"order_id"`
+	AddedItems   []string `json:"added_items,omitempty"`
+	RemovedItems []string `json:"removed_items,omitempty"`
+}
+
+type OrderRemoved struct {
+	OrderID string `json:"order_id"`
+}
+
+type OrderStatusChanged struct {
+	OrderID    string `json:"order_id"`
+	CustomerID string `json:"customer_id"`
+	From       int    `json:"from"`
+	To         int    `json:"to"`
+}
+
+type OrderPromoCodeApplied struct {
+	OrderID     string  `json:"order_id"`
+	PromotionID string  `json:"promotion_id"`
+	Code        string  `json:"code"`
+	Discount    float64 `json:"discount"`
+}
+
+type OrderItemsUnfulfilled struct {
+	OrderID string            `json:"order_id"`
+	Items   []UnfulfilledItem `json:"items"`
+}
+
+type UnfulfilledItem struct {
+	ProductID string `json:"product_id"`
+	Count     int    `json:"count"`
+	Reason    string `json:"reason"`
+}
+
+type OrderBackorderCreated struct {
+	OrderID     string `json:"order_id"`
+	BackorderID string `json:"backorder_id"`
+}
+
+type OrderReturnRequested struct {
+	ReturnID   string            `json:"return_id"`
+	OrderID    string            `json:"order_id"`
+	CustomerID string            `json:"customer_id"`
+	Items      []OrderReturnItem `json:"items"`
+	Reason     string            `json:"reason"`
+}
+
+type OrderReturnItem struct {
+	ProductID string `json:"product_id"`
+	Count     int    `json:"count"`
+}
+
+type OrderReturnApproved struct {
+	ReturnID   string `json:"return_id"`
+	OrderID    string `json:"order_id"`
+	CustomerID string `json:"customer_id"`
+}
+
+type OrderReturnRejected struct {
+	ReturnID   string `json:"return_id"`
+	OrderID    string `json:"order_id"`
+	CustomerID string `json:"customer_id"`
+	Reason     string `json:"reason"`
+}
+
+type OrderReturnCompleted struct {
+	ReturnID     string  `json:"return_id"`
+	OrderID      string  `json:"order_id"`
+	CustomerID   string  `json:"customer_id"`
+	RefundAmount float64 `json:"refund_amount"`
+}
+
+type ScheduledOrderFailed struct {
+	TemplateID string  `json:"template_id"`
+	CustomerID string  `json:"customer_id"`
+	OrderID    *string `json:"order_id,omitempty"`
+	Reason     string  `json:"reason"`
+}
+
+func toStrings[T interface{ String() string }](values []T) []string {
+	result := make([]string, len(values))
+	for i, v := range values {
+		result[i] = v.String()
+	}
+	return result
 }
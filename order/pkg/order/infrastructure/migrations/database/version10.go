@@ -0,0 +1,37 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion10(client mysql.ClientContext) migrator.Migration {
+	return &version10{
+		client: client,
+	}
+}
+
+type version10 struct {
+	client mysql.ClientContext
+}
+
+func (v version10) Version() int64 {
+	return 10
+}
+
+func (v version10) Description() string {
+	return "Add promotion columns to 'orders' table"
+}
+
+func (v version10) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		ALTER TABLE orders
+		    ADD COLUMN promotion_id       VARCHAR(64),
+		    ADD COLUMN discount           DECIMAL(10,2) NOT NULL DEFAULT 0,
+		    ADD COLUMN promotion_redeemed BOOLEAN       NOT NULL DEFAULT FALSE
+	`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion11(client mysql.ClientContext) migrator.Migration {
+	return &version11{
+		client: client,
+	}
+}
+
+type version11 struct {
+	client mysql.ClientContext
+}
+
+func (v version11) Version() int64 {
+	return 11
+}
+
+func (v version11) Description() string {
+	return "Create 'order_templates' and 'order_template_items' tables"
+}
+
+func (v version11) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS order_templates
+		(
+		    template_id       VARCHAR(64)  NOT NULL,
+		    customer_id       VARCHAR(64)  NOT NULL,
+		    scheduled         BOOLEAN      NOT NULL DEFAULT FALSE,
+		    cron_expression   VARCHAR(255) NOT NULL DEFAULT '',
+		    schedule_interval BIGINT       NOT NULL DEFAULT 0,
+		    schedule_paused   BOOLEAN      NOT NULL DEFAULT FALSE,
+		    created_at        DATETIME     NOT NULL,
+		    updated_at        DATETIME     NOT NULL,
+		    PRIMARY KEY (template_id),
+		    INDEX order_templates_customer_id_idx (customer_id)
+		)
+		    ENGINE = InnoDB
+		    CHARACTER SET = utf8mb4
+		    COLLATE utf8mb4_unicode_ci
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS order_template_items
+		(
+		    template_id VARCHAR(64) NOT NULL,
+		    product_id  VARCHAR(64) NOT NULL,
+		    count       INT         NOT NULL,
+		    PRIMARY KEY (template_id, product_id)
+		)
+		    ENGINE = InnoDB
+		    CHARACTER SET = utf8mb4
+		    COLLATE utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion12(client mysql.ClientContext) migrator.Migration {
+	return &version12{
+		client: client,
+	}
+}
+
+type version12 struct {
+	client mysql.ClientContext
+}
+
+func (v version12) Version() int64 {
+	return 12
+}
+
+func (v version12) Description() string {
+	return "Add partial fulfilment columns to 'orders' table and create 'order_unfulfilled_items' table"
+}
+
+func (v version12) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		ALTER TABLE orders
+		    ADD COLUMN allow_partial   BOOLEAN     NOT NULL DEFAULT FALSE,
+		    ADD COLUMN split_backorder BOOLEAN     NOT NULL DEFAULT FALSE,
+		    ADD COLUMN parent_order_id VARCHAR(64),
+		    ADD COLUMN backorder_id    VARCHAR(64),
+		    ADD INDEX orders_parent_order_id_idx (parent_order_id)
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS order_unfulfilled_items
+		(
+		    order_id   VARCHAR(64)   NOT NULL,
+		    product_id VARCHAR(64)   NOT NULL,
+		    count      INT           NOT NULL,
+		    price      DECIMAL(10,2) NOT NULL,
+		    reason     VARCHAR(255)  NOT NULL,
+		    PRIMARY KEY (order_id, product_id)
+		)
+		    ENGINE = InnoDB
+		    CHARACTER SET = utf8mb4
+		    COLLATE utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion13(client mysql.ClientContext) migrator.Migration {
+	return &version13{
+		client: client,
+	}
+}
+
+type version13 struct {
+	client mysql.ClientContext
+}
+
+func (v version13) Version() int64 {
+	return 13
+}
+
+func (v version13) Description() string {
+	return "Create 'order_returns' and 'order_return_items' tables"
+}
+
+func (v version13) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS order_returns
+		(
+		    return_id        VARCHAR(64)   NOT NULL,
+		    order_id         VARCHAR(64)   NOT NULL,
+		    customer_id      VARCHAR(64)   NOT NULL,
+		    status           INT           NOT NULL,
+		    reason           VARCHAR(255)  NOT NULL,
+		    rejection_reason VARCHAR(255)  NOT NULL,
+		    refund_amount    DECIMAL(10,2) NOT NULL,
+		    created_at       DATETIME      NOT NULL,
+		    updated_at       DATETIME      NOT NULL,
+		    PRIMARY KEY (return_id),
+		    INDEX order_returns_order_id_idx (order_id)
+		)
+		    ENGINE = InnoDB
+		    CHARACTER SET = utf8mb4
+		    COLLATE utf8mb4_unicode_ci
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS order_return_items
+		(
+		    return_id  VARCHAR(64)   NOT NULL,
+		    product_id VARCHAR(64)   NOT NULL,
+		    count      INT           NOT NULL,
+		    price      DECIMAL(10,2) NOT NULL,
+		    PRIMARY KEY (return_id, product_id)
+		)
+		    ENGINE = InnoDB
+		    CHARACTER SET = utf8mb4
+		    COLLATE utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
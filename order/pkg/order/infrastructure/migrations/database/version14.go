@@ -0,0 +1,56 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion14(client mysql.ClientContext) migrator.Migration {
+	return &version14{
+		client: client,
+	}
+}
+
+type version14 struct {
+	client mysql.ClientContext
+}
+
+func (v version14) Version() int64 {
+	return 14
+}
+
+func (v version14) Description() string {
+	return "Add delivery columns to 'orders' table and create 'order_shipping_addresses' table"
+}
+
+func (v version14) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		ALTER TABLE orders
+		    ADD COLUMN delivery_method INT           NOT NULL DEFAULT 0,
+		    ADD COLUMN delivery_fee    DECIMAL(10,2) NOT NULL DEFAULT 0
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS order_shipping_addresses
+		(
+		    order_id    VARCHAR(64)  NOT NULL,
+		    recipient   VARCHAR(255) NOT NULL,
+		    phone       VARCHAR(32)  NOT NULL,
+		    country     VARCHAR(255) NOT NULL,
+		    city        VARCHAR(255) NOT NULL,
+		    street      VARCHAR(255) NOT NULL,
+		    postal_code VARCHAR(32)  NOT NULL,
+		    PRIMARY KEY (order_id)
+		)
+		    ENGINE = InnoDB
+		    CHARACTER SET = utf8mb4
+		    COLLATE utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion15(client mysql.ClientContext) migrator.Migration {
+	return &version15{
+		client: client,
+	}
+}
+
+type version15 struct {
+	client mysql.ClientContext
+}
+
+func (v version15) Version() int64 {
+	return 15
+}
+
+func (v version15) Description() string {
+	return "Add 'payment_id' column to 'orders' table"
+}
+
+func (v version15) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `ALTER TABLE orders ADD COLUMN payment_id VARCHAR(64)`)
+	return errors.WithStack(err)
+}
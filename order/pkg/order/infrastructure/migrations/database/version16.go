@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion16(client mysql.ClientContext) migrator.Migration {
+	return &version16{
+		client: client,
+	}
+}
+
+type version16 struct {
+	client mysql.ClientContext
+}
+
+func (v version16) Version() int64 {
+	return 16
+}
+
+func (v version16) Description() string {
+	return "Add 'hold_payment' column to 'orders' table"
+}
+
+func (v version16) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `ALTER TABLE orders ADD COLUMN hold_payment BOOLEAN NOT NULL DEFAULT FALSE`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,34 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion3(client mysql.ClientContext) migrator.Migration {
+	return &version3{
+		client: client,
+	}
+}
+
+type version3 struct {
+	client mysql.ClientContext
+}
+
+func (v version3) Version() int64 {
+	return 3
+}
+
+func (v version3) Description() string {
+	return "Add customer index to 'orders' table"
+}
+
+func (v version3) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		ALTER TABLE orders ADD INDEX orders_customer_id_order_id_idx (customer_id, order_id)
+	`)
+	return errors.WithStack(err)
+}
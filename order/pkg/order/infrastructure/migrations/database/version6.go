@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion6(client mysql.ClientContext) migrator.Migration {
+	return &version6{
+		client: client,
+	}
+}
+
+type version6 struct {
+	client mysql.ClientContext
+}
+
+func (v version6) Version() int64 {
+	return 6
+}
+
+func (v version6) Description() string {
+	return "Create 'order_history' table"
+}
+
+func (v version6) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS order_history
+		(
+		    history_id  BIGINT       NOT NULL AUTO_INCREMENT,
+		    order_id    VARCHAR(64)  NOT NULL,
+		    event_type  VARCHAR(64)  NOT NULL,
+		    actor       VARCHAR(255) NOT NULL,
+		    from_status INT,
+		    to_status   INT,
+		    details     TEXT         NOT NULL,
+		    created_at  DATETIME(6)  NOT NULL,
+		    PRIMARY KEY (history_id),
+		    INDEX order_history_order_id_idx (order_id, history_id)
+		)
+		    ENGINE = InnoDB
+		    CHARACTER SET = utf8mb4
+		    COLLATE utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion7(client mysql.ClientContext) migrator.Migration {
+	return &version7{
+		client: client,
+	}
+}
+
+type version7 struct {
+	client mysql.ClientContext
+}
+
+func (v version7) Version() int64 {
+	return 7
+}
+
+func (v version7) Description() string {
+	return "Add 'version' column to 'orders' table"
+}
+
+func (v version7) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `ALTER TABLE orders ADD COLUMN version INT NOT NULL DEFAULT 1`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion8(client mysql.ClientContext) migrator.Migration {
+	return &version8{
+		client: client,
+	}
+}
+
+type version8 struct {
+	client mysql.ClientContext
+}
+
+func (v version8) Version() int64 {
+	return 8
+}
+
+func (v version8) Description() string {
+	return "Create 'carts' and 'cart_items' tables"
+}
+
+func (v version8) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS carts
+		(
+		    cart_id     VARCHAR(64)  NOT NULL,
+		    customer_id VARCHAR(64)  NOT NULL,
+		    created_at  DATETIME     NOT NULL,
+		    updated_at  DATETIME     NOT NULL,
+		    version     INT          NOT NULL,
+		    PRIMARY KEY (cart_id),
+		    UNIQUE INDEX carts_customer_id_idx (customer_id),
+		    INDEX carts_updated_at_idx (updated_at)
+		)
+		    ENGINE = InnoDB
+		    CHARACTER SET = utf8mb4
+		    COLLATE utf8mb4_unicode_ci
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS cart_items
+		(
+		    cart_id    VARCHAR(64) NOT NULL,
+		    product_id VARCHAR(64) NOT NULL,
+		    count      INT         NOT NULL,
+		    PRIMARY KEY (cart_id, product_id)
+		)
+		    ENGINE = InnoDB
+		    CHARACTER SET = utf8mb4
+		    COLLATE utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
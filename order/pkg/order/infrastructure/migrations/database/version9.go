@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion9(client mysql.ClientContext) migrator.Migration {
+	return &version9{
+		client: client,
+	}
+}
+
+type version9 struct {
+	client mysql.ClientContext
+}
+
+func (v version9) Version() int64 {
+	return 9
+}
+
+func (v version9) Description() string {
+	return "Create 'promotions' and 'promotion_redemptions' tables"
+}
+
+func (v version9) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS promotions
+		(
+		    promotion_id       VARCHAR(64)   NOT NULL,
+		    code               VARCHAR(64)   NOT NULL,
+		    kind               INT           NOT NULL,
+		    value              DECIMAL(10,2) NOT NULL,
+		    min_order_total    DECIMAL(10,2) NOT NULL,
+		    per_customer_limit INT           NOT NULL,
+		    valid_from         DATETIME,
+		    valid_to           DATETIME,
+		    created_at         DATETIME      NOT NULL,
+		    PRIMARY KEY (promotion_id),
+		    UNIQUE INDEX promotions_code_idx (code)
+		)
+		    ENGINE = InnoDB
+		    CHARACTER SET = utf8mb4
+		    COLLATE utf8mb4_unicode_ci
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS promotion_redemptions
+		(
+		    promotion_id VARCHAR(64) NOT NULL,
+		    order_id     VARCHAR(64) NOT NULL,
+		    customer_id  VARCHAR(64) NOT NULL,
+		    created_at   DATETIME    NOT NULL,
+		    PRIMARY KEY (promotion_id, order_id),
+		    INDEX promotion_redemptions_customer_idx (promotion_id, customer_id)
+		)
+		    ENGINE = InnoDB
+		    CHARACTER SET = utf8mb4
+		    COLLATE utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
@@ -3,6 +3,8 @@ package query
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
+	"strings"
 	"time"
 
 	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
@@ -14,6 +16,11 @@ import (
 	"order/pkg/order/domain/model"
 )
 
+const (
+	defaultListLimit = 50
+	maxListLimit     = 500
+)
+
 func NewOrderQueryService(client mysql.ClientContext) query.OrderQueryService {
 	return &orderQueryService{
 		client: client,
@@ -24,20 +31,37 @@ type orderQueryService struct {
 	client mysql.ClientContext
 }
 
-func (o *orderQueryService) FindUser(ctx context.Context, orderID uuid.UUID) (*data.Order, error) {
-	orderRow := struct {
-		ID         uuid.UUID           `db:"order_id"`
-		CustomerID uuid.UUID           `db:"customer_id"`
-		Status     int                 `db:"status"`
-		CreatedAt  time.Time           `db:"created_at"`
-		UpdatedAt  time.Time           `db:"updated_at"`
-		DeletedAt  sql.Null[time.Time] `db:"deleted_at"`
-	}{}
+type orderRow struct {
+	ID          uuid.UUID           `db:"order_id"`
+	CustomerID  uuid.UUID           `db:"customer_id"`
+	Status      int                 `db:"status"`
+	CreatedAt   time.Time           `db:"created_at"`
+	UpdatedAt   time.Time           `db:"updated_at"`
+	DeletedAt   sql.Null[time.Time] `db:"deleted_at"`
+	PromotionID sql.Null[uuid.UUID] `db:"promotion_id"`
+	Discount    float64             `db:"discount"`
+
+	AllowPartial   bool                `db:"allow_partial"`
+	SplitBackorder bool                `db:"split_backorder"`
+	HoldPayment    bool                `db:"hold_payment"`
+	ParentOrderID  sql.Null[uuid.UUID] `db:"parent_order_id"`
+	BackorderID    sql.Null[uuid.UUID] `db:"backorder_id"`
+
+	DeliveryMethod int     `db:"delivery_method"`
+	DeliveryFee    float64 `db:"delivery_fee"`
+
+	PaymentID sql.Null[uuid.UUID] `db:"payment_id"`
+}
+
+const orderColumns = `order_id, customer_id, status, created_at, updated_at, deleted_at, promotion_id, discount,
+	allow_partial, split_backorder, hold_payment, parent_order_id, backorder_id, delivery_method, delivery_fee, payment_id`
 
+func (o *orderQueryService) FindOrder(ctx context.Context, orderID uuid.UUID) (*data.Order, error) {
+	var row orderRow
 	err := o.client.GetContext(
 		ctx,
-		&orderRow,
-		`SELECT order_id, customer_id, status, created_at, updated_at, deleted_at FROM orders WHERE order_id = ?`,
+		&row,
+		`SELECT `+orderColumns+` FROM orders WHERE order_id = ?`,
 		orderID,
 	)
 	if err != nil {
@@ -47,56 +71,283 @@ func (o *orderQueryService) FindUser(ctx context.Context, orderID uuid.UUID) (*d
 		return nil, errors.WithStack(err)
 	}
 
-	items, err := o.loadOrderItems(ctx, orderRow.ID)
+	orders, err := o.toOrders(ctx, []orderRow{row})
+	if err != nil {
+		return nil, err
+	}
+	return &orders[0], nil
+}
+
+func (o *orderQueryService) ListOrders(ctx context.Context, spec data.ListOrdersSpec) (*data.OrderPage, error) {
+	limit := spec.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	limit = min(limit, maxListLimit)
+
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if spec.Cursor != "" {
+		lastOrderID, err := decodeCursor(spec.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, "order_id > ?")
+		args = append(args, lastOrderID)
+	}
+	if spec.CustomerID != nil {
+		conditions = append(conditions, "customer_id = ?")
+		args = append(args, *spec.CustomerID)
+	}
+	if spec.ProductID != nil {
+		conditions = append(conditions, "order_id IN (SELECT order_id FROM order_items WHERE product_id = ?)")
+		args = append(args, *spec.ProductID)
+	}
+	if len(spec.Statuses) > 0 {
+		conditions = append(conditions, "status IN (?"+strings.Repeat(", ?", len(spec.Statuses)-1)+")")
+		for _, status := range spec.Statuses {
+			args = append(args, int(status))
+		}
+	}
+	if spec.CreatedFrom != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *spec.CreatedFrom)
+	}
+	if spec.CreatedTo != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, *spec.CreatedTo)
+	}
+	if spec.UpdatedFrom != nil {
+		conditions = append(conditions, "updated_at >= ?")
+		args = append(args, *spec.UpdatedFrom)
+	}
+	if spec.UpdatedTo != nil {
+		conditions = append(conditions, "updated_at < ?")
+		args = append(args, *spec.UpdatedTo)
+	}
+	if !spec.IncludeDeleted {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+
+	sqlQuery := `SELECT ` + orderColumns + ` FROM orders`
+	if len(conditions) > 0 {
+		sqlQuery += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	// order_id is UUIDv7, so ordering by it keeps orders in creation order and gives a stable cursor
+	sqlQuery += ` ORDER BY order_id LIMIT ?`
+	args = append(args, limit+1)
+
+	var rows []orderRow
+	err := o.client.SelectContext(ctx, &rows, sqlQuery, args...)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 
-	return &data.Order{
-		ID:         orderRow.ID,
-		CustomerID: orderRow.CustomerID,
-		Status:     data.OrderStatus(orderRow.Status),
-		Items:      items,
-		CreatedAt:  orderRow.CreatedAt,
-		UpdatedAt:  orderRow.UpdatedAt,
-		DeletedAt:  fromSQLNull(orderRow.DeletedAt),
-	}, nil
+	page := &data.OrderPage{}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		page.NextCursor = encodeCursor(rows[len(rows)-1].ID)
+	}
+
+	page.Orders = []data.Order{}
+	if len(rows) == 0 {
+		return page, nil
+	}
+	page.Orders, err = o.toOrders(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// toOrders loads items, unfulfilled items and shipping addresses of all rows at once
+func (o *orderQueryService) toOrders(ctx context.Context, rows []orderRow) ([]data.Order, error) {
+	orderIDs := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		orderIDs[i] = row.ID
+	}
+	items, err := o.loadOrderItems(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+	unfulfilledItems, err := o.loadUnfulfilledItems(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+	shippingAddresses, err := o.loadShippingAddresses(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]data.Order, len(rows))
+	for i, row := range rows {
+		orderItems := items[row.ID]
+		if orderItems == nil {
+			orderItems = []data.OrderItem{}
+		}
+		orderUnfulfilledItems := unfulfilledItems[row.ID]
+		if orderUnfulfilledItems == nil {
+			orderUnfulfilledItems = []data.UnfulfilledItem{}
+		}
+		orders[i] = data.Order{
+			ID:          row.ID,
+			CustomerID:  row.CustomerID,
+			Status:      data.OrderStatus(row.Status),
+			Items:       orderItems,
+			CreatedAt:   row.CreatedAt,
+			UpdatedAt:   row.UpdatedAt,
+			DeletedAt:   fromSQLNull(row.DeletedAt),
+			PromotionID: fromSQLNull(row.PromotionID),
+			Discount:    row.Discount,
+
+			AllowPartial:     row.AllowPartial,
+			SplitBackorder:   row.SplitBackorder,
+			HoldPayment:      row.HoldPayment,
+			UnfulfilledItems: orderUnfulfilledItems,
+			ParentOrderID:    fromSQLNull(row.ParentOrderID),
+			BackorderID:      fromSQLNull(row.BackorderID),
+
+			ShippingAddress: shippingAddresses[row.ID],
+			DeliveryMethod:  data.DeliveryMethod(row.DeliveryMethod),
+			DeliveryFee:     row.DeliveryFee,
+
+			PaymentID: fromSQLNull(row.PaymentID),
+		}
+	}
+	return orders, nil
 }
 
-func (o *orderQueryService) loadOrderItems(ctx context.Context, orderID uuid.UUID) ([]data.OrderItem, error) {
+func (o *orderQueryService) loadOrderItems(ctx context.Context, orderIDs []uuid.UUID) (map[uuid.UUID][]data.OrderItem, error) {
 	var itemRows []struct {
 		OrderID    uuid.UUID `db:"order_id"`
 		ProductID  uuid.UUID `db:"product_id"`
 		Count      int       `db:"count"`
+		Price      float64   `db:"price"`
 		TotalPrice float64   `db:"total_price"`
 	}
 
+	inClause, args := orderIDsIn(orderIDs)
 	err := o.client.SelectContext(
 		ctx,
 		&itemRows,
-		`SELECT order_id, product_id, count, total_price FROM order_items WHERE order_id = ?`,
-		orderID,
+		`SELECT order_id, product_id, count, price, total_price FROM order_items WHERE order_id `+inClause,
+		args...,
 	)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 
-	items := make([]data.OrderItem, len(itemRows))
-	for i, row := range itemRows {
-		items[i] = data.OrderItem{
+	items := make(map[uuid.UUID][]data.OrderItem, len(orderIDs))
+	for _, row := range itemRows {
+		items[row.OrderID] = append(items[row.OrderID], data.OrderItem{
 			OrderID:    row.OrderID,
 			ProductID:  row.ProductID,
 			Count:      row.Count,
+			Price:      row.Price,
 			TotalPrice: row.TotalPrice,
-		}
+		})
 	}
 
 	return items, nil
 }
 
+func (o *orderQueryService) loadUnfulfilledItems(ctx context.Context, orderIDs []uuid.UUID) (map[uuid.UUID][]data.UnfulfilledItem, error) {
+	var itemRows []struct {
+		OrderID   uuid.UUID `db:"order_id"`
+		ProductID uuid.UUID `db:"product_id"`
+		Count     int       `db:"count"`
+		Price     float64   `db:"price"`
+		Reason    string    `db:"reason"`
+	}
+
+	inClause, args := orderIDsIn(orderIDs)
+	err := o.client.SelectContext(
+		ctx,
+		&itemRows,
+		`SELECT order_id, product_id, count, price, reason FROM order_unfulfilled_items WHERE order_id `+inClause+` ORDER BY product_id`,
+		args...,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	items := make(map[uuid.UUID][]data.UnfulfilledItem, len(orderIDs))
+	for _, row := range itemRows {
+		items[row.OrderID] = append(items[row.OrderID], data.UnfulfilledItem{
+			ProductID: row.ProductID,
+			Count:     row.Count,
+			Price:     row.Price,
+			Reason:    row.Reason,
+		})
+	}
+	return items, nil
+}
+
+func (o *orderQueryService) loadShippingAddresses(ctx context.Context, orderIDs []uuid.UUID) (map[uuid.UUID]*data.ShippingAddress, error) {
+	var rows []struct {
+		OrderID    uuid.UUID `db:"order_id"`
+		Recipient  string    `db:"recipient"`
+		Phone      string    `db:"phone"`
+		Country    string    `db:"country"`
+		City       string    `db:"city"`
+		Street     string    `db:"street"`
+		PostalCode string    `db:"postal_code"`
+	}
+
+	inClause, args := orderIDsIn(orderIDs)
+	err := o.client.SelectContext(
+		ctx,
+		&rows,
+		`SELECT order_id, recipient, phone, country, city, street, postal_code FROM order_shipping_addresses WHERE order_id `+inClause,
+		args...,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	addresses := make(map[uuid.UUID]*data.ShippingAddress, len(rows))
+	for _, row := range rows {
+		addresses[row.OrderID] = &data.ShippingAddress{
+			Recipient:  row.Recipient,
+			Phone:      row.Phone,
+			Country:    row.Country,
+			City:       row.City,
+			Street:     row.Street,
+			PostalCode: row.PostalCode,
+		}
+	}
+	return addresses, nil
+}
+
+func orderIDsIn(orderIDs []uuid.UUID) (string, []interface{}) {
+	args := make([]interface{}, len(orderIDs))
+	for i, orderID := range orderIDs {
+		args[i] = orderID
+	}
+	return "IN (?" + strings.Repeat(", ?", len(orderIDs)-1) + ")", args
+}
+
 func fromSQLNull[T any](v sql.Null[T]) *T {
 	if v.Valid {
 		return &v.V
 	}
 	return nil
 }
+
+func encodeCursor(lastOrderID uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString(lastOrderID[:])
+}
+
+func decodeCursor(cursor string) (uuid.UUID, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return uuid.Nil, errors.WithStack(query.ErrInvalidCursor)
+	}
+	id, err := uuid.FromBytes(b)
+	if err != nil {
+		return uuid.Nil, errors.WithStack(query.ErrInvalidCursor)
+	}
+	return id, nil
+}
@@ -0,0 +1,68 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"order/pkg/order/app/data"
+	"order/pkg/order/domain/model"
+)
+
+func (o *orderQueryService) GetOrderHistory(ctx context.Context, orderID uuid.UUID) ([]data.OrderHistoryEntry, error) {
+	var rows []struct {
+		OrderID    uuid.UUID     `db:"order_id"`
+		EventType  string        `db:"event_type"`
+		Actor      string        `db:"actor"`
+		FromStatus sql.Null[int] `db:"from_status"`
+		ToStatus   sql.Null[int] `db:"to_status"`
+		Details    string        `db:"details"`
+		CreatedAt  time.Time     `db:"created_at"`
+	}
+	err := o.client.SelectContext(
+		ctx,
+		&rows,
+		`SELECT order_id, event_type, actor, from_status, to_status, details, created_at FROM order_history WHERE order_id = ? ORDER BY history_id`,
+		orderID,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if len(rows) == 0 {
+		// Orders created before history was introduced have no entries
+		var exists bool
+		err = o.client.GetContext(ctx, &exists, `SELECT EXISTS(SELECT 1 FROM orders WHERE order_id = ?)`, orderID)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if !exists {
+			return nil, errors.WithStack(model.ErrOrderNotFound)
+		}
+	}
+
+	entries := make([]data.OrderHistoryEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = data.OrderHistoryEntry{
+			OrderID:    row.OrderID,
+			EventType:  row.EventType,
+			Actor:      row.Actor,
+			FromStatus: toOrderStatus(row.FromStatus),
+			ToStatus:   toOrderStatus(row.ToStatus),
+			Details:    row.Details,
+			CreatedAt:  row.CreatedAt,
+		}
+	}
+	return entries, nil
+}
+
+func toOrderStatus(status sql.Null[int]) *data.OrderStatus {
+	if !status.Valid {
+		return nil
+	}
+	s := data.OrderStatus(status.V)
+	return &s
+}
@@ -0,0 +1,74 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"order/pkg/order/app/data"
+)
+
+func (o *orderQueryService) ListOrderSchedules(ctx context.Context, customerID uuid.UUID) ([]data.OrderTemplate, error) {
+	var rows []struct {
+		ID               uuid.UUID     `db:"template_id"`
+		CustomerID       uuid.UUID     `db:"customer_id"`
+		CronExpression   string        `db:"cron_expression"`
+		ScheduleInterval time.Duration `db:"schedule_interval"`
+		SchedulePaused   bool          `db:"schedule_paused"`
+		CreatedAt        time.Time     `db:"created_at"`
+		UpdatedAt        time.Time     `db:"updated_at"`
+	}
+	err := o.client.SelectContext(
+		ctx,
+		&rows,
+		`
+		SELECT template_id, customer_id, cron_expression, schedule_interval, schedule_paused, created_at, updated_at
+		FROM order_templates WHERE customer_id = ? AND scheduled = TRUE
+		ORDER BY template_id
+		`,
+		customerID,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	templates := make([]data.OrderTemplate, 0, len(rows))
+	for _, row := range rows {
+		var itemRows []struct {
+			ProductID uuid.UUID `db:"product_id"`
+			Count     int       `db:"count"`
+		}
+		err = o.client.SelectContext(
+			ctx,
+			&itemRows,
+			`SELECT product_id, count FROM order_template_items WHERE template_id = ? ORDER BY product_id`,
+			row.ID,
+		)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		items := make([]data.OrderTemplateItem, len(itemRows))
+		for i, item := range itemRows {
+			items[i] = data.OrderTemplateItem{
+				ProductID: item.ProductID,
+				Count:     item.Count,
+			}
+		}
+		templates = append(templates, data.OrderTemplate{
+			ID:         row.ID,
+			CustomerID: row.CustomerID,
+			Items:      items,
+			Schedule: &data.OrderSchedule{
+				CronExpression: row.CronExpression,
+				Interval:       row.ScheduleInterval,
+				Paused:         row.SchedulePaused,
+			},
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+		})
+	}
+	return templates, nil
+}
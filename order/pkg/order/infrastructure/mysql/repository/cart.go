@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"order/pkg/order/domain/model"
+)
+
+func NewCartRepository(ctx context.Context, client mysql.ClientContext) model.CartRepository {
+	return &cartRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type cartRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+func (c *cartRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (c *cartRepository) Store(cart *model.Cart) error {
+	if cart.Version == 0 {
+		_, err := c.client.ExecContext(c.ctx,
+			`INSERT INTO carts (cart_id, customer_id, created_at, updated_at, version) VALUES (?, ?, ?, ?, 1)`,
+			cart.ID,
+			cart.CustomerID,
+			cart.CreatedAt,
+			cart.UpdatedAt,
+		)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	} else {
+		res, err := c.client.ExecContext(c.ctx,
+			`
+		UPDATE carts SET created_at = ?, updated_at = ?, version = version + 1
+		WHERE cart_id = ? AND version = ?
+		`,
+			cart.CreatedAt,
+			cart.UpdatedAt,
+			cart.ID,
+			cart.Version,
+		)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if rows == 0 {
+			return errors.WithStack(model.ErrCartVersionConflict)
+		}
+	}
+	cart.Version++
+
+	// Items are always replaced, so the last removed item is deleted as well
+	_, err := c.client.ExecContext(c.ctx, `DELETE FROM cart_items WHERE cart_id = ?`, cart.ID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for _, item := range cart.Items {
+		_, err = c.client.ExecContext(c.ctx,
+			`INSERT INTO cart_items (cart_id, product_id, count) VALUES (?, ?, ?)`,
+			cart.ID,
+			item.ProductID,
+			item.Count,
+		)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+func (c *cartRepository) FindByCustomerID(customerID uuid.UUID) (*model.Cart, error) {
+	cartRow := struct {
+		ID         uuid.UUID `db:"cart_id"`
+		CustomerID uuid.UUID `db:"customer_id"`
+		CreatedAt  time.Time `db:"created_at"`
+		UpdatedAt  time.Time `db:"updated_at"`
+		Version    int       `db:"version"`
+	}{}
+
+	err := c.client.GetContext(
+		c.ctx,
+		&cartRow,
+		`SELECT cart_id, customer_id, created_at, updated_at, version FROM carts WHERE customer_id = ?`,
+		customerID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(model.ErrCartNotFound)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	var itemRows []struct {
+		ProductID uuid.UUID `db:"product_id"`
+		Count     int       `db:"count"`
+	}
+	err = c.client.SelectContext(
+		c.ctx,
+		&itemRows,
+		`SELECT product_id, count FROM cart_items WHERE cart_id = ? ORDER BY product_id`,
+		cartRow.ID,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	items := make([]model.CartItem, len(itemRows))
+	for i, row := range itemRows {
+		items[i] = model.CartItem{
+			ProductID: row.ProductID,
+			Count:     row.Count,
+		}
+	}
+
+	return &model.Cart{
+		ID:         cartRow.ID,
+		CustomerID: cartRow.CustomerID,
+		Items:      items,
+		CreatedAt:  cartRow.CreatedAt,
+		UpdatedAt:  cartRow.UpdatedAt,
+		Version:    cartRow.Version,
+	}, nil
+}
+
+func (c *cartRepository) Remove(id uuid.UUID) error {
+	_, err := c.client.ExecContext(c.ctx, `DELETE FROM cart_items WHERE cart_id = ?`, id)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = c.client.ExecContext(c.ctx, `DELETE FROM carts WHERE cart_id = ?`, id)
+	return errors.WithStack(err)
+}
+
+func (c *cartRepository) RemoveInactive(updatedBefore time.Time) error {
+	// Single statement, so a cart changed concurrently never loses its items only
+	_, err := c.client.ExecContext(c.ctx,
+		`
+		DELETE carts, cart_items FROM carts
+		LEFT JOIN cart_items ON cart_items.cart_id = carts.cart_id
+		WHERE carts.updated_at < ?
+		`,
+		updatedBefore,
+	)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+
+	"order/pkg/common/idempotency"
+)
+
+func NewIdempotencyRepository(ctx context.Context, client mysql.ClientContext) idempotency.Repository {
+	return &idempotencyRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type idempotencyRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+func (r *idempotencyRepository) Find(scope, key string) (*idempotency.Record, error) {
+	row := struct {
+		Scope       string    `db:"scope"`
+		Key         string    `db:"idempotency_key"`
+		Fingerprint string    `db:"fingerprint"`
+		Response    string    `db:"response"`
+		CreatedAt   time.Time `db:"created_at"`
+	}{}
+
+	err := r.client.GetContext(
+		r.ctx,
+		&row,
+		`SELECT scope, idempotency_key, fingerprint, response, created_at FROM idempotency_keys WHERE scope = ? AND idempotency_key = ?`,
+		scope,
+		key,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(idempotency.ErrRecordNotFound)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	return &idempotency.Record{
+		Scope:       row.Scope,
+		Key:         row.Key,
+		Fingerprint: row.Fingerprint,
+		Response:    row.Response,
+		CreatedAt:   row.CreatedAt,
+	}, nil
+}
+
+func (r *idempotencyRepository) Store(record idempotency.Record) error {
+	_, err := r.client.ExecContext(r.ctx,
+		`
+	INSERT INTO idempotency_keys (scope, idempotency_key, fingerprint, response, created_at) VALUES (?, ?, ?, ?, ?)
+	ON DUPLICATE KEY UPDATE
+		fingerprint=VALUES(fingerprint),
+		response=VALUES(response),
+		created_at=VALUES(created_at)
+	`,
+		record.Scope,
+		record.Key,
+		record.Fingerprint,
+		record.Response,
+		record.CreatedAt,
+	)
+	return errors.WithStack(err)
+}
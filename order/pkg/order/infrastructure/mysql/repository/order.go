@@ -29,70 +29,153 @@ func (o *orderRepository) NextID() (uuid.UUID, error) {
 }
 
 func (o *orderRepository) Store(order *model.Order) error {
-	_, err := o.client.ExecContext(o.ctx,
-		`
-	INSERT INTO orders (order_id, customer_id, status, created_at, updated_at, deleted_at) VALUES (?, ?, ?, ?, ?, ?)
-	ON DUPLICATE KEY UPDATE
-		customer_id=VALUES(customer_id),
-		status=VALUES(status),
-		updated_at=VALUES(updated_at),
-		deleted_at=VALUES(deleted_at)
-	`,
-		order.ID,
-		order.CustomerID,
-		order.Status,
-		order.CreatedAt,
-		order.UpdatedAt,
-		toSQLNull(order.DeletedAt),
-	)
+	if order.Version == 0 {
+		_, err := o.client.ExecContext(o.ctx,
+			`
+		INSERT INTO orders (order_id, customer_id, status, created_at, updated_at, deleted_at, promotion_id, discount, promotion_redeemed,
+			allow_partial, split_backorder, hold_payment, parent_order_id, backorder_id, delivery_method, delivery_fee, payment_id, version)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 1)
+		`,
+			order.ID,
+			order.CustomerID,
+			order.Status,
+			order.CreatedAt,
+			order.UpdatedAt,
+			toSQLNull(order.DeletedAt),
+			toSQLNull(order.PromotionID),
+			order.Discount,
+			order.PromotionRedeemed,
+			order.AllowPartial,
+			order.SplitBackorder,
+			order.HoldPayment,
+			toSQLNull(order.ParentOrderID),
+			toSQLNull(order.BackorderID),
+			order.DeliveryMethod,
+			order.DeliveryFee,
+			toSQLNull(order.PaymentID),
+		)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	} else {
+		res, err := o.client.ExecContext(o.ctx,
+			`
+		UPDATE orders SET customer_id = ?, status = ?, updated_at = ?, deleted_at = ?,
+			promotion_id = ?, discount = ?, promotion_redeemed = ?,
+			allow_partial = ?, split_backorder = ?, hold_payment = ?, parent_order_id = ?, backorder_id = ?,
+			delivery_method = ?, delivery_fee = ?, payment_id = ?, version = version + 1
+		WHERE order_id = ? AND version = ?
+		`,
+			order.CustomerID,
+			order.Status,
+			order.UpdatedAt,
+			toSQLNull(order.DeletedAt),
+			toSQLNull(order.PromotionID),
+			order.Discount,
+			order.PromotionRedeemed,
+			order.AllowPartial,
+			order.SplitBackorder,
+			order.HoldPayment,
+			toSQLNull(order.ParentOrderID),
+			toSQLNull(order.BackorderID),
+			order.DeliveryMethod,
+			order.DeliveryFee,
+			toSQLNull(order.PaymentID),
+			order.ID,
+			order.Version,
+		)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if rows == 0 {
+			return errors.WithStack(model.ErrOrderVersionConflict)
+		}
+	}
+	order.Version++
+
+	// Old items are always deleted, so an order left without items keeps none of them
+	_, err := o.client.ExecContext(o.ctx, `DELETE FROM order_items WHERE order_id = ?`, order.ID)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	// Удаляем старые позиции и вставляем новые
-	if len(order.Items) > 0 {
-		_, err = o.client.ExecContext(o.ctx, `DELETE FROM order_items WHERE order_id = ?`, order.ID)
+	for _, item := range order.Items {
+		_, err = o.client.ExecContext(o.ctx,
+			`
+			INSERT INTO order_items (order_id, product_id, count, price, total_price) VALUES (?, ?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE
+				product_id=VALUES(product_id),
+				count=VALUES(count),
+				price=VALUES(price),
+				total_price=VALUES(total_price)
+			`,
+			item.OrderID,
+			item.ProductID,
+			item.Count,
+			item.Price,
+			item.TotalPrice,
+		)
 		if err != nil {
 			return errors.WithStack(err)
 		}
+	}
 
-		for _, item := range order.Items {
-			_, err = o.client.ExecContext(o.ctx,
-				`
-				INSERT INTO order_items (order_id, product_id, count, total_price) VALUES (?, ?, ?, ?)
-				ON DUPLICATE KEY UPDATE
-					product_id=VALUES(product_id),
-					count=VALUES(count),
-					total_price=VALUES(total_price)
-				`,
-				item.OrderID,
-				item.ProductID,
-				item.Count,
-				item.TotalPrice,
-			)
-			if err != nil {
-				return errors.WithStack(err)
-			}
+	_, err = o.client.ExecContext(o.ctx, `DELETE FROM order_unfulfilled_items WHERE order_id = ?`, order.ID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, item := range order.UnfulfilledItems {
+		_, err = o.client.ExecContext(o.ctx,
+			`INSERT INTO order_unfulfilled_items (order_id, product_id, count, price, reason) VALUES (?, ?, ?, ?, ?)`,
+			order.ID,
+			item.ProductID,
+			item.Count,
+			item.Price,
+			item.Reason,
+		)
+		if err != nil {
+			return errors.WithStack(err)
 		}
 	}
 
-	return nil
+	return o.storeShippingAddress(order)
 }
 
 func (o *orderRepository) Find(id uuid.UUID) (*model.Order, error) {
 	orderRow := struct {
-		ID         uuid.UUID           `db:"order_id"`
-		CustomerID uuid.UUID           `db:"customer_id"`
-		Status     int                 `db:"status"`
-		CreatedAt  time.Time           `db:"created_at"`
-		UpdatedAt  time.Time           `db:"updated_at"`
-		DeletedAt  sql.Null[time.Time] `db:"deleted_at"`
+		ID                uuid.UUID           `db:"order_id"`
+		CustomerID        uuid.UUID           `db:"customer_id"`
+		Status            int                 `db:"status"`
+		CreatedAt         time.Time           `db:"created_at"`
+		UpdatedAt         time.Time           `db:"updated_at"`
+		DeletedAt         sql.Null[time.Time] `db:"deleted_at"`
+		PromotionID       sql.Null[uuid.UUID] `db:"promotion_id"`
+		Discount          float64             `db:"discount"`
+		PromotionRedeemed bool                `db:"promotion_redeemed"`
+		AllowPartial      bool                `db:"allow_partial"`
+		SplitBackorder    bool                `db:"split_backorder"`
+		HoldPayment       bool                `db:"hold_payment"`
+		ParentOrderID     sql.Null[uuid.UUID] `db:"parent_order_id"`
+		BackorderID       sql.Null[uuid.UUID] `db:"backorder_id"`
+		DeliveryMethod    int                 `db:"delivery_method"`
+		DeliveryFee       float64             `db:"delivery_fee"`
+		PaymentID         sql.Null[uuid.UUID] `db:"payment_id"`
+		Version           int                 `db:"version"`
 	}{}
 
 	err := o.client.GetContext(
 		o.ctx,
 		&orderRow,
-		`SELECT order_id, customer_id, status, created_at, updated_at, deleted_at FROM orders WHERE order_id = ?`,
+		`
+		SELECT order_id, customer_id, status, created_at, updated_at, deleted_at, promotion_id, discount, promotion_redeemed,
+			allow_partial, split_backorder, hold_payment, parent_order_id, backorder_id, delivery_method, delivery_fee, payment_id, version
+		FROM orders WHERE order_id = ?
+		`,
 		id,
 	)
 	if err != nil {
@@ -107,14 +190,38 @@ func (o *orderRepository) Find(id uuid.UUID) (*model.Order, error) {
 		return nil, errors.WithStack(err)
 	}
 
+	unfulfilledItems, err := o.loadUnfulfilledItems(orderRow.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	shippingAddress, err := o.loadShippingAddress(orderRow.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &model.Order{
-		ID:         orderRow.ID,
-		CustomerID: orderRow.CustomerID,
-		Status:     model.OrderStatus(orderRow.Status),
-		Items:      items,
-		CreatedAt:  orderRow.CreatedAt,
-		UpdatedAt:  orderRow.UpdatedAt,
-		DeletedAt:  fromSQLNull(orderRow.DeletedAt),
+		ID:                orderRow.ID,
+		CustomerID:        orderRow.CustomerID,
+		Status:            model.OrderStatus(orderRow.Status),
+		Items:             items,
+		CreatedAt:         orderRow.CreatedAt,
+		UpdatedAt:         orderRow.UpdatedAt,
+		DeletedAt:         fromSQLNull(orderRow.DeletedAt),
+		PromotionID:       fromSQLNull(orderRow.PromotionID),
+		Discount:          orderRow.Discount,
+		PromotionRedeemed: orderRow.PromotionRedeemed,
+		AllowPartial:      orderRow.AllowPartial,
+		SplitBackorder:    orderRow.SplitBackorder,
+		HoldPayment:       orderRow.HoldPayment,
+		UnfulfilledItems:  unfulfilledItems,
+		ParentOrderID:     fromSQLNull(orderRow.ParentOrderID),
+		BackorderID:       fromSQLNull(orderRow.BackorderID),
+		ShippingAddress:   shippingAddress,
+		DeliveryMethod:    model.DeliveryMethod(orderRow.DeliveryMethod),
+		DeliveryFee:       orderRow.DeliveryFee,
+		PaymentID:         fromSQLNull(orderRow.PaymentID),
+		Version:           orderRow.Version,
 	}, nil
 }
 
@@ -133,13 +240,14 @@ func (o *orderRepository) loadOrderItems(orderID uuid.UUID) ([]model.OrderItem,
 		OrderID    uuid.UUID `db:"order_id"`
 		ProductID  uuid.UUID `db:"product_id"`
 		Count      int       `db:"count"`
+		Price      float64   `db:"price"`
 		TotalPrice float64   `db:"total_price"`
 	}
 
 	err := o.client.SelectContext(
 		o.ctx,
 		&itemRows,
-		`SELECT order_id, product_id, count, total_price FROM order_items WHERE order_id = ?`,
+		`SELECT order_id, product_id, count, price, total_price FROM order_items WHERE order_id = ?`,
 		orderID,
 	)
 	if err != nil {
@@ -152,6 +260,7 @@ func (o *orderRepository) loadOrderItems(orderID uuid.UUID) ([]model.OrderItem,
 			OrderID:    row.OrderID,
 			ProductID:  row.ProductID,
 			Count:      row.Count,
+			Price:      row.Price,
 			TotalPrice: row.TotalPrice,
 		}
 	}
@@ -159,6 +268,99 @@ func (o *orderRepository) loadOrderItems(orderID uuid.UUID) ([]model.OrderItem,
 	return items, nil
 }
 
+func (o *orderRepository) loadUnfulfilledItems(orderID uuid.UUID) ([]model.UnfulfilledItem, error) {
+	var itemRows []struct {
+		ProductID uuid.UUID `db:"product_id"`
+		Count     int       `db:"count"`
+		Price     float64   `db:"price"`
+		Reason    string    `db:"reason"`
+	}
+
+	err := o.client.SelectContext(
+		o.ctx,
+		&itemRows,
+		`SELECT product_id, count, price, reason FROM order_unfulfilled_items WHERE order_id = ? ORDER BY product_id`,
+		orderID,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	items := make([]model.UnfulfilledItem, len(itemRows))
+	for i, row := range itemRows {
+		items[i] = model.UnfulfilledItem{
+			ProductID: row.ProductID,
+			Count:     row.Count,
+			Price:     row.Price,
+			Reason:    row.Reason,
+		}
+	}
+	return items, nil
+}
+
+func (o *orderRepository) storeShippingAddress(order *model.Order) error {
+	if order.ShippingAddress == nil {
+		_, err := o.client.ExecContext(o.ctx, `DELETE FROM order_shipping_addresses WHERE order_id = ?`, order.ID)
+		return errors.WithStack(err)
+	}
+
+	address := order.ShippingAddress
+	_, err := o.client.ExecContext(o.ctx,
+		`
+		INSERT INTO order_shipping_addresses (order_id, recipient, phone, country, city, street, postal_code)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			recipient=VALUES(recipient),
+			phone=VALUES(phone),
+			country=VALUES(country),
+			city=VALUES(city),
+			street=VALUES(street),
+			postal_code=VALUES(postal_code)
+		`,
+		order.ID,
+		address.Recipient,
+		address.Phone,
+		address.Country,
+		address.City,
+		address.Street,
+		address.PostalCode,
+	)
+	return errors.WithStack(err)
+}
+
+func (o *orderRepository) loadShippingAddress(orderID uuid.UUID) (*model.ShippingAddress, error) {
+	var row struct {
+		Recipient  string `db:"recipient"`
+		Phone      string `db:"phone"`
+		Country    string `db:"country"`
+		City       string `db:"city"`
+		Street     string `db:"street"`
+		PostalCode string `db:"postal_code"`
+	}
+
+	err := o.client.GetContext(
+		o.ctx,
+		&row,
+		`SELECT recipient, phone, country, city, street, postal_code FROM order_shipping_addresses WHERE order_id = ?`,
+		orderID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	return &model.ShippingAddress{
+		Recipient:  row.Recipient,
+		Phone:      row.Phone,
+		Country:    row.Country,
+		City:       row.City,
+		Street:     row.Street,
+		PostalCode: row.PostalCode,
+	}, nil
+}
+
 func fromSQLNull[T any](v sql.Null[T]) *T {
 	if v.Valid {
 		return &v.V
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+
+	appdata "order/pkg/order/app/data"
+	"order/pkg/order/app/service"
+)
+
+func NewOrderHistoryRepository(ctx context.Context, client mysql.ClientContext) service.OrderHistoryRepository {
+	return &orderHistoryRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type orderHistoryRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+func (r *orderHistoryRepository) Append(entry appdata.OrderHistoryEntry) error {
+	_, err := r.client.ExecContext(r.ctx,
+		`INSERT INTO order_history (order_id, event_type, actor, from_status, to_status, details, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		entry.OrderID,
+		entry.EventType,
+		entry.Actor,
+		toSQLNullStatus(entry.FromStatus),
+		toSQLNullStatus(entry.ToStatus),
+		entry.Details,
+		entry.CreatedAt,
+	)
+	return errors.WithStack(err)
+}
+
+func toSQLNullStatus(status *appdata.OrderStatus) sql.Null[int] {
+	if status == nil {
+		return sql.Null[int]{}
+	}
+	return sql.Null[int]{
+		V:     int(*status),
+		Valid: true,
+	}
+}
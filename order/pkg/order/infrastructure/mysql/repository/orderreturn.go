@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"order/pkg/order/domain/model"
+)
+
+func NewOrderReturnRepository(ctx context.Context, client mysql.ClientContext) model.OrderReturnRepository {
+	return &orderReturnRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type orderReturnRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+type orderReturnRow struct {
+	ID              uuid.UUID `db:"return_id"`
+	OrderID         uuid.UUID `db:"order_id"`
+	CustomerID      uuid.UUID `db:"customer_id"`
+	Status          int       `db:"status"`
+	Reason          string    `db:"reason"`
+	RejectionReason string    `db:"rejection_reason"`
+	RefundAmount    float64   `db:"refund_amount"`
+	CreatedAt       time.Time `db:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at"`
+}
+
+const orderReturnColumns = `return_id, order_id, customer_id, status, reason, rejection_reason, refund_amount, created_at, updated_at`
+
+func (o *orderReturnRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (o *orderReturnRepository) Store(orderReturn *model.OrderReturn) error {
+	_, err := o.client.ExecContext(o.ctx,
+		`
+		INSERT INTO order_returns (`+orderReturnColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			status=VALUES(status),
+			rejection_reason=VALUES(rejection_reason),
+			updated_at=VALUES(updated_at)
+		`,
+		orderReturn.ID,
+		orderReturn.OrderID,
+		orderReturn.CustomerID,
+		orderReturn.Status,
+		orderReturn.Reason,
+		orderReturn.RejectionReason,
+		orderReturn.RefundAmount,
+		orderReturn.CreatedAt,
+		orderReturn.UpdatedAt,
+	)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	// Items are not changed after the return is requested
+	for _, item := range orderReturn.Items {
+		_, err = o.client.ExecContext(o.ctx,
+			`INSERT IGNORE INTO order_return_items (return_id, product_id, count, price) VALUES (?, ?, ?, ?)`,
+			orderReturn.ID,
+			item.ProductID,
+			item.Count,
+			item.Price,
+		)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+func (o *orderReturnRepository) Find(id uuid.UUID) (*model.OrderReturn, error) {
+	var row orderReturnRow
+	err := o.client.GetContext(
+		o.ctx,
+		&row,
+		`SELECT `+orderReturnColumns+` FROM order_returns WHERE return_id = ?`,
+		id,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(model.ErrOrderReturnNotFound)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	orderReturn, err := o.toOrderReturn(row)
+	if err != nil {
+		return nil, err
+	}
+	return &orderReturn, nil
+}
+
+func (o *orderReturnRepository) FindByOrderID(orderID uuid.UUID) ([]model.OrderReturn, error) {
+	var rows []orderReturnRow
+	err := o.client.SelectContext(
+		o.ctx,
+		&rows,
+		`SELECT `+orderReturnColumns+` FROM order_returns WHERE order_id = ? ORDER BY return_id`,
+		orderID,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	returns := make([]model.OrderReturn, 0, len(rows))
+	for _, row := range rows {
+		orderReturn, err := o.toOrderReturn(row)
+		if err != nil {
+			return nil, err
+		}
+		returns = append(returns, orderReturn)
+	}
+	return returns, nil
+}
+
+func (o *orderReturnRepository) toOrderReturn(row orderReturnRow) (model.OrderReturn, error) {
+	var itemRows []struct {
+		ProductID uuid.UUID `db:"product_id"`
+		Count     int       `db:"count"`
+		Price     float64   `db:"price"`
+	}
+	err := o.client.SelectContext(
+		o.ctx,
+		&itemRows,
+		`SELECT product_id, count, price FROM order_return_items WHERE return_id = ? ORDER BY product_id`,
+		row.ID,
+	)
+	if err != nil {
+		return model.OrderReturn{}, errors.WithStack(err)
+	}
+
+	orderReturn := model.OrderReturn{
+		ID:              row.ID,
+		OrderID:         row.OrderID,
+		CustomerID:      row.CustomerID,
+		Status:          model.OrderReturnStatus(row.Status),
+		Items:           make([]model.OrderReturnItem, len(itemRows)),
+		Reason:          row.Reason,
+		RejectionReason: row.RejectionReason,
+		RefundAmount:    row.RefundAmount,
+		CreatedAt:       row.CreatedAt,
+		UpdatedAt:       row.UpdatedAt,
+	}
+	for i, item := range itemRows {
+		orderReturn.Items[i] = model.OrderReturnItem{
+			ProductID: item.ProductID,
+			Count:     item.Count,
+			Price:     item.Price,
+		}
+	}
+	return orderReturn, nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"order/pkg/order/domain/model"
+)
+
+func NewOrderTemplateRepository(ctx context.Context, client mysql.ClientContext) model.OrderTemplateRepository {
+	return &orderTemplateRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type orderTemplateRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+func (o *orderTemplateRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (o *orderTemplateRepository) Store(template *model.OrderTemplate) error {
+	var schedule model.OrderSchedule
+	if template.Schedule != nil {
+		schedule = *template.Schedule
+	}
+	_, err := o.client.ExecContext(o.ctx,
+		`
+		INSERT INTO order_templates (template_id, customer_id, scheduled, cron_expression, schedule_interval, schedule_paused, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			customer_id=VALUES(customer_id),
+			scheduled=VALUES(scheduled),
+			cron_expression=VALUES(cron_expression),
+			schedule_interval=VALUES(schedule_interval),
+			schedule_paused=VALUES(schedule_paused),
+			updated_at=VALUES(updated_at)
+		`,
+		template.ID,
+		template.CustomerID,
+		template.Schedule != nil,
+		schedule.CronExpression,
+		schedule.Interval,
+		schedule.Paused,
+		template.CreatedAt,
+		template.UpdatedAt,
+	)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = o.client.ExecContext(o.ctx, `DELETE FROM order_template_items WHERE template_id = ?`, template.ID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for _, item := range template.Items {
+		_, err = o.client.ExecContext(o.ctx,
+			`INSERT INTO order_template_items (template_id, product_id, count) VALUES (?, ?, ?)`,
+			template.ID,
+			item.ProductID,
+			item.Count,
+		)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+func (o *orderTemplateRepository) Find(id uuid.UUID) (*model.OrderTemplate, error) {
+	templateRow := struct {
+		ID               uuid.UUID     `db:"template_id"`
+		CustomerID       uuid.UUID     `db:"customer_id"`
+		Scheduled        bool          `db:"scheduled"`
+		CronExpression   string        `db:"cron_expression"`
+		ScheduleInterval time.Duration `db:"schedule_interval"`
+		SchedulePaused   bool          `db:"schedule_paused"`
+		CreatedAt        time.Time     `db:"created_at"`
+		UpdatedAt        time.Time     `db:"updated_at"`
+	}{}
+
+	err := o.client.GetContext(
+		o.ctx,
+		&templateRow,
+		`
+		SELECT template_id, customer_id, scheduled, cron_expression, schedule_interval, schedule_paused, created_at, updated_at
+		FROM order_templates WHERE template_id = ?
+		`,
+		id,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(model.ErrOrderTemplateNotFound)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	var itemRows []struct {
+		ProductID uuid.UUID `db:"product_id"`
+		Count     int       `db:"count"`
+	}
+	err = o.client.SelectContext(
+		o.ctx,
+		&itemRows,
+		`SELECT product_id, count FROM order_template_items WHERE template_id = ? ORDER BY product_id`,
+		id,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	template := &model.OrderTemplate{
+		ID:         templateRow.ID,
+		CustomerID: templateRow.CustomerID,
+		Items:      make([]model.OrderTemplateItem, len(itemRows)),
+		CreatedAt:  templateRow.CreatedAt,
+		UpdatedAt:  templateRow.UpdatedAt,
+	}
+	for i, row := range itemRows {
+		template.Items[i] = model.OrderTemplateItem{
+			ProductID: row.ProductID,
+			Count:     row.Count,
+		}
+	}
+	if templateRow.Scheduled {
+		template.Schedule = &model.OrderSchedule{
+			CronExpression: templateRow.CronExpression,
+			Interval:       templateRow.ScheduleInterval,
+			Paused:         templateRow.SchedulePaused,
+		}
+	}
+	return template, nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"order/pkg/order/domain/model"
+)
+
+func NewPromotionRepository(ctx context.Context, client mysql.ClientContext) model.PromotionRepository {
+	return &promotionRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type promotionRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+type promotionRow struct {
+	ID               uuid.UUID           `db:"promotion_id"`
+	Code             string              `db:"code"`
+	Kind             int                 `db:"kind"`
+	Value            float64             `db:"value"`
+	MinOrderTotal    float64             `db:"min_order_total"`
+	PerCustomerLimit int                 `db:"per_customer_limit"`
+	ValidFrom        sql.Null[time.Time] `db:"valid_from"`
+	ValidTo          sql.Null[time.Time] `db:"valid_to"`
+	CreatedAt        time.Time           `db:"created_at"`
+}
+
+const promotionColumns = `promotion_id, code, kind, value, min_order_total, per_customer_limit, valid_from, valid_to, created_at`
+
+func (p *promotionRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (p *promotionRepository) Store(promotion *model.Promotion) error {
+	_, err := p.client.ExecContext(p.ctx,
+		`
+		INSERT INTO promotions (promotion_id, code, kind, value, min_order_total, per_customer_limit, valid_from, valid_to, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			code=VALUES(code),
+			kind=VALUES(kind),
+			value=VALUES(value),
+			min_order_total=VALUES(min_order_total),
+			per_customer_limit=VALUES(per_customer_limit),
+			valid_from=VALUES(valid_from),
+			valid_to=VALUES(valid_to)
+		`,
+		promotion.ID,
+		promotion.Code,
+		promotion.Kind,
+		promotion.Value,
+		promotion.MinOrderTotal,
+		promotion.PerCustomerLimit,
+		toSQLNull(promotion.ValidFrom),
+		toSQLNull(promotion.ValidTo),
+		promotion.CreatedAt,
+	)
+	return errors.WithStack(err)
+}
+
+func (p *promotionRepository) Find(id uuid.UUID) (*model.Promotion, error) {
+	return p.find(`SELECT `+promotionColumns+` FROM promotions WHERE promotion_id = ?`, id)
+}
+
+func (p *promotionRepository) FindByCode(code string) (*model.Promotion, error) {
+	return p.find(`SELECT `+promotionColumns+` FROM promotions WHERE code = ?`, code)
+}
+
+func (p *promotionRepository) AddRedemption(redemption model.PromotionRedemption) error {
+	_, err := p.client.ExecContext(p.ctx,
+		`INSERT IGNORE INTO promotion_redemptions (promotion_id, order_id, customer_id, created_at) VALUES (?, ?, ?, ?)`,
+		redemption.PromotionID,
+		redemption.OrderID,
+		redemption.CustomerID,
+		redemption.CreatedAt,
+	)
+	return errors.WithStack(err)
+}
+
+func (p *promotionRepository) RemoveRedemption(promotionID, orderID uuid.UUID) error {
+	_, err := p.client.ExecContext(p.ctx,
+		`DELETE FROM promotion_redemptions WHERE promotion_id = ? AND order_id = ?`,
+		promotionID,
+		orderID,
+	)
+	return errors.WithStack(err)
+}
+
+func (p *promotionRepository) CountRedemptions(promotionID, customerID uuid.UUID) (int, error) {
+	var count int
+	err := p.client.GetContext(p.ctx,
+		&count,
+		`SELECT COUNT(*) FROM promotion_redemptions WHERE promotion_id = ? AND customer_id = ?`,
+		promotionID,
+		customerID,
+	)
+	return count, errors.WithStack(err)
+}
+
+func (p *promotionRepository) find(query string, args ...interface{}) (*model.Promotion, error) {
+	var row promotionRow
+	err := p.client.GetContext(p.ctx, &row, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(model.ErrPromotionNotFound)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	return &model.Promotion{
+		ID:               row.ID,
+		Code:             row.Code,
+		Kind:             model.PromotionKind(row.Kind),
+		Value:            row.Value,
+		MinOrderTotal:    row.MinOrderTotal,
+		PerCustomerLimit: row.PerCustomerLimit,
+		ValidFrom:        fromSQLNull(row.ValidFrom),
+		ValidTo:          fromSQLNull(row.ValidTo),
+		CreatedAt:        row.CreatedAt,
+	}, nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
 
+	"order/pkg/common/idempotency"
 	"order/pkg/order/app/service"
 	"order/pkg/order/domain/model"
 	"order/pkg/order/infrastructure/mysql/repository"
@@ -21,3 +22,27 @@ type repositoryProvider struct {
 func (r *repositoryProvider) OrderRepository(ctx context.Context) model.OrderRepository {
 	return repository.NewOrderRepository(ctx, r.client)
 }
+
+func (r *repositoryProvider) IdempotencyRepository(ctx context.Context) idempotency.Repository {
+	return repository.NewIdempotencyRepository(ctx, r.client)
+}
+
+func (r *repositoryProvider) OrderHistoryRepository(ctx context.Context) service.OrderHistoryRepository {
+	return repository.NewOrderHistoryRepository(ctx, r.client)
+}
+
+func (r *repositoryProvider) CartRepository(ctx context.Context) model.CartRepository {
+	return repository.NewCartRepository(ctx, r.client)
+}
+
+func (r *repositoryProvider) PromotionRepository(ctx context.Context) model.PromotionRepository {
+	return repository.NewPromotionRepository(ctx, r.client)
+}
+
+func (r *repositoryProvider) OrderTemplateRepository(ctx context.Context) model.OrderTemplateRepository {
+	return repository.NewOrderTemplateRepository(ctx, r.client)
+}
+
+func (r *repositoryProvider) OrderReturnRepository(ctx context.Context) model.OrderReturnRepository {
+	return repository.NewOrderReturnRepository(ctx, r.client)
+}
@@ -0,0 +1,52 @@
+package productcatalog
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"order/api/client/productinternal"
+	appdata "order/pkg/order/app/data"
+	"order/pkg/order/app/service"
+)
+
+func NewProductCatalog(conn grpc.ClientConnInterface) service.ProductCatalog {
+	return &productCatalog{
+		client: productinternal.NewProductInternalServiceClient(conn),
+	}
+}
+
+type productCatalog struct {
+	client productinternal.ProductInternalServiceClient
+}
+
+func (p *productCatalog) FindProduct(ctx context.Context, productID uuid.UUID) (appdata.Product, error) {
+	response, err := p.client.FindProduct(ctx, &productinternal.FindProductRequest{
+		ProductID: productID.String(),
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return appdata.Product{}, errors.WithStack(service.ErrProductNotFound)
+		}
+		return appdata.Product{}, errors.WithStack(err)
+	}
+
+	product := appdata.Product{
+		ID:    productID,
+		Name:  response.Name,
+		Price: response.Price,
+	}
+	if response.DeletedAt != nil {
+		deletedAt, err := time.Parse(time.RFC3339, *response.DeletedAt)
+		if err != nil {
+			return appdata.Product{}, errors.WithStack(err)
+		}
+		product.DeletedAt = &deletedAt
+	}
+	return product, nil
+}
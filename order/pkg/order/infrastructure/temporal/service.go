@@ -1,12 +1,24 @@
 package temporal
 
 import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.temporal.io/api/serviceerror"
 	"go.temporal.io/sdk/client"
+
+	"order/pkg/order/domain/model"
+	"order/pkg/order/infrastructure/temporal/workflows"
 )
 
 const TaskQueue = "order_task_queue"
 
 type WorkflowService interface {
+	RunUserDeletedWorkflow(ctx context.Context, id string, event model.UserDeleted) error
+	RunProductRemovedWorkflow(ctx context.Context, id string, event model.ProductRemoved) error
+	RunPaymentStatusChangedWorkflow(ctx context.Context, id string, event model.PaymentStatusChanged) error
+	// StartRemoveExpiredCartsWorkflow starts cron workflow removing expired carts unless it is running already
+	StartRemoveExpiredCartsWorkflow(ctx context.Context) error
 }
 
 func NewWorkflowService(temporalClient client.Client) WorkflowService {
@@ -18,3 +30,33 @@ func NewWorkflowService(temporalClient client.Client) WorkflowService {
 type workflowService struct {
 	temporalClient client.Client
 }
+
+func (s *workflowService) RunUserDeletedWorkflow(ctx context.Context, id string, event model.UserDeleted) error {
+	return s.executeWorkflow(ctx, client.StartWorkflowOptions{ID: "user-deleted-" + id}, workflows.UserDeletedWorkflow, event)
+}
+
+func (s *workflowService) RunProductRemovedWorkflow(ctx context.Context, id string, event model.ProductRemoved) error {
+	return s.executeWorkflow(ctx, client.StartWorkflowOptions{ID: "product-removed-" + id}, workflows.ProductRemovedWorkflow, event)
+}
+
+func (s *workflowService) RunPaymentStatusChangedWorkflow(ctx context.Context, id string, event model.PaymentStatusChanged) error {
+	return s.executeWorkflow(ctx, client.StartWorkflowOptions{ID: "payment-status-changed-" + id}, workflows.PaymentStatusChangedWorkflow, event)
+}
+
+func (s *workflowService) StartRemoveExpiredCartsWorkflow(ctx context.Context) error {
+	return s.executeWorkflow(ctx, client.StartWorkflowOptions{
+		ID:           workflows.RemoveExpiredCartsWorkflowID,
+		CronSchedule: workflows.RemoveExpiredCartsSchedule,
+	}, workflows.RemoveExpiredCartsWorkflow)
+}
+
+func (s *workflowService) executeWorkflow(ctx context.Context, options client.StartWorkflowOptions, workflow interface{}, args ...interface{}) error {
+	options.TaskQueue = TaskQueue
+	_, err := s.temporalClient.ExecuteWorkflow(ctx, options, workflow, args...)
+	// Redelivered message must not fail when its workflow is already started
+	var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+	if errors.As(err, &alreadyStarted) {
+		return nil
+	}
+	return errors.WithStack(err)
+}
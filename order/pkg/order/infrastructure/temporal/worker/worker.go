@@ -8,7 +8,9 @@ import (
 	"go.temporal.io/sdk/activity"
 	"go.temporal.io/sdk/client"
 	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
 
+	"order/pkg/order/app/query"
 	"order/pkg/order/app/service"
 	"order/pkg/order/infrastructure/temporal"
 )
@@ -20,13 +22,46 @@ func InterruptChannel() <-chan interface{} {
 func NewWorker(
 	temporalClient client.Client,
 	os service.OrderService,
+	qs query.OrderQueryService,
+	cs service.CartService,
+	ps service.PromotionService,
+	ots service.OrderTemplateService,
+	ors service.OrderReturnService,
 ) worker.Worker {
 	w := worker.New(temporalClient, temporal.TaskQueue, worker.Options{})
 
-	acts := appactivity.NewOrderActivities(os)
+	acts := appactivity.NewOrderActivities(os, qs)
+	cartActs := appactivity.NewCartActivities(cs)
+	promotionActs := appactivity.NewPromotionActivities(ps)
+	orderTemplateActs := appactivity.NewOrderTemplateActivities(ots)
+	orderReturnActs := appactivity.NewOrderReturnActivities(ors)
 
 	w.RegisterActivityWithOptions(acts.SetOrderStatusActivity, activity.RegisterOptions{Name: "SetOrderStatusActivity"})
+	w.RegisterActivityWithOptions(acts.FindOrdersActivity, activity.RegisterOptions{Name: "FindOrdersActivity"})
+	w.RegisterActivityWithOptions(acts.CancelOrderActivity, activity.RegisterOptions{Name: "CancelOrderActivity"})
+	w.RegisterActivityWithOptions(acts.ExpireOrderActivity, activity.RegisterOptions{Name: "ExpireOrderActivity"})
+	w.RegisterActivityWithOptions(acts.RemoveOrderProductActivity, activity.RegisterOptions{Name: "RemoveOrderProductActivity"})
+	w.RegisterActivityWithOptions(acts.ApplyPaymentStatusActivity, activity.RegisterOptions{Name: "ApplyPaymentStatusActivity"})
+	w.RegisterActivityWithOptions(acts.MarkUnfulfilledItemsActivity, activity.RegisterOptions{Name: "MarkUnfulfilledItemsActivity"})
+	w.RegisterActivityWithOptions(acts.CreateBackorderActivity, activity.RegisterOptions{Name: "CreateBackorderActivity"})
+	w.RegisterActivityWithOptions(acts.SetOrderPaymentActivity, activity.RegisterOptions{Name: "SetOrderPaymentActivity"})
+	w.RegisterActivityWithOptions(cartActs.RemoveExpiredCartsActivity, activity.RegisterOptions{Name: "RemoveExpiredCartsActivity"})
+	w.RegisterActivityWithOptions(promotionActs.RedeemPromoCodeActivity, activity.RegisterOptions{Name: "RedeemPromoCodeActivity"})
+	w.RegisterActivityWithOptions(promotionActs.ReleasePromoCodeActivity, activity.RegisterOptions{Name: "ReleasePromoCodeActivity"})
+	w.RegisterActivityWithOptions(orderTemplateActs.PrepareScheduledOrderActivity, activity.RegisterOptions{Name: "PrepareScheduledOrderActivity"})
+	w.RegisterActivityWithOptions(orderTemplateActs.ReportScheduledOrderFailureActivity, activity.RegisterOptions{Name: "ReportScheduledOrderFailureActivity"})
+	w.RegisterActivityWithOptions(orderReturnActs.ApproveReturnActivity, activity.RegisterOptions{Name: "ApproveReturnActivity"})
+	w.RegisterActivityWithOptions(orderReturnActs.RejectReturnActivity, activity.RegisterOptions{Name: "RejectReturnActivity"})
+	w.RegisterActivityWithOptions(orderReturnActs.CompleteReturnActivity, activity.RegisterOptions{Name: "CompleteReturnActivity"})
 
-	w.RegisterWorkflow(workflows.CreateOrderSaga)
+	w.RegisterWorkflowWithOptions(workflows.CreateOrderSaga, workflow.RegisterOptions{Name: workflows.CreateOrderSagaName})
+	w.RegisterWorkflowWithOptions(workflows.LegacyCreateOrderSaga, workflow.RegisterOptions{Name: workflows.LegacyCreateOrderSagaName})
+	w.RegisterWorkflow(workflows.OrderExpiryWorkflow)
+	w.RegisterWorkflow(workflows.UserDeletedWorkflow)
+	w.RegisterWorkflow(workflows.ProductRemovedWorkflow)
+	w.RegisterWorkflow(workflows.PaymentStatusChangedWorkflow)
+	w.RegisterWorkflow(workflows.RemoveExpiredCartsWorkflow)
+	w.RegisterWorkflow(workflows.ScheduledOrderWorkflow)
+	w.RegisterWorkflow(workflows.OrderReturnWorkflow)
 	return w
 }
@@ -0,0 +1,27 @@
+package workflows
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+const (
+	RemoveExpiredCartsWorkflowID = "remove-expired-carts"
+	// RemoveExpiredCartsSchedule is a cron schedule of RemoveExpiredCartsWorkflow
+	RemoveExpiredCartsSchedule = "@every 1h"
+)
+
+// RemoveExpiredCartsWorkflow removes carts not changed for longer than cart TTL, it runs by RemoveExpiredCartsSchedule
+func RemoveExpiredCartsWorkflow(ctx workflow.Context) error {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy: &temporal.RetryPolicy{
+			MaximumAttempts: 3,
+		},
+	})
+
+	// CALL BY EXPLICIT STRING NAME "RemoveExpiredCartsActivity"
+	return workflow.ExecuteActivity(ctx, "RemoveExpiredCartsActivity").Get(ctx, nil)
+}
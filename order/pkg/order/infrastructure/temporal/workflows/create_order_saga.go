@@ -1,17 +1,59 @@
 package workflows
 
 import (
+	"errors"
+	"slices"
 	"time"
 
 	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/workflow"
 )
 
+const (
+	// CancelOrderSignal asks a running CreateOrderSaga to compensate and cancel the order,
+	// the signal carries the reason reported as LastError, which is empty when the order is cancelled by request
+	CancelOrderSignal = "cancel-order"
+	// CapturePaymentSignal asks CreateOrderSaga holding the payment to capture it,
+	// the signal carries the captured amount and zero captures the whole hold
+	CapturePaymentSignal = "capture-payment"
+	// OrderSagaStatusQuery returns OrderSagaStatus of CreateOrderSaga
+	OrderSagaStatusQuery = "order-saga-status"
+
+	// CreateOrderSagaName is the name CreateOrderSaga is registered and started under,
+	// sagas started under LegacyCreateOrderSagaName keep running LegacyCreateOrderSaga
+	CreateOrderSagaName = "CreateOrderSagaV2"
+
+	productTaskQueue = "product-task-queue"
+	paymentTaskQueue = "payment_task_queue"
+
+	// UnfulfilledReasonOutOfStock is the reason of items which could not be reserved
+	UnfulfilledReasonOutOfStock = "out of stock"
+)
+
+var (
+	errNothingReserved    = errors.New("no items of the order are in stock")
+	errPaymentHoldExpired = errors.New("payment hold expired before it is captured")
+)
+
+func OrderSagaWorkflowID(orderID string) string {
+	return "order-saga-" + orderID
+}
+
 type OrderSagaParams struct {
-	OrderID    string
-	UserID     string
-	Items      []OrderItemParam
+	OrderID string
+	UserID  string
+	Items   []OrderItemParam
+	// TotalPrice is the order total before discount, the discount of applied promo code is taken off when charging
 	TotalPrice float64
+	// DeliveryFee is charged along with the order total, it is not reduced by the discount
+	DeliveryFee float64
+	// AllowPartial makes the saga reserve items in stock and charge for them only instead of cancelling the order
+	AllowPartial bool
+	// SplitBackorder makes the saga move unfulfilled items into a backorder once the order is paid
+	SplitBackorder bool
+	// PaymentHoldTTL makes the saga hold the amount on the wallet and capture it on CapturePaymentSignal
+	// instead of charging at once, the order is cancelled when the hold is not captured in time, zero charges at once
+	PaymentHoldTTL time.Duration
 }
 
 type OrderItemParam struct {
@@ -19,56 +61,435 @@ type OrderItemParam struct {
 	Quantity  int
 }
 
-func CreateOrderSaga(ctx workflow.Context, params OrderSagaParams) error {
+type UnfulfilledItemParam struct {
+	ProductID string
+	Quantity  int
+	Reason    string
+}
+
+type OrderSagaStep int
+
+const (
+	OrderSagaStarting OrderSagaStep = iota
+	OrderSagaReserving
+	OrderSagaCharging
+	OrderSagaCompensating
+	OrderSagaCompleted
+	OrderSagaCancelled
+	// OrderSagaAwaitingCapture means the payment is held and the saga waits for CapturePaymentSignal
+	OrderSagaAwaitingCapture
+)
+
+type OrderSagaStatus struct {
+	Step OrderSagaStep
+	// Deprecated: CurrentItem is always zero since all items are reserved by a single ReserveProducts call
+	CurrentItem      int
+	ReservedItems    []OrderItemParam
+	UnfulfilledItems []UnfulfilledItemParam
+	// BackorderID is set when unfulfilled items are moved into a backorder
+	BackorderID string
+	// PaymentID is set once the payment of the order is created
+	PaymentID string
+	// HoldID is set once the wallet is authorized for the order paid through a hold
+	HoldID    string
+	LastError string
+}
+
+type orderSaga struct {
+	params     OrderSagaParams
+	status     OrderSagaStatus
+	totalPrice float64
+	// reserving is set once products are requested, stock is reserved under the order ID, so it is released by it
+	reserving       bool
+	promoRedeemed   bool
+	charged         bool
+	paid            bool
+	cancelRequested bool
+	cancelReason    string
+	// captureAmount is received with CapturePaymentSignal
+	captureRequested bool
+	captureAmount    float64
+}
+
+func (s *orderSaga) fail(err error) {
+	// Step interrupted by cancellation is not a failure, the reason of cancellation is reported instead
+	if temporal.IsCanceledError(err) {
+		return
+	}
+	s.status.LastError = err.Error()
+}
+
+// CreateOrderSaga returns its final status, Step is OrderSagaCancelled when the order is not paid
+func CreateOrderSaga(ctx workflow.Context, params OrderSagaParams) (OrderSagaStatus, error) {
+	retryPolicy := &temporal.RetryPolicy{
+		MaximumAttempts: 3,
+	}
 	ao := workflow.ActivityOptions{
 		StartToCloseTimeout: time.Minute,
-		RetryPolicy: &temporal.RetryPolicy{
-			MaximumAttempts: 3,
-		},
+		RetryPolicy:         retryPolicy,
 	}
 	ctx = workflow.WithActivityOptions(ctx, ao)
+	// Steps run in stepCtx, so cancellation stops retries of the running step instead of waiting for them,
+	// the attempt in progress is waited for, so its result is not lost
+	stepCtx, cancelSteps := workflow.WithCancel(ctx)
+	stepCtx = workflow.WithWaitForCancellation(stepCtx, true)
 
 	logger := workflow.GetLogger(ctx)
 	logger.Info("Starting Order Saga", "OrderID", params.OrderID)
 
-	// 1. Reserve Products
-	for _, item := range params.Items {
-		ctxProduct := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
-			TaskQueue:           "product-task-queue",
-			StartToCloseTimeout: time.Minute,
+	saga := &orderSaga{params: params, totalPrice: params.TotalPrice}
+	err := workflow.SetQueryHandler(ctx, OrderSagaStatusQuery, func() (OrderSagaStatus, error) {
+		return saga.status, nil
+	})
+	if err != nil {
+		return saga.status, err
+	}
+
+	workflow.Go(ctx, func(ctx workflow.Context) {
+		workflow.GetSignalChannel(ctx, CancelOrderSignal).Receive(ctx, &saga.cancelReason)
+		logger.Info("Order cancellation requested", "OrderID", params.OrderID, "Reason", saga.cancelReason)
+		saga.cancelRequested = true
+		cancelSteps()
+	})
+
+	if params.PaymentHoldTTL > 0 {
+		workflow.Go(ctx, func(ctx workflow.Context) {
+			workflow.GetSignalChannel(ctx, CapturePaymentSignal).Receive(ctx, &saga.captureAmount)
+			logger.Info("Order payment capture requested", "OrderID", params.OrderID)
+			saga.captureRequested = true
 		})
+	}
 
-		// CALL BY EXPLICIT STRING NAME "ReserveProduct"
-		err := workflow.ExecuteActivity(ctxProduct, "ReserveProduct", item.ProductID, item.Quantity).Get(ctx, nil)
-		if err != nil {
-			logger.Error("Failed to reserve product", "Error", err)
-			return setOrderStatus(ctx, params.OrderID, "Cancelled")
+	err = setOrderStatus(ctx, params.OrderID, "Pending")
+	if err != nil {
+		saga.fail(err)
+		return saga.status, err
+	}
+
+	// 1. Reserve Products
+	ctxProduct := workflow.WithActivityOptions(stepCtx, workflow.ActivityOptions{
+		TaskQueue:           productTaskQueue,
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy:         retryPolicy,
+	})
+	saga.status.Step = OrderSagaReserving
+	if saga.cancelRequested {
+		return saga.cancel(ctx)
+	}
+
+	saga.reserving = true
+	if params.AllowPartial {
+		err = saga.reserveAvailable(ctx, ctxProduct)
+	} else {
+		// CALL BY EXPLICIT STRING NAME "ReserveProducts"
+		// All items are reserved in one transaction, so nothing is left reserved when it fails
+		err = workflow.ExecuteActivity(ctxProduct, "ReserveProducts", params.OrderID, params.Items).Get(ctx, nil)
+		if err == nil {
+			saga.status.ReservedItems = params.Items
 		}
 	}
+	if err != nil {
+		logger.Error("Failed to reserve products", "Error", err)
+		saga.fail(err)
+		return saga.cancel(ctx)
+	}
+
+	if saga.cancelRequested {
+		return saga.cancel(ctx)
+	}
 
 	// 2. Charge Wallet
-	ctxPayment := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
-		TaskQueue:           "payment_task_queue",
+	saga.status.Step = OrderSagaCharging
+
+	// Redemption is counted only here, so promo code of a failed order is released by compensation
+	var discount float64
+	// CALL BY EXPLICIT STRING NAME "RedeemPromoCodeActivity"
+	err = workflow.ExecuteActivity(stepCtx, "RedeemPromoCodeActivity", params.OrderID).Get(ctx, &discount)
+	if err != nil {
+		logger.Error("Failed to redeem promo code", "Error", err)
+		saga.fail(err)
+		return saga.cancel(ctx)
+	}
+	saga.promoRedeemed = true
+
+	ctxPayment := workflow.WithActivityOptions(stepCtx, workflow.ActivityOptions{
+		TaskQueue:           paymentTaskQueue,
 		StartToCloseTimeout: time.Minute,
+		RetryPolicy:         retryPolicy,
 	})
 
-	// CALL BY EXPLICIT STRING NAME "ChargeWallet"
-	err := workflow.ExecuteActivity(ctxPayment, "ChargeWallet", params.UserID, params.TotalPrice).Get(ctx, nil)
+	amount := saga.totalPrice - discount + params.DeliveryFee
+	if params.PaymentHoldTTL > 0 {
+		err = saga.holdPayment(ctx, ctxPayment, ctxProduct, amount)
+	} else {
+		err = saga.chargePayment(ctx, ctxPayment, amount)
+		if err == nil {
+			err = saga.confirmProducts(ctx, ctxProduct)
+		}
+	}
+	if err != nil {
+		saga.fail(err)
+		return saga.cancel(ctx)
+	}
+
+	if saga.cancelRequested {
+		return saga.cancel(ctx)
+	}
+
+	// 3. Success
+	err = setOrderStatus(ctx, params.OrderID, "Paid")
 	if err != nil {
-		logger.Error("Failed to charge wallet", "Error", err)
+		saga.fail(err)
+		return saga.status, err
+	}
+	saga.paid = true
+	saga.status.Step = OrderSagaCompleted
+	// Cancellation received while the order was being paid is honoured, later ones are rejected by CancelOrder
+	if saga.cancelRequested {
+		return saga.cancel(ctx)
+	}
 
-		// Compensation: Release Products
-		for _, item := range params.Items {
-			ctxProduct := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{TaskQueue: "product-task-queue"})
-			// CALL BY EXPLICIT STRING NAME "ReleaseProduct"
-			_ = workflow.ExecuteActivity(ctxProduct, "ReleaseProduct", item.ProductID, item.Quantity).Get(ctx, nil)
+	if params.SplitBackorder && len(saga.status.UnfulfilledItems) > 0 {
+		// The order is paid already, so failed backorder is reported in status without cancelling it
+		// CALL BY EXPLICIT STRING NAME "CreateBackorderActivity"
+		err = workflow.ExecuteActivity(ctx, "CreateBackorderActivity", params.OrderID).Get(ctx, &saga.status.BackorderID)
+		if err != nil {
+			logger.Error("Failed to create backorder", "OrderID", params.OrderID, "Error", err)
+			saga.fail(err)
 		}
+	}
+	return saga.status, nil
+}
+
+// chargePayment creates the payment of the order and debits the wallet at once
+func (s *orderSaga) chargePayment(ctx, ctxPayment workflow.Context, amount float64) error {
+	logger := workflow.GetLogger(ctx)
+	// CALL BY EXPLICIT STRING NAME "CreatePayment"
+	err := workflow.ExecuteActivity(ctxPayment, "CreatePayment", s.params.UserID, s.params.OrderID, amount).Get(ctx, &s.status.PaymentID)
+	if err != nil {
+		logger.Error("Failed to create payment", "Error", err)
+		return err
+	}
 
-		return setOrderStatus(ctx, params.OrderID, "Cancelled")
+	// CALL BY EXPLICIT STRING NAME "SetOrderPaymentActivity"
+	err = workflow.ExecuteActivity(ctx, "SetOrderPaymentActivity", s.params.OrderID, s.status.PaymentID).Get(ctx, nil)
+	if err != nil {
+		logger.Error("Failed to set order payment", "PaymentID", s.status.PaymentID, "Error", err)
+		return err
 	}
 
-	// 3. Success
-	return setOrderStatus(ctx, params.OrderID, "Paid")
+	// CALL BY EXPLICIT STRING NAME "ChargePayment"
+	// The payment is moved to Succeeded when the wallet is debited and to Failed otherwise
+	err = workflow.ExecuteActivity(ctxPayment, "ChargePayment", s.status.PaymentID).Get(ctx, nil)
+	if err != nil {
+		logger.Error("Failed to charge payment", "PaymentID", s.status.PaymentID, "Error", err)
+		return err
+	}
+	s.charged = true
+	return nil
+}
+
+// confirmProducts keeps reserved stock taken, so the sweep of expired reservations does not release it,
+// reservation released already fails the saga
+func (s *orderSaga) confirmProducts(ctx, ctxProduct workflow.Context) error {
+	// CALL BY EXPLICIT STRING NAME "ConfirmProducts"
+	err := workflow.ExecuteActivity(ctxProduct, "ConfirmProducts", s.params.OrderID).Get(ctx, nil)
+	if err != nil {
+		workflow.GetLogger(ctx).Error("Failed to confirm products", "OrderID", s.params.OrderID, "Error", err)
+	}
+	return err
+}
+
+// holdPayment creates the payment of the order, authorizes amount on the wallet and captures it into the payment
+// once CapturePaymentSignal is received, cancellation requested while waiting leaves the hold to compensation
+func (s *orderSaga) holdPayment(ctx, ctxPayment, ctxProduct workflow.Context, amount float64) error {
+	logger := workflow.GetLogger(ctx)
+	// CALL BY EXPLICIT STRING NAME "CreatePayment"
+	err := workflow.ExecuteActivity(ctxPayment, "CreatePayment", s.params.UserID, s.params.OrderID, amount).Get(ctx, &s.status.PaymentID)
+	if err != nil {
+		logger.Error("Failed to create payment", "Error", err)
+		return err
+	}
+
+	// CALL BY EXPLICIT STRING NAME "SetOrderPaymentActivity"
+	err = workflow.ExecuteActivity(ctx, "SetOrderPaymentActivity", s.params.OrderID, s.status.PaymentID).Get(ctx, nil)
+	if err != nil {
+		logger.Error("Failed to set order payment", "PaymentID", s.status.PaymentID, "Error", err)
+		return err
+	}
+
+	// CALL BY EXPLICIT STRING NAME "AuthorizeWallet"
+	err = workflow.ExecuteActivity(ctxPayment, "AuthorizeWallet",
+		s.params.UserID, s.params.OrderID, amount, s.params.PaymentHoldTTL,
+	).Get(ctx, &s.status.HoldID)
+	if err != nil {
+		logger.Error("Failed to authorize wallet", "Error", err)
+		return err
+	}
+	// Stock is confirmed before waiting, since the hold may outlive the reservation
+	if err = s.confirmProducts(ctx, ctxProduct); err != nil {
+		return err
+	}
+
+	s.status.Step = OrderSagaAwaitingCapture
+	ok, err := workflow.AwaitWithTimeout(ctx, s.params.PaymentHoldTTL, func() bool {
+		return s.captureRequested || s.cancelRequested
+	})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		logger.Info("Order payment hold expired", "OrderID", s.params.OrderID, "HoldID", s.status.HoldID)
+		return errPaymentHoldExpired
+	}
+	if s.cancelRequested {
+		return nil
+	}
+
+	s.status.Step = OrderSagaCharging
+	// CALL BY EXPLICIT STRING NAME "CapturePayment"
+	// The payment is moved to Succeeded with the captured amount and to Failed when the hold can not be captured
+	err = workflow.ExecuteActivity(ctxPayment, "CapturePayment", s.status.PaymentID, s.status.HoldID, s.captureAmount).Get(ctx, nil)
+	if err != nil {
+		logger.Error("Failed to capture payment", "PaymentID", s.status.PaymentID, "HoldID", s.status.HoldID, "Error", err)
+		return err
+	}
+	s.charged = true
+	return nil
+}
+
+// reserveAvailable reserves items in stock and takes the rest off the order
+func (s *orderSaga) reserveAvailable(ctx, ctxProduct workflow.Context) error {
+	var reserved []OrderItemParam
+	// CALL BY EXPLICIT STRING NAME "ReserveAvailableProducts"
+	err := workflow.ExecuteActivity(ctxProduct, "ReserveAvailableProducts", s.params.OrderID, s.params.Items).Get(ctx, &reserved)
+	if err != nil {
+		return err
+	}
+	s.status.ReservedItems = reserved
+	if len(reserved) == 0 {
+		return errNothingReserved
+	}
+
+	s.status.UnfulfilledItems = unfulfilledItems(s.params.Items, reserved)
+	if len(s.status.UnfulfilledItems) == 0 {
+		return nil
+	}
+	// CALL BY EXPLICIT STRING NAME "MarkUnfulfilledItemsActivity"
+	return workflow.ExecuteActivity(ctx, "MarkUnfulfilledItemsActivity", s.params.OrderID, s.status.UnfulfilledItems).Get(ctx, &s.totalPrice)
+}
+
+// unfulfilledItems returns requested quantities which are not reserved, per product in order of request
+func unfulfilledItems(requested, reserved []OrderItemParam) []UnfulfilledItemParam {
+	var result []UnfulfilledItemParam
+	for _, item := range requested {
+		i := slices.IndexFunc(result, func(u UnfulfilledItemParam) bool {
+			return u.ProductID == item.ProductID
+		})
+		if i < 0 {
+			result = append(result, UnfulfilledItemParam{ProductID: item.ProductID, Reason: UnfulfilledReasonOutOfStock})
+			i = len(result) - 1
+		}
+		result[i].Quantity += item.Quantity
+	}
+	for _, item := range reserved {
+		i := slices.IndexFunc(result, func(u UnfulfilledItemParam) bool {
+			return u.ProductID == item.ProductID
+		})
+		if i >= 0 {
+			result[i].Quantity -= item.Quantity
+		}
+	}
+	return slices.DeleteFunc(result, func(u UnfulfilledItemParam) bool {
+		return u.Quantity <= 0
+	})
+}
+
+// cancel compensates the saga and returns its final status
+func (s *orderSaga) cancel(ctx workflow.Context) (OrderSagaStatus, error) {
+	err := s.compensate(ctx)
+	return s.status, err
+}
+
+// compensate undoes every step completed so far in reverse order and cancels the order, paid order is refunded instead
+func (s *orderSaga) compensate(ctx workflow.Context) error {
+	logger := workflow.GetLogger(ctx)
+	s.status.Step = OrderSagaCompensating
+	if s.cancelReason != "" {
+		s.status.LastError = s.cancelReason
+	}
+
+	if s.status.PaymentID != "" {
+		ctxPayment := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+			TaskQueue:           paymentTaskQueue,
+			StartToCloseTimeout: time.Minute,
+		})
+		// CALL BY EXPLICIT STRING NAME "RefundPayment"
+		// CALL BY EXPLICIT STRING NAME "CancelPayment"
+		// CancelPayment refunds the payment as well when it is charged but the result of ChargePayment is lost
+		activityName := "CancelPayment"
+		if s.charged {
+			activityName = "RefundPayment"
+		}
+		err := workflow.ExecuteActivity(ctxPayment, activityName, s.status.PaymentID).Get(ctx, nil)
+		if err != nil {
+			logger.Error("Failed to compensate payment", "PaymentID", s.status.PaymentID, "Error", err)
+			s.fail(err)
+		}
+	}
+
+	// Captured hold is refunded along with its payment, so only the hold which is not captured is voided
+	if s.status.HoldID != "" && !s.charged {
+		ctxPayment := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+			TaskQueue:           paymentTaskQueue,
+			StartToCloseTimeout: time.Minute,
+		})
+		// CALL BY EXPLICIT STRING NAME "VoidHold"
+		// Expired hold is released already, so voiding it does nothing
+		err := workflow.ExecuteActivity(ctxPayment, "VoidHold", s.status.HoldID).Get(ctx, nil)
+		if err != nil {
+			logger.Error("Failed to compensate payment hold", "HoldID", s.status.HoldID, "Error", err)
+			s.fail(err)
+		}
+	}
+
+	if s.promoRedeemed {
+		// CALL BY EXPLICIT STRING NAME "ReleasePromoCodeActivity"
+		err := workflow.ExecuteActivity(ctx, "ReleasePromoCodeActivity", s.params.OrderID).Get(ctx, nil)
+		if err != nil {
+			logger.Error("Failed to release promo code", "Error", err)
+			s.fail(err)
+		}
+	}
+
+	ctxProduct := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		TaskQueue:           productTaskQueue,
+		StartToCloseTimeout: time.Minute,
+	})
+	if s.reserving {
+		// CALL BY EXPLICIT STRING NAME "ReleaseProducts"
+		// Reservations of the order are released even when the result of reserving is lost
+		err := workflow.ExecuteActivity(ctxProduct, "ReleaseProducts", s.params.OrderID).Get(ctx, nil)
+		if err != nil {
+			logger.Error("Failed to release products", "OrderID", s.params.OrderID, "Error", err)
+			s.fail(err)
+		} else {
+			s.status.ReservedItems = nil
+		}
+	}
+
+	// Paid order can not be cancelled anymore, so it is moved to Refunded
+	finalStatus := "Cancelled"
+	if s.paid {
+		finalStatus = "Refunded"
+	}
+	err := setOrderStatus(ctx, s.params.OrderID, finalStatus)
+	if err != nil {
+		s.fail(err)
+		return err
+	}
+	s.status.Step = OrderSagaCancelled
+	return nil
 }
 
 func setOrderStatus(ctx workflow.Context, orderID, status string) error {
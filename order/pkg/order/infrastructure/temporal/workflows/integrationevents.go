@@ -0,0 +1,83 @@
+package workflows
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"order/pkg/order/domain/model"
+)
+
+type FindOrdersParams struct {
+	CustomerID string
+	ProductID  string
+	Statuses   []string
+}
+
+// openOrderStatuses are statuses of orders which are not paid yet
+var openOrderStatuses = []string{"Open", "Pending"}
+
+func integrationEventActivityOptions(ctx workflow.Context) workflow.Context {
+	return workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy: &temporal.RetryPolicy{
+			MaximumAttempts: 10,
+		},
+	})
+}
+
+// UserDeletedWorkflow cancels orders of the deleted user which are not paid yet
+func UserDeletedWorkflow(ctx workflow.Context, event model.UserDeleted) error {
+	ctx = integrationEventActivityOptions(ctx)
+
+	var orderIDs []string
+	// CALL BY EXPLICIT STRING NAME "FindOrdersActivity"
+	err := workflow.ExecuteActivity(ctx, "FindOrdersActivity", FindOrdersParams{
+		CustomerID: event.UserID,
+		Statuses:   openOrderStatuses,
+	}).Get(ctx, &orderIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, orderID := range orderIDs {
+		// CALL BY EXPLICIT STRING NAME "CancelOrderActivity"
+		err = workflow.ExecuteActivity(ctx, "CancelOrderActivity", orderID).Get(ctx, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ProductRemovedWorkflow removes the product from orders which can still be changed
+func ProductRemovedWorkflow(ctx workflow.Context, event model.ProductRemoved) error {
+	ctx = integrationEventActivityOptions(ctx)
+
+	var orderIDs []string
+	// CALL BY EXPLICIT STRING NAME "FindOrdersActivity"
+	err := workflow.ExecuteActivity(ctx, "FindOrdersActivity", FindOrdersParams{
+		ProductID: event.ProductID,
+		Statuses:  []string{"Open"},
+	}).Get(ctx, &orderIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, orderID := range orderIDs {
+		// CALL BY EXPLICIT STRING NAME "RemoveOrderProductActivity"
+		err = workflow.ExecuteActivity(ctx, "RemoveOrderProductActivity", orderID, event.ProductID).Get(ctx, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func PaymentStatusChangedWorkflow(ctx workflow.Context, event model.PaymentStatusChanged) error {
+	ctx = integrationEventActivityOptions(ctx)
+
+	// CALL BY EXPLICIT STRING NAME "ApplyPaymentStatusActivity"
+	return workflow.ExecuteActivity(ctx, "ApplyPaymentStatusActivity", event.OrderID, int(event.Status)).Get(ctx, nil)
+}
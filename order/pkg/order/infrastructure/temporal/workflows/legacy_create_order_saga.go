@@ -0,0 +1,66 @@
+package workflows
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// LegacyCreateOrderSagaName is the name CreateOrderSaga was registered under before it was reworked,
+// LegacyCreateOrderSaga is kept under it until all sagas started with it are closed
+const LegacyCreateOrderSagaName = "CreateOrderSaga"
+
+// LegacyCreateOrderSaga replays sagas started before CreateOrderSaga was registered as CreateOrderSagaName,
+// it must not be changed and is not started for new orders
+func LegacyCreateOrderSaga(ctx workflow.Context, params OrderSagaParams) error {
+	ao := workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy: &temporal.RetryPolicy{
+			MaximumAttempts: 3,
+		},
+	}
+	ctx = workflow.WithActivityOptions(ctx, ao)
+
+	logger := workflow.GetLogger(ctx)
+	logger.Info("Starting Order Saga", "OrderID", params.OrderID)
+
+	// 1. Reserve Products
+	for _, item := range params.Items {
+		ctxProduct := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+			TaskQueue:           productTaskQueue,
+			StartToCloseTimeout: time.Minute,
+		})
+
+		// CALL BY EXPLICIT STRING NAME "ReserveProduct"
+		err := workflow.ExecuteActivity(ctxProduct, "ReserveProduct", item.ProductID, item.Quantity).Get(ctx, nil)
+		if err != nil {
+			logger.Error("Failed to reserve product", "Error", err)
+			return setOrderStatus(ctx, params.OrderID, "Cancelled")
+		}
+	}
+
+	// 2. Charge Wallet
+	ctxPayment := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		TaskQueue:           paymentTaskQueue,
+		StartToCloseTimeout: time.Minute,
+	})
+
+	// CALL BY EXPLICIT STRING NAME "ChargeWallet"
+	err := workflow.ExecuteActivity(ctxPayment, "ChargeWallet", params.UserID, params.TotalPrice).Get(ctx, nil)
+	if err != nil {
+		logger.Error("Failed to charge wallet", "Error", err)
+
+		// Compensation: Release Products
+		for _, item := range params.Items {
+			ctxProduct := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{TaskQueue: productTaskQueue})
+			// CALL BY EXPLICIT STRING NAME "ReleaseProduct"
+			_ = workflow.ExecuteActivity(ctxProduct, "ReleaseProduct", item.ProductID, item.Quantity).Get(ctx, nil)
+		}
+
+		return setOrderStatus(ctx, params.OrderID, "Cancelled")
+	}
+
+	// 3. Success
+	return setOrderStatus(ctx, params.OrderID, "Paid")
+}
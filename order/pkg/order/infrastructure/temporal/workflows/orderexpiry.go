@@ -0,0 +1,29 @@
+package workflows
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+func OrderExpiryWorkflowID(orderID string) string {
+	return "order-expiry-" + orderID
+}
+
+// OrderExpiryWorkflow is started along with the order and cancels it once ttl passes if it is still not paid,
+// it runs apart from CreateOrderSaga, so orders whose saga never started or stalled expire as well
+func OrderExpiryWorkflow(ctx workflow.Context, orderID string, ttl time.Duration) error {
+	if err := workflow.Sleep(ctx, ttl); err != nil {
+		return err
+	}
+
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy: &temporal.RetryPolicy{
+			MaximumAttempts: 10,
+		},
+	})
+	// CALL BY EXPLICIT STRING NAME "ExpireOrderActivity"
+	return workflow.ExecuteActivity(ctx, "ExpireOrderActivity", orderID).Get(ctx, nil)
+}
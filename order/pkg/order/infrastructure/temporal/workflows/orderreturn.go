@@ -0,0 +1,117 @@
+package workflows
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// ReturnDecisionSignal approves or rejects the return waited by OrderReturnWorkflow, its payload is ReturnDecision
+const ReturnDecisionSignal = "return-decision"
+
+// restockReturnedProductChange is the workflow.GetVersion change ID of restocking returned products by return ID
+const restockReturnedProductChange = "restock-returned-product"
+
+// completeReturnBeforeRefundChange is the workflow.GetVersion change ID of completing the return before its refund
+const completeReturnBeforeRefundChange = "complete-return-before-refund"
+
+func OrderReturnWorkflowID(returnID string) string {
+	return "order-return-" + returnID
+}
+
+type OrderReturnParams struct {
+	ReturnID     string
+	OrderID      string
+	CustomerID   string
+	Items        []OrderItemParam
+	RefundAmount float64
+}
+
+type ReturnDecision struct {
+	Approved bool
+	// Reason is set for a rejected return
+	Reason string
+}
+
+// OrderReturnWorkflow waits for the decision on the return, approved return is restocked, completed and refunded
+func OrderReturnWorkflow(ctx workflow.Context, params OrderReturnParams) error {
+	retryPolicy := &temporal.RetryPolicy{
+		MaximumAttempts: 3,
+	}
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy:         retryPolicy,
+	})
+	logger := workflow.GetLogger(ctx)
+
+	var decision ReturnDecision
+	workflow.GetSignalChannel(ctx, ReturnDecisionSignal).Receive(ctx, &decision)
+
+	if !decision.Approved {
+		// CALL BY EXPLICIT STRING NAME "RejectReturnActivity"
+		return workflow.ExecuteActivity(ctx, "RejectReturnActivity", params.ReturnID, decision.Reason).Get(ctx, nil)
+	}
+
+	// CALL BY EXPLICIT STRING NAME "ApproveReturnActivity"
+	err := workflow.ExecuteActivity(ctx, "ApproveReturnActivity", params.ReturnID).Get(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	ctxProduct := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		TaskQueue:           productTaskQueue,
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy:         retryPolicy,
+	})
+	// Workflows started before restocking by return ID call ReleaseProduct with its original arguments
+	restockByReturn := workflow.GetVersion(ctx, restockReturnedProductChange, workflow.DefaultVersion, 1) == 1
+	for _, item := range params.Items {
+		if restockByReturn {
+			// CALL BY EXPLICIT STRING NAME "RestockReturnedProduct"
+			// Returned stock is put back once per return, so retried activity does not restock twice
+			err = workflow.ExecuteActivity(ctxProduct, "RestockReturnedProduct", params.OrderID, item.ProductID, params.ReturnID, item.Quantity).Get(ctx, nil)
+		} else {
+			// CALL BY EXPLICIT STRING NAME "ReleaseProduct"
+			err = workflow.ExecuteActivity(ctxProduct, "ReleaseProduct", item.ProductID, item.Quantity).Get(ctx, nil)
+		}
+		if err != nil {
+			logger.Error("Failed to restock returned product", "ReturnID", params.ReturnID, "ProductID", item.ProductID, "Error", err)
+			return err
+		}
+	}
+
+	// Return is completed before the refund, so the order is Returned when the full refund of the payment refunds it,
+	// workflows started before keep refunding first
+	completeFirst := workflow.GetVersion(ctx, completeReturnBeforeRefundChange, workflow.DefaultVersion, 1) == 1
+	if completeFirst {
+		if err = completeReturn(ctx, params); err != nil {
+			return err
+		}
+	}
+
+	if params.RefundAmount > 0 {
+		ctxPayment := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+			TaskQueue:           paymentTaskQueue,
+			StartToCloseTimeout: time.Minute,
+			RetryPolicy:         retryPolicy,
+		})
+		// CALL BY EXPLICIT STRING NAME "RefundReturn"
+		// Refund is taken off the order payment and keyed by the return, so the order is not refunded twice
+		err = workflow.ExecuteActivity(ctxPayment, "RefundReturn", params.OrderID, params.ReturnID, params.RefundAmount).Get(ctx, nil)
+		if err != nil {
+			logger.Error("Failed to refund returned items", "ReturnID", params.ReturnID, "Error", err)
+			return err
+		}
+	}
+
+	if completeFirst {
+		return nil
+	}
+	return completeReturn(ctx, params)
+}
+
+func completeReturn(ctx workflow.Context, params OrderReturnParams) error {
+	// CALL BY EXPLICIT STRING NAME "CompleteReturnActivity"
+	return workflow.ExecuteActivity(ctx, "CompleteReturnActivity", params.ReturnID).Get(ctx, nil)
+}
@@ -0,0 +1,58 @@
+package workflows
+
+import (
+	"errors"
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// OrderScheduleID is ID of the Temporal Schedule running ScheduledOrderWorkflow for the order template
+func OrderScheduleID(templateID string) string {
+	return "order-schedule-" + templateID
+}
+
+// ScheduledOrderWorkflow is started by the order schedule, it creates an order from the template and runs CreateOrderSaga for it
+func ScheduledOrderWorkflow(ctx workflow.Context, templateID string) error {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy: &temporal.RetryPolicy{
+			MaximumAttempts: 3,
+		},
+	})
+	logger := workflow.GetLogger(ctx)
+
+	// Workflow ID of every scheduled run is unique, so it keeps retried activity from creating another order
+	runID := workflow.GetInfo(ctx).WorkflowExecution.ID
+	var params OrderSagaParams
+	// CALL BY EXPLICIT STRING NAME "PrepareScheduledOrderActivity"
+	err := workflow.ExecuteActivity(ctx, "PrepareScheduledOrderActivity", templateID, runID).Get(ctx, &params)
+	if err != nil {
+		logger.Error("Failed to create scheduled order", "TemplateID", templateID, "Error", err)
+		return errors.Join(err, reportScheduledOrderFailure(ctx, templateID, "", err.Error()))
+	}
+
+	ctxSaga := workflow.WithChildOptions(ctx, workflow.ChildWorkflowOptions{
+		WorkflowID: OrderSagaWorkflowID(params.OrderID),
+	})
+	var sagaStatus OrderSagaStatus
+	err = workflow.ExecuteChildWorkflow(ctxSaga, CreateOrderSagaName, params).Get(ctx, &sagaStatus)
+	if err != nil {
+		logger.Error("Scheduled order saga failed", "OrderID", params.OrderID, "Error", err)
+		return errors.Join(err, reportScheduledOrderFailure(ctx, templateID, params.OrderID, err.Error()))
+	}
+	if sagaStatus.Step == OrderSagaCancelled {
+		reason := sagaStatus.LastError
+		if reason == "" {
+			reason = "order is cancelled"
+		}
+		return reportScheduledOrderFailure(ctx, templateID, params.OrderID, reason)
+	}
+	return nil
+}
+
+func reportScheduledOrderFailure(ctx workflow.Context, templateID, orderID, reason string) error {
+	// CALL BY EXPLICIT STRING NAME "ReportScheduledOrderFailureActivity"
+	return workflow.ExecuteActivity(ctx, "ReportScheduledOrderFailureActivity", templateID, orderID, reason).Get(ctx, nil)
+}
@@ -0,0 +1,129 @@
+package transport
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"order/api/server/orderinternalapi"
+	appservice "order/pkg/order/app/service"
+	"order/pkg/order/domain/model"
+	domainservice "order/pkg/order/domain/service"
+)
+
+func (o orderInternalAPI) FindCart(ctx context.Context, request *orderinternalapi.FindCartRequest) (*orderinternalapi.FindCartResponse, error) {
+	customerID, err := uuid.Parse(request.CustomerID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.CustomerID)
+	}
+
+	cart, err := o.cartService.FindCart(ctx, customerID)
+	if err != nil {
+		if errors.Is(err, model.ErrCartNotFound) {
+			return nil, status.Errorf(codes.NotFound, "cart of customer %q not found", request.CustomerID)
+		}
+		return nil, err
+	}
+
+	items := make([]*orderinternalapi.CartItem, len(cart.Items))
+	for i, item := range cart.Items {
+		items[i] = &orderinternalapi.CartItem{
+			ProductID: item.ProductID.String(),
+			Count:     int32(item.Count), // #nosec G115
+		}
+	}
+	return &orderinternalapi.FindCartResponse{
+		CartID:     cart.ID.String(),
+		CustomerID: cart.CustomerID.String(),
+		Items:      items,
+		CreatedAt:  cart.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:  cart.UpdatedAt.Format(time.RFC3339),
+	}, nil
+}
+
+func (o orderInternalAPI) AddCartItem(ctx context.Context, request *orderinternalapi.AddCartItemRequest) (*emptypb.Empty, error) {
+	customerID, productID, err := parseCartItemIDs(request.CustomerID, request.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = o.cartService.AddCartItem(ctx, customerID, productID, int(request.Count))
+	if err != nil {
+		return nil, toCartError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (o orderInternalAPI) RemoveCartItem(ctx context.Context, request *orderinternalapi.RemoveCartItemRequest) (*emptypb.Empty, error) {
+	customerID, productID, err := parseCartItemIDs(request.CustomerID, request.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = o.cartService.RemoveCartItem(ctx, customerID, productID)
+	if err != nil {
+		return nil, toCartError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (o orderInternalAPI) SetCartItemQuantity(ctx context.Context, request *orderinternalapi.SetCartItemQuantityRequest) (*emptypb.Empty, error) {
+	customerID, productID, err := parseCartItemIDs(request.CustomerID, request.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = o.cartService.SetCartItemQuantity(ctx, customerID, productID, int(request.Count))
+	if err != nil {
+		return nil, toCartError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (o orderInternalAPI) Checkout(ctx context.Context, request *orderinternalapi.CheckoutRequest) (*orderinternalapi.CheckoutResponse, error) {
+	customerID, err := uuid.Parse(request.CustomerID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.CustomerID)
+	}
+
+	orderID, err := o.cartService.Checkout(ctx, customerID)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrCartNotFound):
+			return nil, status.Errorf(codes.NotFound, "cart of customer %q not found", request.CustomerID)
+		case errors.Is(err, appservice.ErrEmptyCart):
+			return nil, status.Errorf(codes.FailedPrecondition, "cart of customer %q is empty", request.CustomerID)
+		}
+		return nil, toCartError(err)
+	}
+	return &orderinternalapi.CheckoutResponse{
+		OrderID: orderID.String(),
+	}, nil
+}
+
+func parseCartItemIDs(customerID, productID string) (uuid.UUID, uuid.UUID, error) {
+	cID, err := uuid.Parse(customerID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", customerID)
+	}
+	pID, err := uuid.Parse(productID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", productID)
+	}
+	return cID, pID, nil
+}
+
+func toCartError(err error) error {
+	switch {
+	case errors.Is(err, appservice.ErrProductNotFound),
+		errors.Is(err, appservice.ErrProductRemoved),
+		errors.Is(err, domainservice.ErrInvalidItemCount):
+		return status.Error(codes.InvalidArgument, errors.Cause(err).Error())
+	}
+	return err
+}
@@ -5,28 +5,46 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
 
 	"order/api/server/orderinternalapi"
+	"order/pkg/common/idempotency"
 	appdata "order/pkg/order/app/data"
 	appquery "order/pkg/order/app/query"
 	appservice "order/pkg/order/app/service"
+	"order/pkg/order/domain/model"
+	domainservice "order/pkg/order/domain/service"
 )
 
 func NewOrderInternalAPI(
 	orderQueryService appquery.OrderQueryService,
 	orderService appservice.OrderService,
+	cartService appservice.CartService,
+	promotionService appservice.PromotionService,
+	orderTemplateService appservice.OrderTemplateService,
+	orderReturnService appservice.OrderReturnService,
 ) orderinternalapi.OrderInternalAPIServer {
 	return &orderInternalAPI{
-		orderQueryService: orderQueryService,
-		orderService:      orderService,
+		orderQueryService:    orderQueryService,
+		orderService:         orderService,
+		cartService:          cartService,
+		promotionService:     promotionService,
+		orderTemplateService: orderTemplateService,
+		orderReturnService:   orderReturnService,
 	}
 }
 
 type orderInternalAPI struct {
-	orderQueryService appquery.OrderQueryService
-	orderService      appservice.OrderService
+	orderQueryService    appquery.OrderQueryService
+	orderService         appservice.OrderService
+	cartService          appservice.CartService
+	promotionService     appservice.PromotionService
+	orderTemplateService appservice.OrderTemplateService
+	orderReturnService   appservice.OrderReturnService
 
 	orderinternalapi.UnimplementedOrderInternalAPIServer
 }
@@ -42,6 +60,17 @@ func (o orderInternalAPI) StoreOrder(ctx context.Context, request *orderinternal
 			return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.OrderID)
 		}
 	}
+	if request.SplitBackorder && !request.AllowPartial {
+		return nil, status.Error(codes.InvalidArgument, "splitBackorder requires allowPartial")
+	}
+	var addressID *uuid.UUID
+	if request.AddressID != nil {
+		aID, err := uuid.Parse(*request.AddressID)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", *request.AddressID)
+		}
+		addressID = &aID
+	}
 
 	customerID, err := uuid.Parse(request.CustomerID)
 	if err != nil {
@@ -59,20 +88,37 @@ func (o orderInternalAPI) StoreOrder(ctx context.Context, request *orderinternal
 			return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", item.ProductID)
 		}
 		items[i] = appdata.OrderItem{
-			OrderID:    orderItemID,
-			ProductID:  productID,
-			Count:      int(item.Count),
-			TotalPrice: item.TotalPrice,
+			OrderID:   orderItemID,
+			ProductID: productID,
+			Count:     int(item.Count),
 		}
 	}
 
 	orderID, err = o.orderService.StoreOrder(ctx, appdata.Order{
-		ID:         orderID,
-		CustomerID: customerID,
-		Status:     appdata.OrderStatus(request.Status),
-		Items:      items,
-	})
+		ID:             orderID,
+		CustomerID:     customerID,
+		Status:         appdata.OrderStatus(request.Status),
+		Items:          items,
+		AllowPartial:   request.AllowPartial,
+		SplitBackorder: request.SplitBackorder,
+		HoldPayment:    request.HoldPayment,
+		AddressID:      addressID,
+		DeliveryMethod: appdata.DeliveryMethod(request.DeliveryMethod),
+	}, idempotencyKey(ctx, request.IdempotencyKey))
 	if err != nil {
+		switch {
+		case errors.Is(err, idempotency.ErrKeyConflict):
+			return nil, status.Error(codes.AlreadyExists, errors.Cause(err).Error())
+		case errors.Is(err, appservice.ErrProductNotFound),
+			errors.Is(err, appservice.ErrProductRemoved),
+			errors.Is(err, domainservice.ErrInvalidItemCount),
+			errors.Is(err, appservice.ErrAddressNotFound),
+			errors.Is(err, domainservice.ErrInvalidDeliveryMethod),
+			errors.Is(err, domainservice.ErrShippingAddressRequired):
+			return nil, status.Error(codes.InvalidArgument, errors.Cause(err).Error())
+		case errors.Is(err, appservice.ErrPaymentHoldDisabled):
+			return nil, status.Error(codes.FailedPrecondition, errors.Cause(err).Error())
+		}
 		return nil, err
 	}
 
@@ -86,7 +132,7 @@ func (o orderInternalAPI) FindOrder(ctx context.Context, request *orderinternala
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.OrderID)
 	}
-	order, err := o.orderQueryService.FindUser(ctx, orderID)
+	order, err := o.orderQueryService.FindOrder(ctx, orderID)
 	if err != nil {
 		return nil, err
 	}
@@ -94,6 +140,228 @@ func (o orderInternalAPI) FindOrder(ctx context.Context, request *orderinternala
 		return nil, status.Errorf(codes.NotFound, "order %q not found", request.OrderID)
 	}
 
+	return toFindOrderResponse(*order), nil
+}
+
+func (o orderInternalAPI) ListOrders(ctx context.Context, request *orderinternalapi.ListOrdersRequest) (*orderinternalapi.ListOrdersResponse, error) {
+	spec := appdata.ListOrdersSpec{
+		Statuses:       make([]appdata.OrderStatus, len(request.Statuses)),
+		IncludeDeleted: request.IncludeDeleted,
+		Limit:          int(request.Limit),
+		Cursor:         request.Cursor,
+	}
+	if request.CustomerID != nil {
+		customerID, err := uuid.Parse(*request.CustomerID)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", *request.CustomerID)
+		}
+		spec.CustomerID = &customerID
+	}
+	for i, s := range request.Statuses {
+		spec.Statuses[i] = appdata.OrderStatus(s)
+	}
+
+	var err error
+	if spec.CreatedFrom, err = parseOptionalTime(request.CreatedFrom); err != nil {
+		return nil, err
+	}
+	if spec.CreatedTo, err = parseOptionalTime(request.CreatedTo); err != nil {
+		return nil, err
+	}
+	if spec.UpdatedFrom, err = parseOptionalTime(request.UpdatedFrom); err != nil {
+		return nil, err
+	}
+	if spec.UpdatedTo, err = parseOptionalTime(request.UpdatedTo); err != nil {
+		return nil, err
+	}
+
+	page, err := o.orderQueryService.ListOrders(ctx, spec)
+	if err != nil {
+		if errors.Is(err, appquery.ErrInvalidCursor) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid cursor %q", request.Cursor)
+		}
+		return nil, err
+	}
+
+	orders := make([]*orderinternalapi.FindOrderResponse, len(page.Orders))
+	for i, order := range page.Orders {
+		orders[i] = toFindOrderResponse(order)
+	}
+
+	return &orderinternalapi.ListOrdersResponse{
+		Orders:     orders,
+		NextCursor: page.NextCursor,
+	}, nil
+}
+
+func (o orderInternalAPI) CancelOrder(ctx context.Context, request *orderinternalapi.CancelOrderRequest) (*emptypb.Empty, error) {
+	orderID, err := uuid.Parse(request.OrderID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.OrderID)
+	}
+
+	err = o.orderService.CancelOrder(ctx, orderID)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrOrderNotFound):
+			return nil, status.Errorf(codes.NotFound, "order %q not found", request.OrderID)
+		case errors.Is(err, domainservice.ErrInvalidOrderStatus):
+			return nil, status.Errorf(codes.FailedPrecondition, "order %q can not be cancelled", request.OrderID)
+		}
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (o orderInternalAPI) UpdateOrderStatus(ctx context.Context, request *orderinternalapi.UpdateOrderStatusRequest) (*emptypb.Empty, error) {
+	orderID, err := uuid.Parse(request.OrderID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.OrderID)
+	}
+
+	err = o.orderService.UpdateOrderStatus(ctx, orderID, appdata.OrderStatus(request.Status))
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrOrderNotFound):
+			return nil, status.Errorf(codes.NotFound, "order %q not found", request.OrderID)
+		case errors.Is(err, domainservice.ErrInvalidOrderStatus):
+			return nil, status.Errorf(codes.FailedPrecondition, "order %q can not be moved to %s", request.OrderID, request.Status)
+		}
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (o orderInternalAPI) GetOrderSagaStatus(ctx context.Context, request *orderinternalapi.GetOrderSagaStatusRequest) (*orderinternalapi.GetOrderSagaStatusResponse, error) {
+	orderID, err := uuid.Parse(request.OrderID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.OrderID)
+	}
+
+	sagaStatus, err := o.orderService.GetOrderSagaStatus(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, appservice.ErrOrderSagaNotFound) {
+			return nil, status.Errorf(codes.NotFound, "saga for order %q not found", request.OrderID)
+		}
+		return nil, err
+	}
+
+	reservedItems := make([]*orderinternalapi.OrderSagaItem, len(sagaStatus.ReservedItems))
+	for i, item := range sagaStatus.ReservedItems {
+		reservedItems[i] = &orderinternalapi.OrderSagaItem{
+			ProductID: item.ProductID.String(),
+			Quantity:  int32(item.Quantity), // #nosec G115
+		}
+	}
+
+	unfulfilledItems := make([]*orderinternalapi.OrderSagaItem, len(sagaStatus.UnfulfilledItems))
+	for i, item := range sagaStatus.UnfulfilledItems {
+		unfulfilledItems[i] = &orderinternalapi.OrderSagaItem{
+			ProductID: item.ProductID.String(),
+			Quantity:  int32(item.Quantity), // #nosec G115
+		}
+	}
+
+	response := &orderinternalapi.GetOrderSagaStatusResponse{
+		OrderID:          sagaStatus.OrderID.String(),
+		Step:             orderinternalapi.OrderSagaStep(sagaStatus.Step), // nolint:gosec
+		CurrentItem:      int32(sagaStatus.CurrentItem),                   // #nosec G115
+		ReservedItems:    reservedItems,
+		LastError:        sagaStatus.LastError,
+		UnfulfilledItems: unfulfilledItems,
+	}
+	if sagaStatus.BackorderID != nil {
+		backorderIDStr := sagaStatus.BackorderID.String()
+		response.BackorderID = &backorderIDStr
+	}
+	if sagaStatus.HoldID != nil {
+		holdIDStr := sagaStatus.HoldID.String()
+		response.HoldID = &holdIDStr
+	}
+	return response, nil
+}
+
+func (o orderInternalAPI) CaptureOrderPayment(ctx context.Context, request *orderinternalapi.CaptureOrderPaymentRequest) (*emptypb.Empty, error) {
+	orderID, err := uuid.Parse(request.OrderID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.OrderID)
+	}
+
+	err = o.orderService.CaptureOrderPayment(ctx, orderID, request.GetAmount())
+	if err != nil {
+		switch {
+		case errors.Is(err, appservice.ErrOrderSagaNotFound):
+			return nil, status.Errorf(codes.NotFound, "saga for order %q not found", request.OrderID)
+		case errors.Is(err, appservice.ErrInvalidCaptureAmount):
+			return nil, status.Error(codes.InvalidArgument, "amount must not be negative")
+		case errors.Is(err, appservice.ErrOrderNotAwaitingCapture):
+			return nil, status.Error(codes.FailedPrecondition, errors.Cause(err).Error())
+		}
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (o orderInternalAPI) SubmitBackorder(ctx context.Context, request *orderinternalapi.SubmitBackorderRequest) (*emptypb.Empty, error) {
+	orderID, err := uuid.Parse(request.OrderID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.OrderID)
+	}
+
+	err = o.orderService.SubmitBackorder(ctx, orderID)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrOrderNotFound):
+			return nil, status.Errorf(codes.NotFound, "order %q not found", request.OrderID)
+		case errors.Is(err, appservice.ErrNotBackorder),
+			errors.Is(err, appservice.ErrOrderSagaAlreadyStarted),
+			errors.Is(err, domainservice.ErrInvalidOrderStatus):
+			return nil, status.Error(codes.FailedPrecondition, errors.Cause(err).Error())
+		}
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (o orderInternalAPI) GetOrderHistory(ctx context.Context, request *orderinternalapi.GetOrderHistoryRequest) (*orderinternalapi.GetOrderHistoryResponse, error) {
+	orderID, err := uuid.Parse(request.OrderID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.OrderID)
+	}
+
+	history, err := o.orderQueryService.GetOrderHistory(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, model.ErrOrderNotFound) {
+			return nil, status.Errorf(codes.NotFound, "order %q not found", request.OrderID)
+		}
+		return nil, err
+	}
+
+	entries := make([]*orderinternalapi.OrderHistoryEntry, len(history))
+	for i, entry := range history {
+		entries[i] = &orderinternalapi.OrderHistoryEntry{
+			EventType:  entry.EventType,
+			Actor:      entry.Actor,
+			FromStatus: toOptionalOrderStatus(entry.FromStatus),
+			ToStatus:   toOptionalOrderStatus(entry.ToStatus),
+			Details:    entry.Details,
+			CreatedAt:  entry.CreatedAt.Format(time.RFC3339Nano),
+		}
+	}
+	return &orderinternalapi.GetOrderHistoryResponse{
+		Entries: entries,
+	}, nil
+}
+
+func toOptionalOrderStatus(status *appdata.OrderStatus) *orderinternalapi.OrderStatus {
+	if status == nil {
+		return nil
+	}
+	s := orderinternalapi.OrderStatus(*status) // nolint:gosec
+	return &s
+}
+
+func toFindOrderResponse(order appdata.Order) *orderinternalapi.FindOrderResponse {
 	items := make([]*orderinternalapi.OrderItem, len(order.Items))
 	for i, item := range order.Items {
 		items[i] = &orderinternalapi.OrderItem{
@@ -101,21 +369,87 @@ func (o orderInternalAPI) FindOrder(ctx context.Context, request *orderinternala
 			ProductID:  item.ProductID.String(),
 			Count:      int32(item.Count), // #nosec G115
 			TotalPrice: item.TotalPrice,
+			Price:      item.Price,
 		}
 	}
 
 	response := &orderinternalapi.FindOrderResponse{
-		OrderID:    orderID.String(),
+		OrderID:    order.ID.String(),
 		Status:     orderinternalapi.OrderStatus(order.Status), // nolint:gosec
 		CustomerID: order.CustomerID.String(),
 		Items:      items,
 		CreatedAt:  order.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:  order.UpdatedAt.Format(time.RFC3339),
+		Discount:   order.Discount,
+
+		AllowPartial:     order.AllowPartial,
+		SplitBackorder:   order.SplitBackorder,
+		HoldPayment:      order.HoldPayment,
+		UnfulfilledItems: make([]*orderinternalapi.UnfulfilledItem, len(order.UnfulfilledItems)),
+
+		DeliveryMethod: orderinternalapi.DeliveryMethod(order.DeliveryMethod), // nolint:gosec
+		DeliveryFee:    order.DeliveryFee,
+	}
+	if order.ShippingAddress != nil {
+		response.ShippingAddress = &orderinternalapi.ShippingAddress{
+			Recipient:  order.ShippingAddress.Recipient,
+			Phone:      order.ShippingAddress.Phone,
+			Country:    order.ShippingAddress.Country,
+			City:       order.ShippingAddress.City,
+			Street:     order.ShippingAddress.Street,
+			PostalCode: order.ShippingAddress.PostalCode,
+		}
+	}
+	for i, item := range order.UnfulfilledItems {
+		response.UnfulfilledItems[i] = &orderinternalapi.UnfulfilledItem{
+			ProductID: item.ProductID.String(),
+			Count:     int32(item.Count), // #nosec G115
+			Price:     item.Price,
+			Reason:    item.Reason,
+		}
+	}
+	if order.ParentOrderID != nil {
+		parentOrderIDStr := order.ParentOrderID.String()
+		response.ParentOrderID = &parentOrderIDStr
+	}
+	if order.BackorderID != nil {
+		backorderIDStr := order.BackorderID.String()
+		response.BackorderID = &backorderIDStr
+	}
+	if order.PaymentID != nil {
+		paymentIDStr := order.PaymentID.String()
+		response.PaymentID = &paymentIDStr
 	}
 	if order.DeletedAt != nil {
 		deletedAtStr := order.DeletedAt.Format(time.RFC3339)
 		response.DeletedAt = &deletedAtStr
 	}
+	if order.PromotionID != nil {
+		promotionIDStr := order.PromotionID.String()
+		response.PromotionID = &promotionIDStr
+	}
+	return response
+}
 
-	return response, nil
+func parseOptionalTime(value *string) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, *value)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid time %q, expected RFC3339", *value)
+	}
+	return &t, nil
+}
+
+const idempotencyKeyMetadata = "idempotency-key"
+
+func idempotencyKey(ctx context.Context, key *string) string {
+	if key != nil && *key != "" {
+		return *key
+	}
+	if values := metadata.ValueFromIncomingContext(ctx, idempotencyKeyMetadata); len(values) > 0 {
+		return values[0]
+	}
+	return ""
 }
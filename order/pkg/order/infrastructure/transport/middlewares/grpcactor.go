@@ -0,0 +1,26 @@
+package middlewares
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	appservice "order/pkg/order/app/service"
+)
+
+const (
+	actorMetadata = "actor"
+	defaultActor  = "api"
+)
+
+// NewGRPCActorMiddleware takes actor of the call from "actor" metadata, so it gets into order history
+func NewGRPCActorMiddleware() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		actor := defaultActor
+		if values := metadata.ValueFromIncomingContext(ctx, actorMetadata); len(values) > 0 && values[0] != "" {
+			actor = values[0]
+		}
+		return handler(appservice.WithActor(ctx, actor), req)
+	}
+}
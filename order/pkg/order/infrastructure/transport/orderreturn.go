@@ -0,0 +1,79 @@
+package transport
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"order/api/server/orderinternalapi"
+	appdata "order/pkg/order/app/data"
+	"order/pkg/order/domain/model"
+	domainservice "order/pkg/order/domain/service"
+)
+
+func (o orderInternalAPI) RequestReturn(ctx context.Context, request *orderinternalapi.RequestReturnRequest) (*orderinternalapi.RequestReturnResponse, error) {
+	orderID, err := uuid.Parse(request.OrderID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.OrderID)
+	}
+	items := make([]appdata.OrderReturnItem, len(request.Items))
+	for i, item := range request.Items {
+		productID, err := uuid.Parse(item.ProductID)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", item.ProductID)
+		}
+		items[i] = appdata.OrderReturnItem{
+			ProductID: productID,
+			Count:     int(item.Count),
+		}
+	}
+
+	returnID, err := o.orderReturnService.RequestReturn(ctx, orderID, items, request.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrOrderNotFound):
+			return nil, status.Errorf(codes.NotFound, "order %q not found", request.OrderID)
+		case errors.Is(err, domainservice.ErrEmptyOrderReturn),
+			errors.Is(err, domainservice.ErrInvalidItemCount):
+			return nil, status.Error(codes.InvalidArgument, errors.Cause(err).Error())
+		case errors.Is(err, domainservice.ErrOrderNotReturnable),
+			errors.Is(err, domainservice.ErrOrderReturnInProgress):
+			return nil, status.Error(codes.FailedPrecondition, errors.Cause(err).Error())
+		}
+		return nil, err
+	}
+	return &orderinternalapi.RequestReturnResponse{
+		ReturnID: returnID.String(),
+	}, nil
+}
+
+func (o orderInternalAPI) ApproveReturn(ctx context.Context, request *orderinternalapi.ApproveReturnRequest) (*emptypb.Empty, error) {
+	return o.decideReturn(ctx, request.ReturnID, true, "")
+}
+
+func (o orderInternalAPI) RejectReturn(ctx context.Context, request *orderinternalapi.RejectReturnRequest) (*emptypb.Empty, error) {
+	return o.decideReturn(ctx, request.ReturnID, false, request.Reason)
+}
+
+func (o orderInternalAPI) decideReturn(ctx context.Context, returnIDStr string, approved bool, reason string) (*emptypb.Empty, error) {
+	returnID, err := uuid.Parse(returnIDStr)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", returnIDStr)
+	}
+
+	err = o.orderReturnService.DecideReturn(ctx, returnID, approved, reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrOrderReturnNotFound):
+			return nil, status.Errorf(codes.NotFound, "return %q not found", returnIDStr)
+		case errors.Is(err, domainservice.ErrInvalidOrderReturnStatus):
+			return nil, status.Errorf(codes.FailedPrecondition, "return %q is decided already", returnIDStr)
+		}
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
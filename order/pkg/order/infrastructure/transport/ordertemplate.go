@@ -0,0 +1,147 @@
+package transport
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.temporal.io/api/serviceerror"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"order/api/server/orderinternalapi"
+	appdata "order/pkg/order/app/data"
+	"order/pkg/order/domain/model"
+	domainservice "order/pkg/order/domain/service"
+)
+
+func (o orderInternalAPI) CreateOrderTemplate(ctx context.Context, request *orderinternalapi.CreateOrderTemplateRequest) (*orderinternalapi.CreateOrderTemplateResponse, error) {
+	customerID, err := uuid.Parse(request.CustomerID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.CustomerID)
+	}
+	items := make([]appdata.OrderTemplateItem, len(request.Items))
+	for i, item := range request.Items {
+		productID, err := uuid.Parse(item.ProductID)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", item.ProductID)
+		}
+		items[i] = appdata.OrderTemplateItem{
+			ProductID: productID,
+			Count:     int(item.Count),
+		}
+	}
+
+	templateID, err := o.orderTemplateService.CreateOrderTemplate(ctx, customerID, items)
+	if err != nil {
+		if errors.Is(err, domainservice.ErrEmptyOrderTemplate) || errors.Is(err, domainservice.ErrInvalidItemCount) {
+			return nil, status.Error(codes.InvalidArgument, errors.Cause(err).Error())
+		}
+		return nil, err
+	}
+	return &orderinternalapi.CreateOrderTemplateResponse{
+		TemplateID: templateID.String(),
+	}, nil
+}
+
+func (o orderInternalAPI) ScheduleOrderTemplate(ctx context.Context, request *orderinternalapi.ScheduleOrderTemplateRequest) (*emptypb.Empty, error) {
+	templateID, err := uuid.Parse(request.TemplateID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.TemplateID)
+	}
+	schedule := appdata.OrderSchedule{
+		CronExpression: request.GetCronExpression(),
+		Interval:       time.Duration(request.GetIntervalSeconds()) * time.Second,
+	}
+
+	err = o.orderTemplateService.ScheduleOrderTemplate(ctx, templateID, schedule)
+	if err != nil {
+		// Temporal validates cron expression when the schedule is created
+		var invalidArgument *serviceerror.InvalidArgument
+		switch {
+		case errors.Is(err, model.ErrOrderTemplateNotFound):
+			return nil, status.Errorf(codes.NotFound, "order template %q not found", request.TemplateID)
+		case errors.Is(err, domainservice.ErrInvalidOrderSchedule),
+			errors.As(err, &invalidArgument):
+			return nil, status.Error(codes.InvalidArgument, errors.Cause(err).Error())
+		case errors.Is(err, domainservice.ErrOrderTemplateAlreadyScheduled):
+			return nil, status.Error(codes.FailedPrecondition, errors.Cause(err).Error())
+		}
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (o orderInternalAPI) PauseOrderSchedule(ctx context.Context, request *orderinternalapi.PauseOrderScheduleRequest) (*emptypb.Empty, error) {
+	templateID, err := uuid.Parse(request.TemplateID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.TemplateID)
+	}
+	err = o.orderTemplateService.PauseOrderSchedule(ctx, templateID)
+	if err != nil {
+		return nil, orderScheduleError(err, request.TemplateID)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (o orderInternalAPI) ResumeOrderSchedule(ctx context.Context, request *orderinternalapi.ResumeOrderScheduleRequest) (*emptypb.Empty, error) {
+	templateID, err := uuid.Parse(request.TemplateID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.TemplateID)
+	}
+	err = o.orderTemplateService.ResumeOrderSchedule(ctx, templateID)
+	if err != nil {
+		return nil, orderScheduleError(err, request.TemplateID)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (o orderInternalAPI) ListOrderSchedules(ctx context.Context, request *orderinternalapi.ListOrderSchedulesRequest) (*orderinternalapi.ListOrderSchedulesResponse, error) {
+	customerID, err := uuid.Parse(request.CustomerID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.CustomerID)
+	}
+	templates, err := o.orderQueryService.ListOrderSchedules(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make([]*orderinternalapi.OrderSchedule, len(templates))
+	for i, template := range templates {
+		items := make([]*orderinternalapi.OrderTemplateItem, len(template.Items))
+		for j, item := range template.Items {
+			items[j] = &orderinternalapi.OrderTemplateItem{
+				ProductID: item.ProductID.String(),
+				Count:     int32(item.Count), // #nosec G115
+			}
+		}
+		schedule := &orderinternalapi.OrderSchedule{
+			TemplateID: template.ID.String(),
+			CustomerID: template.CustomerID.String(),
+			Items:      items,
+			Paused:     template.Schedule.Paused,
+			CreatedAt:  template.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:  template.UpdatedAt.Format(time.RFC3339),
+		}
+		if template.Schedule.CronExpression != "" {
+			schedule.CronExpression = &template.Schedule.CronExpression
+		} else {
+			intervalSeconds := int64(template.Schedule.Interval / time.Second)
+			schedule.IntervalSeconds = &intervalSeconds
+		}
+		schedules[i] = schedule
+	}
+	return &orderinternalapi.ListOrderSchedulesResponse{Schedules: schedules}, nil
+}
+
+func orderScheduleError(err error, templateID string) error {
+	switch {
+	case errors.Is(err, model.ErrOrderTemplateNotFound):
+		return status.Errorf(codes.NotFound, "order template %q not found", templateID)
+	case errors.Is(err, domainservice.ErrOrderTemplateNotScheduled):
+		return status.Error(codes.FailedPrecondition, errors.Cause(err).Error())
+	}
+	return err
+}
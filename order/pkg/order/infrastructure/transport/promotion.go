@@ -0,0 +1,72 @@
+package transport
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"order/api/server/orderinternalapi"
+	appdata "order/pkg/order/app/data"
+	"order/pkg/order/domain/model"
+	domainservice "order/pkg/order/domain/service"
+)
+
+func (o orderInternalAPI) CreatePromotion(ctx context.Context, request *orderinternalapi.CreatePromotionRequest) (*orderinternalapi.CreatePromotionResponse, error) {
+	promotion := appdata.Promotion{
+		Code:             request.Code,
+		Kind:             appdata.PromotionKind(request.Kind),
+		Value:            request.Value,
+		MinOrderTotal:    request.MinOrderTotal,
+		PerCustomerLimit: int(request.PerCustomerLimit),
+	}
+	var err error
+	if promotion.ValidFrom, err = parseOptionalTime(request.ValidFrom); err != nil {
+		return nil, err
+	}
+	if promotion.ValidTo, err = parseOptionalTime(request.ValidTo); err != nil {
+		return nil, err
+	}
+
+	promotionID, err := o.promotionService.CreatePromotion(ctx, promotion)
+	if err != nil {
+		switch {
+		case errors.Is(err, domainservice.ErrInvalidPromotion):
+			return nil, status.Error(codes.InvalidArgument, errors.Cause(err).Error())
+		case errors.Is(err, domainservice.ErrPromoCodeAlreadyExists):
+			return nil, status.Errorf(codes.AlreadyExists, "promo code %q already exists", request.Code)
+		}
+		return nil, err
+	}
+	return &orderinternalapi.CreatePromotionResponse{
+		PromotionID: promotionID.String(),
+	}, nil
+}
+
+func (o orderInternalAPI) ApplyPromoCode(ctx context.Context, request *orderinternalapi.ApplyPromoCodeRequest) (*emptypb.Empty, error) {
+	orderID, err := uuid.Parse(request.OrderID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.OrderID)
+	}
+
+	err = o.promotionService.ApplyPromoCode(ctx, orderID, request.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrOrderNotFound):
+			return nil, status.Errorf(codes.NotFound, "order %q not found", request.OrderID)
+		case errors.Is(err, model.ErrPromotionNotFound):
+			return nil, status.Errorf(codes.NotFound, "promo code %q not found", request.Code)
+		case errors.Is(err, domainservice.ErrInvalidOrderStatus),
+			errors.Is(err, domainservice.ErrPromoCodeAlreadyRedeemed),
+			errors.Is(err, domainservice.ErrPromotionNotActive),
+			errors.Is(err, domainservice.ErrOrderTotalTooLow),
+			errors.Is(err, domainservice.ErrPromotionUsageLimitReached):
+			return nil, status.Error(codes.FailedPrecondition, errors.Cause(err).Error())
+		}
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
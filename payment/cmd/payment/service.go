@@ -20,6 +20,7 @@ import (
 	"payment/pkg/payment/infrastructure/integrationevent"
 	inframysql "payment/pkg/payment/infrastructure/mysql"
 	"payment/pkg/payment/infrastructure/mysql/query"
+	"payment/pkg/payment/infrastructure/temporal"
 	"payment/pkg/payment/infrastructure/transport"
 	"payment/pkg/payment/infrastructure/transport/middlewares"
 )
@@ -27,6 +28,7 @@ import (
 type serviceConfig struct {
 	Service  Service  `envconfig:"service"`
 	Database Database `envconfig:"database" required:"true"`
+	Temporal Temporal `envconfig:"temporal" required:"true"`
 }
 
 func service(logger logging.Logger) *cli.Command {
@@ -51,6 +53,15 @@ func service(logger logging.Logger) *cli.Command {
 			closer.AddCloser(databaseConnector)
 			databaseConnectionPool := mysql.NewConnectionPool(databaseConnector.TransactionalClient())
 
+			temporalClient, err := temporal.NewClient(logger, cnf.Temporal.Host)
+			if err != nil {
+				return err
+			}
+			closer.AddCloser(libio.CloserFunc(func() error {
+				temporalClient.Close()
+				return nil
+			}))
+
 			libUoW := mysql.NewUnitOfWork(databaseConnectionPool, inframysql.NewRepositoryProvider)
 			libLUow := mysql.NewLockableUnitOfWork(libUoW, mysql.NewLocker(databaseConnectionPool))
 			uow := inframysql.NewUnitOfWork(libUoW)
@@ -62,6 +73,7 @@ func service(logger logging.Logger) *cli.Command {
 				appservice.NewPaymentService(uow, luow, eventDispatcher),
 				query.NewWalletQueryService(databaseConnector.TransactionalClient()),
 				appservice.NewWalletService(uow, luow, eventDispatcher),
+				appservice.NewWalletHoldService(luow, eventDispatcher, temporal.NewWorkflowService(temporalClient)),
 			)
 
 			errGroup := errgroup.Group{}
@@ -72,6 +84,7 @@ func service(logger logging.Logger) *cli.Command {
 				}
 				grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(
 					middlewares.NewGRPCLoggingMiddleware(logger),
+					transport.NewGRPCErrorMiddleware(),
 				))
 				paymentinternal.RegisterPaymentInternalAPIServer(grpcServer, userPublicAPIServer)
 				graceCallback(c.Context, logger, cnf.Service.GracePeriod, func(_ context.Context) error {
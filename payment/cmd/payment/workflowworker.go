@@ -67,6 +67,8 @@ func workflowWorker(logger logging.Logger) *cli.Command {
 				w := worker.NewWorker(
 					temporalClient,
 					appservice.NewWalletService(uow, luow, eventDispatcher),
+					appservice.NewPaymentService(uow, luow, eventDispatcher),
+					appservice.NewWalletHoldService(luow, eventDispatcher, temporal.NewWorkflowService(temporalClient)),
 				)
 				logger.Info("Worker created, starting...")
 				err := w.Run(worker.InterruptChannel())
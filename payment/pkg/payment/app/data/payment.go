@@ -14,6 +14,7 @@ const (
 	Succeeded
 	Failed
 	Cancelled
+	Refunded
 )
 
 type Payment struct {
@@ -26,3 +27,17 @@ type Payment struct {
 	UpdatedAt time.Time
 	DeletedAt *time.Time
 }
+
+type ListPaymentsSpec struct {
+	WalletID *uuid.UUID
+	OrderID  *uuid.UUID
+	Limit    int
+	// Cursor is an opaque token returned as PaymentPage.NextCursor, empty for the first page
+	Cursor string
+}
+
+type PaymentPage struct {
+	Payments []Payment
+	// NextCursor is empty when there are no more payments
+	NextCursor string
+}
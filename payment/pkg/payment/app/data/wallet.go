@@ -7,10 +7,72 @@ import (
 )
 
 type Wallet struct {
+	ID      uuid.UUID
+	UserID  uuid.UUID
+	Balance float64
+	// AvailableBalance is the balance minus active holds
+	AvailableBalance float64
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	DeletedAt        *time.Time
+}
+
+type WalletHoldStatus int
+
+const (
+	HoldActive WalletHoldStatus = iota
+	HoldCaptured
+	HoldVoided
+	HoldExpired
+)
+
+type WalletHold struct {
+	ID             uuid.UUID
+	WalletID       uuid.UUID
+	OrderID        uuid.UUID
+	Amount         float64
+	CapturedAmount float64
+	Status         WalletHoldStatus
+	ExpiresAt      time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type WalletTransactionDirection int
+
+const (
+	Debit WalletTransactionDirection = iota
+	Credit
+)
+
+type WalletTransactionReason int
+
+const (
+	Charge WalletTransactionReason = iota
+	Refund
+	TopUp
+	Adjustment
+)
+
+type WalletTransaction struct {
 	ID        uuid.UUID
-	UserID    uuid.UUID
-	Balance   float64
+	WalletID  uuid.UUID
+	Amount    float64
+	Direction WalletTransactionDirection
+	Reason    WalletTransactionReason
+	OrderID   *uuid.UUID
 	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt *time.Time
+}
+
+type ListWalletTransactionsSpec struct {
+	WalletID uuid.UUID
+	Limit    int
+	// Cursor is an opaque token returned as WalletTransactionPage.NextCursor, empty for the first page
+	Cursor string
+}
+
+type WalletTransactionPage struct {
+	Transactions []WalletTransaction
+	// NextCursor is empty when there are no more transactions
+	NextCursor string
 }
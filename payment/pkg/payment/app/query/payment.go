@@ -10,4 +10,6 @@ import (
 
 type PaymentQueryService interface {
 	FindPayment(ctx context.Context, paymentID uuid.UUID) (*data.Payment, error)
+	// ListPayments returns payments which are not removed from the oldest one
+	ListPayments(ctx context.Context, spec data.ListPaymentsSpec) (*data.PaymentPage, error)
 }
@@ -2,12 +2,19 @@ package query
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
 
 	"payment/pkg/payment/app/data"
 )
 
+var ErrInvalidCursor = errors.New("invalid cursor")
+
 type WalletQueryService interface {
 	FindWallet(ctx context.Context, walletID uuid.UUID) (*data.Wallet, error)
+	// FindWalletByUser returns the wallet of the user which is not removed
+	FindWalletByUser(ctx context.Context, userID uuid.UUID) (*data.Wallet, error)
+	// ListWalletTransactions returns the wallet ledger from the oldest transaction
+	ListWalletTransactions(ctx context.Context, spec data.ListWalletTransactionsSpec) (*data.WalletTransactionPage, error)
 }
@@ -2,9 +2,11 @@ package service
 
 import (
 	"context"
+	"fmt"
 
 	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 
 	commonevent "payment/pkg/common/event"
 	"payment/pkg/payment/app/data"
@@ -13,10 +15,26 @@ import (
 )
 
 type PaymentService interface {
-	CreatePayment(ctx context.Context, orderID uuid.UUID, amount float64) (uuid.UUID, error)
+	// CreatePayment returns ID of the Pending payment charging the wallet of the user for the order,
+	// repeated call returns the same payment
+	CreatePayment(ctx context.Context, userID, orderID uuid.UUID, amount float64) (uuid.UUID, error)
+	// ChargePayment debits the wallet and moves the payment through Processing to Succeeded,
+	// the payment is moved to Failed and model.ErrPaymentFailed is returned when the wallet can not be debited
+	ChargePayment(ctx context.Context, paymentID uuid.UUID) error
+	// CapturePayment captures the wallet hold authorized for the payment and moves the payment through Processing to Succeeded
+	// with the captured amount, zero amount captures the whole hold, repeated call returns the captured amount,
+	// the payment is moved to Failed and model.ErrPaymentFailed is returned when the hold can not be captured
+	CapturePayment(ctx context.Context, paymentID, holdID uuid.UUID, amount float64) (float64, error)
+	// CancelPayment cancels the Pending payment and refunds the Succeeded one, finished payments are not changed
+	CancelPayment(ctx context.Context, paymentID uuid.UUID) error
 	RemovePayment(ctx context.Context, paymentID uuid.UUID) error
 	SetPaymentStatus(ctx context.Context, paymentID uuid.UUID, status int) error
 	FindPayment(ctx context.Context, paymentID uuid.UUID) (data.Payment, error)
+	// RefundPayment credits what is left of the succeeded payment back to its wallet, refunded payment is not credited again
+	RefundPayment(ctx context.Context, paymentID uuid.UUID) error
+	// RefundReturn credits amount of the order return back off the order payment, capped by what is left of it,
+	// repeated call with the same return does nothing
+	RefundReturn(ctx context.Context, orderID, returnID uuid.UUID, amount float64) error
 }
 
 func NewPaymentService(
@@ -37,37 +55,161 @@ type paymentService struct {
 	eventDispatcher outbox.EventDispatcher[outbox.Event]
 }
 
-func (s *paymentService) CreatePayment(ctx context.Context, orderID uuid.UUID, amount float64) (uuid.UUID, error) {
+func (s *paymentService) CreatePayment(ctx context.Context, userID, orderID uuid.UUID, amount float64) (uuid.UUID, error) {
 	var paymentID uuid.UUID
 
+	// Version of a payment which is not stored yet can not be checked, so the lock keeps one payment per order
 	err := s.luow.Execute(ctx, []string{paymentLockByOrder(orderID)}, func(provider RepositoryProvider) error {
-		domainService := s.paymentDomainService(ctx, provider.PaymentRepository(ctx))
-		id, err := domainService.CreatePayment(orderID, amount)
+		payment, err := provider.PaymentRepository(ctx).FindByOrderID(orderID)
+		if err == nil {
+			paymentID = payment.ID
+			return nil
+		}
+		if !errors.Is(err, model.ErrPaymentNotFound) {
+			return err
+		}
+
+		wallet, err := provider.WalletRepository(ctx).FindByUserID(userID)
 		if err != nil {
 			return err
 		}
-		paymentID = id
-		return nil
+		paymentID, err = s.paymentDomainService(ctx, provider.PaymentRepository(ctx)).CreatePayment(wallet.ID, orderID, amount)
+		return err
 	})
 
 	return paymentID, err
 }
 
+func (s *paymentService) ChargePayment(ctx context.Context, paymentID uuid.UUID) error {
+	var debitErr error
+	err := s.updatePayment(ctx, func(provider RepositoryProvider) error {
+		debitErr = nil
+		payment, err := provider.PaymentRepository(ctx).Find(paymentID)
+		if err != nil {
+			return err
+		}
+
+		paymentDomainService := s.paymentDomainService(ctx, provider.PaymentRepository(ctx))
+		switch payment.Status {
+		case model.Succeeded:
+			return nil
+		case model.Failed:
+			debitErr = model.ErrPaymentFailed
+			return nil
+		case model.Pending:
+			if err = paymentDomainService.SetStatus(paymentID, model.Processing); err != nil {
+				return err
+			}
+		case model.Processing:
+			// Processing payment may be set by SetPaymentStatus, it is not debited yet
+		default:
+			return service.ErrInvalidPaymentStatus
+		}
+
+		err = s.debit(ctx, provider, payment)
+		if errors.Is(err, model.ErrInsufficientFunds) || errors.Is(err, model.ErrWalletNotFound) {
+			// Failed status is committed, so the error is returned after the transaction
+			debitErr = fmt.Errorf("%w: %w", model.ErrPaymentFailed, err)
+			return paymentDomainService.SetStatus(paymentID, model.Failed)
+		}
+		if err != nil {
+			return err
+		}
+		return paymentDomainService.SetStatus(paymentID, model.Succeeded)
+	})
+	if err != nil {
+		return err
+	}
+	return debitErr
+}
+
+func (s *paymentService) CapturePayment(ctx context.Context, paymentID, holdID uuid.UUID, amount float64) (float64, error) {
+	var captured float64
+	var captureErr error
+	// Wallet hold has no version, so its capture is serialized with WalletHoldService by the hold lock
+	err := retryOnConflict(func() error {
+		return s.luow.Execute(ctx, []string{walletHoldLock(holdID)}, func(provider RepositoryProvider) error {
+			captureErr = nil
+			payment, err := provider.PaymentRepository(ctx).Find(paymentID)
+			if err != nil {
+				return err
+			}
+
+			paymentDomainService := s.paymentDomainService(ctx, provider.PaymentRepository(ctx))
+			switch payment.Status {
+			case model.Succeeded:
+				captured = payment.CapturedAmount
+				return nil
+			case model.Failed:
+				captureErr = model.ErrPaymentFailed
+				return nil
+			case model.Pending:
+				if err = paymentDomainService.SetStatus(paymentID, model.Processing); err != nil {
+					return err
+				}
+			case model.Processing:
+			default:
+				return service.ErrInvalidPaymentStatus
+			}
+
+			captured, err = s.walletHoldDomainService(ctx, provider).Capture(holdID, amount)
+			if errors.Is(err, service.ErrWalletHoldExpired) ||
+				errors.Is(err, service.ErrInvalidHoldStatus) ||
+				errors.Is(err, service.ErrCaptureExceedsHold) ||
+				errors.Is(err, model.ErrInsufficientFunds) {
+				// Failed status is committed, so the error is returned after the transaction
+				captureErr = fmt.Errorf("%w: %w", model.ErrPaymentFailed, err)
+				return paymentDomainService.SetStatus(paymentID, model.Failed)
+			}
+			if err != nil {
+				return err
+			}
+			return paymentDomainService.Capture(paymentID, captured)
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+	return captured, captureErr
+}
+
+func (s *paymentService) CancelPayment(ctx context.Context, paymentID uuid.UUID) error {
+	return s.updatePayment(ctx, func(provider RepositoryProvider) error {
+		payment, err := provider.PaymentRepository(ctx).Find(paymentID)
+		if err != nil {
+			return err
+		}
+
+		paymentDomainService := s.paymentDomainService(ctx, provider.PaymentRepository(ctx))
+		switch payment.Status {
+		case model.Pending:
+			return paymentDomainService.SetStatus(paymentID, model.Cancelled)
+		case model.Processing:
+			// Wallet is debited in the same transaction as the payment succeeds, so Processing payment is not charged
+			return paymentDomainService.SetStatus(paymentID, model.Failed)
+		case model.Succeeded:
+			return s.refund(ctx, provider, payment)
+		default:
+			return nil
+		}
+	})
+}
+
 func (s *paymentService) RemovePayment(ctx context.Context, paymentID uuid.UUID) error {
-	return s.luow.Execute(ctx, []string{paymentLock(paymentID)}, func(provider RepositoryProvider) error {
+	return s.updatePayment(ctx, func(provider RepositoryProvider) error {
 		return s.paymentDomainService(ctx, provider.PaymentRepository(ctx)).RemovePayment(paymentID)
 	})
 }
 
 func (s *paymentService) SetPaymentStatus(ctx context.Context, paymentID uuid.UUID, status int) error {
-	return s.luow.Execute(ctx, []string{paymentLock(paymentID)}, func(provider RepositoryProvider) error {
+	return s.updatePayment(ctx, func(provider RepositoryProvider) error {
 		return s.paymentDomainService(ctx, provider.PaymentRepository(ctx)).SetStatus(paymentID, model.PaymentStatus(status))
 	})
 }
 
 func (s *paymentService) FindPayment(ctx context.Context, paymentID uuid.UUID) (data.Payment, error) {
 	var payment data.Payment
-	err := s.luow.Execute(ctx, []string{paymentLock(paymentID)}, func(provider RepositoryProvider) error {
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
 		domainPayment, err := provider.PaymentRepository(ctx).Find(paymentID)
 		if err != nil {
 			return err
@@ -84,10 +226,93 @@ func (s *paymentService) FindPayment(ctx context.Context, paymentID uuid.UUID) (
 	return payment, err
 }
 
+func (s *paymentService) RefundPayment(ctx context.Context, paymentID uuid.UUID) error {
+	return s.updatePayment(ctx, func(provider RepositoryProvider) error {
+		payment, err := provider.PaymentRepository(ctx).Find(paymentID)
+		if err != nil {
+			return err
+		}
+		if payment.Status == model.Refunded {
+			return nil
+		}
+		return s.refund(ctx, provider, payment)
+	})
+}
+
+func (s *paymentService) RefundReturn(ctx context.Context, orderID, returnID uuid.UUID, amount float64) error {
+	return s.updatePayment(ctx, func(provider RepositoryProvider) error {
+		refunded, err := provider.WalletTransactionRepository(ctx).HasReference(returnID)
+		if err != nil || refunded {
+			return err
+		}
+		payment, err := provider.PaymentRepository(ctx).FindByOrderID(orderID)
+		if err != nil {
+			return err
+		}
+		refundAmount, err := s.paymentDomainService(ctx, provider.PaymentRepository(ctx)).Refund(payment.ID, amount)
+		// Payment refunded in full already has nothing left for the return
+		if err != nil || refundAmount == 0 {
+			return err
+		}
+		wallet, err := provider.WalletRepository(ctx).Find(payment.WalletID)
+		if err != nil {
+			return err
+		}
+		return s.walletDomainService(ctx, provider).RefundReturn(wallet.UserID, orderID, returnID, refundAmount)
+	})
+}
+
+func (s *paymentService) debit(ctx context.Context, provider RepositoryProvider, payment *model.Payment) error {
+	wallet, err := provider.WalletRepository(ctx).Find(payment.WalletID)
+	if err != nil {
+		return err
+	}
+	return s.walletDomainService(ctx, provider).Debit(wallet.UserID, payment.OrderID, payment.Amount)
+}
+
+// refund credits back what is left of the payment after refunds of order returns
+func (s *paymentService) refund(ctx context.Context, provider RepositoryProvider, payment *model.Payment) error {
+	amount, err := s.paymentDomainService(ctx, provider.PaymentRepository(ctx)).Refund(payment.ID, payment.CapturedAmount)
+	if err != nil || amount == 0 {
+		return err
+	}
+
+	wallet, err := provider.WalletRepository(ctx).Find(payment.WalletID)
+	if err != nil {
+		return err
+	}
+	return s.walletDomainService(ctx, provider).Credit(wallet.UserID, payment.OrderID, amount)
+}
+
+// updatePayment runs f in a new transaction again when the payment is changed concurrently
+func (s *paymentService) updatePayment(ctx context.Context, f func(provider RepositoryProvider) error) error {
+	return retryOnConflict(func() error {
+		return s.uow.Execute(ctx, f)
+	})
+}
+
 func (s *paymentService) paymentDomainService(ctx context.Context, repository model.PaymentRepository) service.Payment {
 	return service.NewPaymentService(repository, s.domainEventDispatcher(ctx))
 }
 
+func (s *paymentService) walletDomainService(ctx context.Context, provider RepositoryProvider) service.Wallet {
+	return service.NewWalletService(
+		provider.WalletRepository(ctx),
+		provider.WalletTransactionRepository(ctx),
+		provider.WalletHoldRepository(ctx),
+		s.domainEventDispatcher(ctx),
+	)
+}
+
+func (s *paymentService) walletHoldDomainService(ctx context.Context, provider RepositoryProvider) service.WalletHold {
+	return service.NewWalletHoldService(
+		provider.WalletHoldRepository(ctx),
+		provider.WalletRepository(ctx),
+		s.walletDomainService(ctx, provider),
+		s.domainEventDispatcher(ctx),
+	)
+}
+
 func (s *paymentService) domainEventDispatcher(ctx context.Context) commonevent.Dispatcher {
 	return &domainEventDispatcher{
 		ctx:             ctx,
@@ -97,10 +322,6 @@ func (s *paymentService) domainEventDispatcher(ctx context.Context) commonevent.
 
 const basePaymentLock = "payment_"
 
-func paymentLock(id uuid.UUID) string {
-	return basePaymentLock + id.String()
-}
-
 func paymentLockByOrder(orderID uuid.UUID) string {
 	return basePaymentLock + "order_" + orderID.String()
 }
@@ -3,11 +3,15 @@ package service
 import (
 	"context"
 
+	"github.com/pkg/errors"
+
 	"payment/pkg/payment/domain/model"
 )
 
 type RepositoryProvider interface {
 	WalletRepository(ctx context.Context) model.WalletRepository
+	WalletTransactionRepository(ctx context.Context) model.WalletTransactionRepository
+	WalletHoldRepository(ctx context.Context) model.WalletHoldRepository
 	PaymentRepository(ctx context.Context) model.PaymentRepository
 }
 
@@ -17,3 +21,17 @@ type LockableUnitOfWork interface {
 type UnitOfWork interface {
 	Execute(ctx context.Context, f func(provider RepositoryProvider) error) error
 }
+
+const maxConflictRetries = 3
+
+// retryOnConflict runs f again when the wallet or the payment is changed concurrently
+func retryOnConflict(f func() error) error {
+	var err error
+	for range maxConflictRetries {
+		err = f()
+		if !errors.Is(err, model.ErrWalletVersionConflict) && !errors.Is(err, model.ErrPaymentVersionConflict) {
+			return err
+		}
+	}
+	return err
+}
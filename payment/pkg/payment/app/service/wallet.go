@@ -8,7 +8,6 @@ import (
 
 	commonevent "payment/pkg/common/event"
 	"payment/pkg/payment/app/data"
-	"payment/pkg/payment/domain/model"
 	"payment/pkg/payment/domain/service"
 )
 
@@ -17,7 +16,13 @@ type WalletService interface {
 	RemoveWallet(ctx context.Context, walletID uuid.UUID) error
 	UpdateWalletBalance(ctx context.Context, walletID uuid.UUID, newBalance float64) error
 	FindWallet(ctx context.Context, walletID uuid.UUID) (data.Wallet, error)
-	Charge(ctx context.Context, userID uuid.UUID, amount float64) error
+	// Debit charges amount for the order from the wallet of the user, overdraft is rejected with model.ErrInsufficientFunds
+	Debit(ctx context.Context, userID, orderID uuid.UUID, amount float64) error
+	// Credit refunds amount for the order to the wallet of the user
+	Credit(ctx context.Context, userID, orderID uuid.UUID, amount float64) error
+	// RefundReturn credits amount of the order return to the wallet of the user, repeated call with the same return does nothing
+	RefundReturn(ctx context.Context, userID, orderID, returnID uuid.UUID, amount float64) error
+	TopUpWallet(ctx context.Context, walletID uuid.UUID, amount float64) error
 }
 
 func NewWalletService(
@@ -42,7 +47,7 @@ func (s *walletService) CreateWallet(ctx context.Context, userID uuid.UUID) (uui
 	var walletID uuid.UUID
 
 	err := s.luow.Execute(ctx, []string{walletLockByUser(userID)}, func(provider RepositoryProvider) error {
-		domainService := s.walletDomainService(ctx, provider.WalletRepository(ctx))
+		domainService := s.walletDomainService(ctx, provider)
 		id, err := domainService.CreateWallet(userID)
 		if err != nil {
 			return err
@@ -55,36 +60,53 @@ func (s *walletService) CreateWallet(ctx context.Context, userID uuid.UUID) (uui
 }
 
 func (s *walletService) RemoveWallet(ctx context.Context, walletID uuid.UUID) error {
-	return s.luow.Execute(ctx, []string{walletLock(walletID)}, func(provider RepositoryProvider) error {
-		return s.walletDomainService(ctx, provider.WalletRepository(ctx)).RemoveWallet(walletID)
+	return s.updateWallet(ctx, func(provider RepositoryProvider) error {
+		return s.walletDomainService(ctx, provider).RemoveWallet(walletID)
 	})
 }
 
 func (s *walletService) UpdateWalletBalance(ctx context.Context, walletID uuid.UUID, newBalance float64) error {
-	return s.luow.Execute(ctx, []string{walletLock(walletID)}, func(provider RepositoryProvider) error {
-		return s.walletDomainService(ctx, provider.WalletRepository(ctx)).UpdateWalletBalance(walletID, newBalance)
+	return s.updateWallet(ctx, func(provider RepositoryProvider) error {
+		return s.walletDomainService(ctx, provider).UpdateWalletBalance(walletID, newBalance)
 	})
 }
 
 func (s *walletService) FindWallet(ctx context.Context, walletID uuid.UUID) (data.Wallet, error) {
 	var wallet data.Wallet
-	err := s.luow.Execute(ctx, []string{walletLock(walletID)}, func(provider RepositoryProvider) error {
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
 		domainWallet, err := provider.WalletRepository(ctx).Find(walletID)
 		if err != nil {
 			return err
 		}
+		held, err := provider.WalletHoldRepository(ctx).ActiveAmount(walletID)
+		if err != nil {
+			return err
+		}
 		wallet = data.Wallet{
-			ID:      domainWallet.ID,
-			UserID:  domainWallet.UserID,
-			Balance: domainWallet.Balance,
+			ID:               domainWallet.ID,
+			UserID:           domainWallet.UserID,
+			Balance:          domainWallet.Balance,
+			AvailableBalance: domainWallet.Balance - held,
 		}
 		return nil
 	})
 	return wallet, err
 }
 
-func (s *walletService) walletDomainService(ctx context.Context, repository model.WalletRepository) service.Wallet {
-	return service.NewWalletService(repository, s.domainEventDispatcher(ctx))
+// updateWallet runs f in a new transaction again when the wallet is changed concurrently
+func (s *walletService) updateWallet(ctx context.Context, f func(provider RepositoryProvider) error) error {
+	return retryOnConflict(func() error {
+		return s.uow.Execute(ctx, f)
+	})
+}
+
+func (s *walletService) walletDomainService(ctx context.Context, provider RepositoryProvider) service.Wallet {
+	return service.NewWalletService(
+		provider.WalletRepository(ctx),
+		provider.WalletTransactionRepository(ctx),
+		provider.WalletHoldRepository(ctx),
+		s.domainEventDispatcher(ctx),
+	)
 }
 
 func (s *walletService) domainEventDispatcher(ctx context.Context) commonevent.Dispatcher {
@@ -94,18 +116,32 @@ func (s *walletService) domainEventDispatcher(ctx context.Context) commonevent.D
 	}
 }
 
-func (s *walletService) Charge(ctx context.Context, userID uuid.UUID, _ float64) error {
-	return s.luow.Execute(ctx, []string{walletLockByUser(userID)}, func(_ RepositoryProvider) error {
-		return nil
+func (s *walletService) Debit(ctx context.Context, userID, orderID uuid.UUID, amount float64) error {
+	return s.updateWallet(ctx, func(provider RepositoryProvider) error {
+		return s.walletDomainService(ctx, provider).Debit(userID, orderID, amount)
 	})
 }
 
-const baseWalletLock = "wallet_"
+func (s *walletService) Credit(ctx context.Context, userID, orderID uuid.UUID, amount float64) error {
+	return s.updateWallet(ctx, func(provider RepositoryProvider) error {
+		return s.walletDomainService(ctx, provider).Credit(userID, orderID, amount)
+	})
+}
+
+func (s *walletService) RefundReturn(ctx context.Context, userID, orderID, returnID uuid.UUID, amount float64) error {
+	return s.updateWallet(ctx, func(provider RepositoryProvider) error {
+		return s.walletDomainService(ctx, provider).RefundReturn(userID, orderID, returnID, amount)
+	})
+}
 
-func walletLock(id uuid.UUID) string {
-	return baseWalletLock + id.String()
+func (s *walletService) TopUpWallet(ctx context.Context, walletID uuid.UUID, amount float64) error {
+	return s.updateWallet(ctx, func(provider RepositoryProvider) error {
+		return s.walletDomainService(ctx, provider).TopUpWallet(walletID, amount)
+	})
 }
 
+const baseWalletLock = "wallet_"
+
 func walletLockByUser(userID uuid.UUID) string {
 	return baseWalletLock + "user_" + userID.String()
 }
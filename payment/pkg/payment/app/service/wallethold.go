@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+	"github.com/google/uuid"
+
+	commonevent "payment/pkg/common/event"
+	"payment/pkg/payment/app/data"
+	"payment/pkg/payment/domain/model"
+	"payment/pkg/payment/domain/service"
+)
+
+// WalletHoldScheduler expires the hold once its ExpiresAt passes
+type WalletHoldScheduler interface {
+	ScheduleHoldExpiry(ctx context.Context, holdID uuid.UUID, expiresAt time.Time) error
+}
+
+type WalletHoldService interface {
+	// AuthorizeWallet holds amount of the wallet of the user for the order and schedules its expiry,
+	// zero ttl holds for the default time, repeated call returns the active or captured hold of the order
+	AuthorizeWallet(ctx context.Context, userID, orderID uuid.UUID, amount float64, ttl time.Duration) (data.WalletHold, error)
+	// CaptureHold debits amount of the active hold and releases the rest, zero amount captures the whole hold
+	CaptureHold(ctx context.Context, holdID uuid.UUID, amount float64) (data.WalletHold, error)
+	VoidHold(ctx context.Context, holdID uuid.UUID) error
+	// ExpireHold releases the hold which is not captured or voided by its ExpiresAt
+	ExpireHold(ctx context.Context, holdID uuid.UUID) error
+}
+
+func NewWalletHoldService(
+	luow LockableUnitOfWork,
+	eventDispatcher outbox.EventDispatcher[outbox.Event],
+	scheduler WalletHoldScheduler,
+) WalletHoldService {
+	return &walletHoldService{
+		luow:            luow,
+		eventDispatcher: eventDispatcher,
+		scheduler:       scheduler,
+	}
+}
+
+type walletHoldService struct {
+	luow            LockableUnitOfWork
+	eventDispatcher outbox.EventDispatcher[outbox.Event]
+	scheduler       WalletHoldScheduler
+}
+
+func (s *walletHoldService) AuthorizeWallet(
+	ctx context.Context,
+	userID, orderID uuid.UUID,
+	amount float64,
+	ttl time.Duration,
+) (data.WalletHold, error) {
+	var hold *model.WalletHold
+	err := s.luow.Execute(ctx, []string{walletHoldLockByOrder(orderID)}, func(provider RepositoryProvider) error {
+		holdID, err := s.walletHoldDomainService(ctx, provider).Authorize(userID, orderID, amount, ttl)
+		if err != nil {
+			return err
+		}
+		hold, err = provider.WalletHoldRepository(ctx).Find(holdID)
+		return err
+	})
+	if err != nil {
+		return data.WalletHold{}, err
+	}
+
+	// Expiry is scheduled again for the repeated call, so the hold is released even if the first call failed to schedule it
+	if hold.Status == model.HoldActive {
+		if err = s.scheduler.ScheduleHoldExpiry(ctx, hold.ID, hold.ExpiresAt); err != nil {
+			return data.WalletHold{}, err
+		}
+	}
+	return toDataWalletHold(hold), nil
+}
+
+func (s *walletHoldService) CaptureHold(ctx context.Context, holdID uuid.UUID, amount float64) (data.WalletHold, error) {
+	var hold *model.WalletHold
+	err := s.luow.Execute(ctx, []string{walletHoldLock(holdID)}, func(provider RepositoryProvider) error {
+		_, err := s.walletHoldDomainService(ctx, provider).Capture(holdID, amount)
+		if err != nil {
+			return err
+		}
+		hold, err = provider.WalletHoldRepository(ctx).Find(holdID)
+		return err
+	})
+	if err != nil {
+		return data.WalletHold{}, err
+	}
+	return toDataWalletHold(hold), nil
+}
+
+func (s *walletHoldService) VoidHold(ctx context.Context, holdID uuid.UUID) error {
+	return s.luow.Execute(ctx, []string{walletHoldLock(holdID)}, func(provider RepositoryProvider) error {
+		return s.walletHoldDomainService(ctx, provider).Void(holdID)
+	})
+}
+
+func (s *walletHoldService) ExpireHold(ctx context.Context, holdID uuid.UUID) error {
+	return s.luow.Execute(ctx, []string{walletHoldLock(holdID)}, func(provider RepositoryProvider) error {
+		return s.walletHoldDomainService(ctx, provider).Expire(holdID)
+	})
+}
+
+func (s *walletHoldService) walletHoldDomainService(ctx context.Context, provider RepositoryProvider) service.WalletHold {
+	dispatcher := s.domainEventDispatcher(ctx)
+	return service.NewWalletHoldService(
+		provider.WalletHoldRepository(ctx),
+		provider.WalletRepository(ctx),
+		service.NewWalletService(
+			provider.WalletRepository(ctx),
+			provider.WalletTransactionRepository(ctx),
+			provider.WalletHoldRepository(ctx),
+			dispatcher,
+		),
+		dispatcher,
+	)
+}
+
+func (s *walletHoldService) domainEventDispatcher(ctx context.Context) commonevent.Dispatcher {
+	return &domainEventDispatcher{
+		ctx:             ctx,
+		eventDispatcher: s.eventDispatcher,
+	}
+}
+
+func toDataWalletHold(hold *model.WalletHold) data.WalletHold {
+	return data.WalletHold{
+		ID:             hold.ID,
+		WalletID:       hold.WalletID,
+		OrderID:        hold.OrderID,
+		Amount:         hold.Amount,
+		CapturedAmount: hold.CapturedAmount,
+		Status:         data.WalletHoldStatus(hold.Status),
+		ExpiresAt:      hold.ExpiresAt,
+		CreatedAt:      hold.CreatedAt,
+		UpdatedAt:      hold.UpdatedAt,
+	}
+}
+
+const baseWalletHoldLock = "wallet_hold_"
+
+func walletHoldLock(holdID uuid.UUID) string {
+	return baseWalletHoldLock + holdID.String()
+}
+
+func walletHoldLockByOrder(orderID uuid.UUID) string {
+	return baseWalletHoldLock + "order_" + orderID.String()
+}
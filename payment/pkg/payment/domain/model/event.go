@@ -1,6 +1,10 @@
 package model
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type WalletCreated struct {
 	WalletID uuid.UUID
@@ -14,6 +18,7 @@ func (e WalletCreated) Type() string {
 
 type WalletBalanceChanged struct {
 	WalletID   uuid.UUID
+	UserID     uuid.UUID
 	OldBalance float64
 	NewBalance float64
 }
@@ -30,6 +35,41 @@ func (e WalletRemoved) Type() string {
 	return "WalletRemoved"
 }
 
+type WalletHoldAuthorized struct {
+	HoldID    uuid.UUID
+	WalletID  uuid.UUID
+	OrderID   uuid.UUID
+	Amount    float64
+	ExpiresAt time.Time
+}
+
+func (e WalletHoldAuthorized) Type() string {
+	return "WalletHoldAuthorized"
+}
+
+type WalletHoldCaptured struct {
+	HoldID   uuid.UUID
+	WalletID uuid.UUID
+	OrderID  uuid.UUID
+	Amount   float64
+}
+
+func (e WalletHoldCaptured) Type() string {
+	return "WalletHoldCaptured"
+}
+
+// WalletHoldReleased is dispatched when the hold is voided or expired
+type WalletHoldReleased struct {
+	HoldID   uuid.UUID
+	WalletID uuid.UUID
+	OrderID  uuid.UUID
+	Status   WalletHoldStatus
+}
+
+func (e WalletHoldReleased) Type() string {
+	return "WalletHoldReleased"
+}
+
 type PaymentCreated struct {
 	PaymentID uuid.UUID
 	WalletID  uuid.UUID
@@ -43,6 +83,7 @@ func (e PaymentCreated) Type() string {
 
 type PaymentStatusChanged struct {
 	PaymentID uuid.UUID
+	OrderID   uuid.UUID
 	From      PaymentStatus
 	To        PaymentStatus
 }
@@ -7,7 +7,13 @@ import (
 	"github.com/google/uuid"
 )
 
-var ErrPaymentNotFound = errors.New("payment not found")
+var (
+	ErrPaymentNotFound = errors.New("payment not found")
+	// ErrPaymentFailed means the wallet could not be debited for the payment
+	ErrPaymentFailed = errors.New("payment failed")
+	// ErrPaymentVersionConflict means the payment was changed concurrently since it was found
+	ErrPaymentVersionConflict = errors.New("payment version conflict")
+)
 
 type PaymentStatus int
 
@@ -17,22 +23,33 @@ const (
 	Succeeded
 	Failed
 	Cancelled
+	// Refunded payment is succeeded and then credited back to the wallet
+	Refunded
 )
 
 type Payment struct {
-	ID        uuid.UUID
-	WalletID  uuid.UUID
-	OrderID   uuid.UUID
-	Amount    float64
-	Status    PaymentStatus
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt *time.Time
+	ID       uuid.UUID
+	WalletID uuid.UUID
+	OrderID  uuid.UUID
+	// Amount is charged at once or authorized by the wallet hold for the payment
+	Amount float64
+	// CapturedAmount is taken off the wallet once the payment succeeds, it is less than Amount when the hold is captured partially
+	CapturedAmount float64
+	// RefundedAmount is credited back to the wallet so far, it never exceeds CapturedAmount
+	RefundedAmount float64
+	Status         PaymentStatus
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      *time.Time
+	// Version is zero for a new payment and is incremented by every Store
+	Version int
 }
 
 type PaymentRepository interface {
 	NextID() (uuid.UUID, error)
 	Store(payment *Payment) error
 	Find(id uuid.UUID) (*Payment, error)
+	// FindByOrderID returns the last payment of the order which is not removed
+	FindByOrderID(orderID uuid.UUID) (*Payment, error)
 	Remove(id uuid.UUID) error
 }
@@ -7,7 +7,13 @@ import (
 	"github.com/google/uuid"
 )
 
-var ErrWalletNotFound = errors.New("wallet not found")
+var (
+	ErrWalletNotFound = errors.New("wallet not found")
+	// ErrInsufficientFunds means the wallet balance is less than the debited amount
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrWalletVersionConflict means the wallet was changed concurrently since it was found
+	ErrWalletVersionConflict = errors.New("wallet version conflict")
+)
 
 type Wallet struct {
 	ID        uuid.UUID
@@ -16,11 +22,15 @@ type Wallet struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt *time.Time
+	// Version is zero for a new wallet and is incremented by every Store
+	Version int
 }
 
 type WalletRepository interface {
 	NextID() (uuid.UUID, error)
 	Store(wallet *Wallet) error
 	Find(id uuid.UUID) (*Wallet, error)
+	// FindByUserID returns the wallet of the user which is not removed
+	FindByUserID(userID uuid.UUID) (*Wallet, error)
 	Remove(id uuid.UUID) error
 }
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrWalletHoldNotFound = errors.New("wallet hold not found")
+
+type WalletHoldStatus int
+
+const (
+	// HoldActive hold reserves its amount of the wallet balance until it is captured, voided or expired
+	HoldActive WalletHoldStatus = iota
+	HoldCaptured
+	HoldVoided
+	HoldExpired
+)
+
+// WalletHold reserves funds of the wallet for the order without moving them
+type WalletHold struct {
+	ID       uuid.UUID
+	WalletID uuid.UUID
+	OrderID  uuid.UUID
+	Amount   float64
+	// CapturedAmount is debited from the wallet when the hold is captured, the rest of Amount is released
+	CapturedAmount float64
+	Status         WalletHoldStatus
+	ExpiresAt      time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type WalletHoldRepository interface {
+	NextID() (uuid.UUID, error)
+	Store(hold *WalletHold) error
+	Find(id uuid.UUID) (*WalletHold, error)
+	// FindByOrderID returns the last hold of the order
+	FindByOrderID(orderID uuid.UUID) (*WalletHold, error)
+	// ActiveAmount sums amounts of active holds of the wallet
+	ActiveAmount(walletID uuid.UUID) (float64, error)
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WalletTransactionDirection int
+
+const (
+	TransactionDebit WalletTransactionDirection = iota
+	TransactionCredit
+)
+
+type WalletTransactionReason int
+
+const (
+	ReasonCharge WalletTransactionReason = iota
+	ReasonRefund
+	ReasonTopUp
+	ReasonAdjustment
+)
+
+// WalletTransaction is an entry of the wallet ledger, entries are never changed or removed
+type WalletTransaction struct {
+	ID       uuid.UUID
+	WalletID uuid.UUID
+	// Amount is positive, Direction tells whether it is taken off or added to the balance
+	Amount    float64
+	Direction WalletTransactionDirection
+	Reason    WalletTransactionReason
+	// OrderID is set for charges and refunds of orders
+	OrderID *uuid.UUID
+	// ReferenceID identifies the operation the entry is recorded for, e.g. the refunded order return,
+	// the ledger holds one entry per reference
+	ReferenceID *uuid.UUID
+	CreatedAt   time.Time
+}
+
+type WalletTransactionRepository interface {
+	NextID() (uuid.UUID, error)
+	Append(transaction *WalletTransaction) error
+	// Balance sums credits minus debits of the wallet
+	Balance(walletID uuid.UUID) (float64, error)
+	// HasReference tells whether an entry with the reference is recorded already
+	HasReference(referenceID uuid.UUID) (bool, error)
+}
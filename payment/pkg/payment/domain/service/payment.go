@@ -15,9 +15,16 @@ var (
 )
 
 type Payment interface {
-	CreatePayment(orderID uuid.UUID, amount float64) (uuid.UUID, error)
+	// CreatePayment returns ID of the Pending payment charging amount for the order from the wallet
+	CreatePayment(walletID, orderID uuid.UUID, amount float64) (uuid.UUID, error)
 	RemovePayment(paymentID uuid.UUID) error
 	SetStatus(paymentID uuid.UUID, status model.PaymentStatus) error
+	// Refund takes amount off what is left of the Succeeded payment and returns the part to credit back,
+	// the payment is moved to Refunded once nothing is left, refunded payment returns zero
+	Refund(paymentID uuid.UUID, amount float64) (float64, error)
+	// Capture moves the Processing payment to Succeeded with amount captured off the wallet hold as CapturedAmount,
+	// the amount may be less than the authorized payment amount when the hold is captured partially
+	Capture(paymentID uuid.UUID, amount float64) error
 }
 
 func NewPaymentService(repo model.PaymentRepository, dispatcher commonevent.Dispatcher) Payment {
@@ -32,7 +39,11 @@ type paymentService struct {
 	dispatcher commonevent.Dispatcher
 }
 
-func (p paymentService) CreatePayment(orderID uuid.UUID, amount float64) (uuid.UUID, error) {
+func (p paymentService) CreatePayment(walletID, orderID uuid.UUID, amount float64) (uuid.UUID, error) {
+	if amount <= 0 {
+		return uuid.Nil, ErrInvalidAmount
+	}
+
 	paymentID, err := p.repo.NextID()
 	if err != nil {
 		return uuid.Nil, err
@@ -41,6 +52,7 @@ func (p paymentService) CreatePayment(orderID uuid.UUID, amount float64) (uuid.U
 	currentTime := time.Now()
 	err = p.repo.Store(&model.Payment{
 		ID:        paymentID,
+		WalletID:  walletID,
 		OrderID:   orderID,
 		Amount:    amount,
 		Status:    model.Pending,
@@ -53,6 +65,7 @@ func (p paymentService) CreatePayment(orderID uuid.UUID, amount float64) (uuid.U
 
 	return paymentID, p.dispatcher.Dispatch(model.PaymentCreated{
 		PaymentID: paymentID,
+		WalletID:  walletID,
 		OrderID:   orderID,
 		Amount:    amount,
 	})
@@ -93,6 +106,10 @@ func (p paymentService) SetStatus(paymentID uuid.UUID, status model.PaymentStatu
 	}
 
 	payment.Status = status
+	if status == model.Succeeded {
+		// Payment succeeding without capture is charged in full
+		payment.CapturedAmount = payment.Amount
+	}
 	payment.UpdatedAt = time.Now()
 
 	if err = p.repo.Store(payment); err != nil {
@@ -101,18 +118,84 @@ func (p paymentService) SetStatus(paymentID uuid.UUID, status model.PaymentStatu
 
 	return p.dispatcher.Dispatch(model.PaymentStatusChanged{
 		PaymentID: paymentID,
+		OrderID:   payment.OrderID,
 		From:      oldStatus,
 		To:        status,
 	})
 }
 
+func (p paymentService) Refund(paymentID uuid.UUID, amount float64) (float64, error) {
+	if amount <= 0 {
+		return 0, ErrInvalidAmount
+	}
+
+	payment, err := p.repo.Find(paymentID)
+	if err != nil {
+		return 0, err
+	}
+	switch payment.Status {
+	case model.Refunded:
+		return 0, nil
+	case model.Succeeded:
+	default:
+		return 0, ErrInvalidPaymentStatus
+	}
+
+	refunded := min(amount, roundToCents(payment.CapturedAmount-payment.RefundedAmount))
+	payment.RefundedAmount = roundToCents(payment.RefundedAmount + refunded)
+	payment.UpdatedAt = time.Now()
+	if payment.RefundedAmount < payment.CapturedAmount {
+		return refunded, p.repo.Store(payment)
+	}
+
+	payment.Status = model.Refunded
+	if err = p.repo.Store(payment); err != nil {
+		return 0, err
+	}
+	return refunded, p.dispatcher.Dispatch(model.PaymentStatusChanged{
+		PaymentID: paymentID,
+		OrderID:   payment.OrderID,
+		From:      model.Succeeded,
+		To:        model.Refunded,
+	})
+}
+
+func (p paymentService) Capture(paymentID uuid.UUID, amount float64) error {
+	payment, err := p.repo.Find(paymentID)
+	if err != nil {
+		return err
+	}
+	if amount <= 0 || amount > payment.Amount {
+		return ErrInvalidAmount
+	}
+	if payment.Status != model.Processing {
+		return ErrInvalidPaymentStatus
+	}
+
+	payment.CapturedAmount = amount
+	payment.Status = model.Succeeded
+	payment.UpdatedAt = time.Now()
+	if err = p.repo.Store(payment); err != nil {
+		return err
+	}
+
+	return p.dispatcher.Dispatch(model.PaymentStatusChanged{
+		PaymentID: paymentID,
+		OrderID:   payment.OrderID,
+		From:      model.Processing,
+		To:        model.Succeeded,
+	})
+}
+
 func (p paymentService) isValidStatusTransition(from, to model.PaymentStatus) bool {
 	switch from {
 	case model.Pending:
 		return to == model.Processing || to == model.Cancelled
 	case model.Processing:
 		return to == model.Succeeded || to == model.Failed
-	case model.Succeeded, model.Failed, model.Cancelled:
+	case model.Succeeded:
+		return to == model.Refunded
+	case model.Failed, model.Cancelled, model.Refunded:
 		return false
 	default:
 		return false
@@ -38,6 +38,14 @@ func (m *MockPaymentRepository) Find(id uuid.UUID) (*model.Payment, error) {
 	return nil, args.Error(1)
 }
 
+func (m *MockPaymentRepository) FindByOrderID(orderID uuid.UUID) (*model.Payment, error) {
+	args := m.Called(orderID)
+	if payment, ok := args.Get(0).(*model.Payment); ok {
+		return payment, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockPaymentRepository) Remove(id uuid.UUID) error {
 	args := m.Called(id)
 	return args.Error(0)
@@ -58,6 +66,7 @@ func newPendingPayment(id, orderID uuid.UUID) *model.Payment {
 func newSucceededPayment(id, orderID uuid.UUID) *model.Payment {
 	payment := newPendingPayment(id, orderID)
 	payment.Status = model.Succeeded
+	payment.CapturedAmount = payment.Amount
 	return payment
 }
 
@@ -65,12 +74,14 @@ func TestCreatePayment_Success(t *testing.T) {
 	paymentRepo := new(MockPaymentRepository)
 	eventDispatcher := new(MockEventDispatcher)
 
+	walletID := uuid.New()
 	orderID := uuid.New()
 	paymentID := uuid.New()
 
 	paymentRepo.On("NextID").Return(paymentID, nil)
 	paymentRepo.On("Store", mock.MatchedBy(func(payment *model.Payment) bool {
 		return payment.ID == paymentID &&
+			payment.WalletID == walletID &&
 			payment.OrderID == orderID &&
 			payment.Amount == testAmount &&
 			payment.Status == model.Pending &&
@@ -79,12 +90,12 @@ func TestCreatePayment_Success(t *testing.T) {
 	})).Return(nil)
 
 	eventDispatcher.On("Dispatch", mock.MatchedBy(func(e model.PaymentCreated) bool {
-		return e.PaymentID == paymentID && e.OrderID == orderID && e.Amount == testAmount
+		return e.PaymentID == paymentID && e.WalletID == walletID && e.OrderID == orderID && e.Amount == testAmount
 	})).Return(nil)
 
 	svc := NewPaymentService(paymentRepo, eventDispatcher)
 
-	id, err := svc.CreatePayment(orderID, testAmount)
+	id, err := svc.CreatePayment(walletID, orderID, testAmount)
 
 	assert.NoError(t, err)
 	assert.Equal(t, paymentID, id)
@@ -96,6 +107,7 @@ func TestCreatePayment_RepoError(t *testing.T) {
 	paymentRepo := new(MockPaymentRepository)
 	eventDisp := new(MockEventDispatcher)
 
+	walletID := uuid.New()
 	orderID := uuid.New()
 	paymentID := uuid.New()
 
@@ -104,7 +116,7 @@ func TestCreatePayment_RepoError(t *testing.T) {
 
 	svc := NewPaymentService(paymentRepo, eventDisp)
 
-	_, err := svc.CreatePayment(orderID, testAmount)
+	_, err := svc.CreatePayment(walletID, orderID, testAmount)
 	assert.Error(t, err)
 	paymentRepo.AssertExpectations(t)
 	eventDisp.AssertExpectations(t)
@@ -114,6 +126,7 @@ func TestCreatePayment_EventDispatchError(t *testing.T) {
 	paymentRepo := new(MockPaymentRepository)
 	eventDisp := new(MockEventDispatcher)
 
+	walletID := uuid.New()
 	orderID := uuid.New()
 	paymentID := uuid.New()
 
@@ -123,12 +136,23 @@ func TestCreatePayment_EventDispatchError(t *testing.T) {
 
 	svc := NewPaymentService(paymentRepo, eventDisp)
 
-	_, err := svc.CreatePayment(orderID, testAmount)
+	_, err := svc.CreatePayment(walletID, orderID, testAmount)
 	assert.Error(t, err)
 	paymentRepo.AssertExpectations(t)
 	eventDisp.AssertExpectations(t)
 }
 
+func TestCreatePayment_InvalidAmount(t *testing.T) {
+	paymentRepo := new(MockPaymentRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	svc := NewPaymentService(paymentRepo, eventDisp)
+
+	_, err := svc.CreatePayment(uuid.New(), uuid.New(), 0)
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+	paymentRepo.AssertNotCalled(t, "Store", mock.Anything)
+}
+
 func TestRemovePayment_Success(t *testing.T) {
 	paymentRepo := new(MockPaymentRepository)
 	eventDisp := new(MockEventDispatcher)
@@ -195,7 +219,7 @@ func TestSetStatus_ValidTransition_PendingToProcessing(t *testing.T) {
 	})).Return(nil)
 
 	eventDisp.On("Dispatch", mock.MatchedBy(func(e model.PaymentStatusChanged) bool {
-		return e.PaymentID == paymentID && e.From == model.Pending && e.To == model.Processing
+		return e.PaymentID == paymentID && e.OrderID == orderID && e.From == model.Pending && e.To == model.Processing
 	})).Return(nil)
 
 	svc := NewPaymentService(paymentRepo, eventDisp)
@@ -257,6 +281,10 @@ func TestIsValidStatusTransition_Matrix(t *testing.T) {
 		{model.Succeeded, model.Pending, false, "Succeeded → Pending"},
 		{model.Failed, model.Succeeded, false, "Failed → Succeeded"},
 		{model.Cancelled, model.Succeeded, false, "Cancelled → Succeeded"},
+
+		{model.Succeeded, model.Refunded, true, "Succeeded → Refunded"},
+		{model.Processing, model.Refunded, false, "Processing → Refunded"},
+		{model.Refunded, model.Succeeded, false, "Refunded → Succeeded"},
 	}
 
 	for _, tt := range tests {
@@ -265,3 +293,123 @@ func TestIsValidStatusTransition_Matrix(t *testing.T) {
 		})
 	}
 }
+
+func TestRefund_Partial(t *testing.T) {
+	paymentRepo := new(MockPaymentRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	paymentID := uuid.New()
+	paymentRepo.On("Find", paymentID).Return(newSucceededPayment(paymentID, uuid.New()), nil)
+	paymentRepo.On("Store", mock.MatchedBy(func(p *model.Payment) bool {
+		return p.Status == model.Succeeded && p.RefundedAmount == 10
+	})).Return(nil)
+
+	svc := NewPaymentService(paymentRepo, eventDisp)
+
+	refunded, err := svc.Refund(paymentID, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 10.0, refunded)
+	paymentRepo.AssertExpectations(t)
+	eventDisp.AssertNotCalled(t, "Dispatch", mock.Anything)
+}
+
+func TestRefund_RemainderOnly(t *testing.T) {
+	paymentRepo := new(MockPaymentRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	paymentID := uuid.New()
+	payment := newSucceededPayment(paymentID, uuid.New())
+	payment.RefundedAmount = 10
+	paymentRepo.On("Find", paymentID).Return(payment, nil)
+	paymentRepo.On("Store", mock.MatchedBy(func(p *model.Payment) bool {
+		return p.Status == model.Refunded && p.RefundedAmount == testAmount
+	})).Return(nil)
+	eventDisp.On("Dispatch", mock.MatchedBy(func(e model.PaymentStatusChanged) bool {
+		return e.PaymentID == paymentID && e.From == model.Succeeded && e.To == model.Refunded
+	})).Return(nil)
+
+	svc := NewPaymentService(paymentRepo, eventDisp)
+
+	refunded, err := svc.Refund(paymentID, testAmount)
+	assert.NoError(t, err)
+	assert.Equal(t, testAmount-10, refunded)
+	paymentRepo.AssertExpectations(t)
+	eventDisp.AssertExpectations(t)
+}
+
+func TestRefund_Refunded(t *testing.T) {
+	paymentRepo := new(MockPaymentRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	paymentID := uuid.New()
+	payment := newSucceededPayment(paymentID, uuid.New())
+	payment.Status = model.Refunded
+	paymentRepo.On("Find", paymentID).Return(payment, nil)
+
+	svc := NewPaymentService(paymentRepo, eventDisp)
+
+	refunded, err := svc.Refund(paymentID, testAmount)
+	assert.NoError(t, err)
+	assert.Zero(t, refunded)
+	paymentRepo.AssertNotCalled(t, "Store", mock.Anything)
+}
+
+func TestCapturePayment_Partial(t *testing.T) {
+	paymentRepo := new(MockPaymentRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	paymentID := uuid.New()
+	payment := newPendingPayment(paymentID, uuid.New())
+	payment.Status = model.Processing
+	paymentRepo.On("Find", paymentID).Return(payment, nil)
+	paymentRepo.On("Store", mock.MatchedBy(func(p *model.Payment) bool {
+		return p.Status == model.Succeeded && p.Amount == testAmount && p.CapturedAmount == 50
+	})).Return(nil)
+	eventDisp.On("Dispatch", mock.MatchedBy(func(e model.PaymentStatusChanged) bool {
+		return e.PaymentID == paymentID && e.From == model.Processing && e.To == model.Succeeded
+	})).Return(nil)
+
+	svc := NewPaymentService(paymentRepo, eventDisp)
+
+	err := svc.Capture(paymentID, 50)
+	assert.NoError(t, err)
+	paymentRepo.AssertExpectations(t)
+	eventDisp.AssertExpectations(t)
+}
+
+func TestRefund_PartiallyCaptured(t *testing.T) {
+	paymentRepo := new(MockPaymentRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	paymentID := uuid.New()
+	payment := newSucceededPayment(paymentID, uuid.New())
+	payment.CapturedAmount = 50
+	paymentRepo.On("Find", paymentID).Return(payment, nil)
+	paymentRepo.On("Store", mock.MatchedBy(func(p *model.Payment) bool {
+		return p.Status == model.Refunded && p.Amount == testAmount && p.RefundedAmount == 50
+	})).Return(nil)
+	eventDisp.On("Dispatch", mock.Anything).Return(nil)
+
+	svc := NewPaymentService(paymentRepo, eventDisp)
+
+	refunded, err := svc.Refund(paymentID, testAmount)
+	assert.NoError(t, err)
+	assert.Equal(t, 50.0, refunded)
+	paymentRepo.AssertExpectations(t)
+}
+
+func TestCapturePayment_ExceedsAmount(t *testing.T) {
+	paymentRepo := new(MockPaymentRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	paymentID := uuid.New()
+	payment := newPendingPayment(paymentID, uuid.New())
+	payment.Status = model.Processing
+	paymentRepo.On("Find", paymentID).Return(payment, nil)
+
+	svc := NewPaymentService(paymentRepo, eventDisp)
+
+	err := svc.Capture(paymentID, testAmount+1)
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+	paymentRepo.AssertNotCalled(t, "Store", mock.Anything)
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"errors"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,24 +15,44 @@ const defaultBalance = 100000.0
 
 var (
 	ErrInvalidWalletBalance = errors.New("invalid wallet balance")
+	ErrInvalidAmount        = errors.New("amount must be positive")
+	// ErrWalletLedgerMismatch means the wallet balance differs from the sum of its ledger
+	ErrWalletLedgerMismatch = errors.New("wallet balance does not match ledger")
 )
 
 type Wallet interface {
 	CreateWallet(userID uuid.UUID) (uuid.UUID, error)
 	RemoveWallet(walletID uuid.UUID) error
 	UpdateWalletBalance(walletID uuid.UUID, newBalance float64) error
+	// Debit charges amount for the order from the wallet of the user,
+	// ErrInsufficientFunds is returned when the amount exceeds the balance left by active holds
+	Debit(userID, orderID uuid.UUID, amount float64) error
+	// Credit refunds amount for the order to the wallet of the user
+	Credit(userID, orderID uuid.UUID, amount float64) error
+	// RefundReturn credits amount of the order return to the wallet of the user, the return is refunded once
+	RefundReturn(userID, orderID, returnID uuid.UUID, amount float64) error
+	TopUpWallet(walletID uuid.UUID, amount float64) error
 }
 
-func NewWalletService(repo model.WalletRepository, dispatcher commonevent.Dispatcher) Wallet {
+func NewWalletService(
+	repo model.WalletRepository,
+	transactionRepo model.WalletTransactionRepository,
+	holdRepo model.WalletHoldRepository,
+	dispatcher commonevent.Dispatcher,
+) Wallet {
 	return &walletService{
-		repo:       repo,
-		dispatcher: dispatcher,
+		repo:            repo,
+		transactionRepo: transactionRepo,
+		holdRepo:        holdRepo,
+		dispatcher:      dispatcher,
 	}
 }
 
 type walletService struct {
-	repo       model.WalletRepository
-	dispatcher commonevent.Dispatcher
+	repo            model.WalletRepository
+	transactionRepo model.WalletTransactionRepository
+	holdRepo        model.WalletHoldRepository
+	dispatcher      commonevent.Dispatcher
 }
 
 func (w walletService) CreateWallet(userID uuid.UUID) (uuid.UUID, error) {
@@ -52,6 +73,10 @@ func (w walletService) CreateWallet(userID uuid.UUID) (uuid.UUID, error) {
 	if err != nil {
 		return uuid.Nil, err
 	}
+	err = w.appendTransaction(walletID, initialBalance, model.ReasonTopUp, nil, nil)
+	if err != nil {
+		return uuid.Nil, err
+	}
 
 	return walletID, w.dispatcher.Dispatch(model.WalletCreated{
 		WalletID: walletID,
@@ -88,22 +113,146 @@ func (w walletService) UpdateWalletBalance(walletID uuid.UUID, newBalance float6
 		return err
 	}
 
-	oldBalance := wallet.Balance
-
 	if newBalance < 0 {
 		return ErrInvalidWalletBalance
 	}
 
-	wallet.Balance = newBalance
+	return w.changeBalance(wallet, newBalance-wallet.Balance, model.ReasonAdjustment, nil, nil)
+}
+
+func (w walletService) Debit(userID, orderID uuid.UUID, amount float64) error {
+	if amount <= 0 {
+		return ErrInvalidAmount
+	}
+
+	wallet, err := w.repo.FindByUserID(userID)
+	if err != nil {
+		return err
+	}
+	held, err := w.holdRepo.ActiveAmount(wallet.ID)
+	if err != nil {
+		return err
+	}
+	if roundToCents(wallet.Balance-held) < amount {
+		return model.ErrInsufficientFunds
+	}
+	return w.changeBalance(wallet, -amount, model.ReasonCharge, &orderID, nil)
+}
+
+func (w walletService) Credit(userID, orderID uuid.UUID, amount float64) error {
+	if amount <= 0 {
+		return ErrInvalidAmount
+	}
+
+	wallet, err := w.repo.FindByUserID(userID)
+	if err != nil {
+		return err
+	}
+	return w.changeBalance(wallet, amount, model.ReasonRefund, &orderID, nil)
+}
+
+func (w walletService) RefundReturn(userID, orderID, returnID uuid.UUID, amount float64) error {
+	if amount <= 0 {
+		return ErrInvalidAmount
+	}
+
+	refunded, err := w.transactionRepo.HasReference(returnID)
+	if err != nil {
+		return err
+	}
+	if refunded {
+		return nil
+	}
+
+	wallet, err := w.repo.FindByUserID(userID)
+	if err != nil {
+		return err
+	}
+	return w.changeBalance(wallet, amount, model.ReasonRefund, &orderID, &returnID)
+}
+
+func (w walletService) TopUpWallet(walletID uuid.UUID, amount float64) error {
+	if amount <= 0 {
+		return ErrInvalidAmount
+	}
+
+	wallet, err := w.repo.Find(walletID)
+	if err != nil {
+		return err
+	}
+	if wallet.DeletedAt != nil {
+		return model.ErrWalletNotFound
+	}
+	return w.changeBalance(wallet, amount, model.ReasonTopUp, nil, nil)
+}
+
+// changeBalance records delta in the ledger and applies it to the wallet balance checked against the ledger
+func (w walletService) changeBalance(
+	wallet *model.Wallet,
+	delta float64,
+	reason model.WalletTransactionReason,
+	orderID *uuid.UUID,
+	referenceID *uuid.UUID,
+) error {
+	ledgerBalance, err := w.transactionRepo.Balance(wallet.ID)
+	if err != nil {
+		return err
+	}
+	if roundToCents(ledgerBalance) != roundToCents(wallet.Balance) {
+		return ErrWalletLedgerMismatch
+	}
+
+	oldBalance := wallet.Balance
+	wallet.Balance = roundToCents(oldBalance + delta)
 	wallet.UpdatedAt = time.Now()
 
+	if delta != 0 {
+		if err = w.appendTransaction(wallet.ID, delta, reason, orderID, referenceID); err != nil {
+			return err
+		}
+	}
 	if err = w.repo.Store(wallet); err != nil {
 		return err
 	}
 
 	return w.dispatcher.Dispatch(model.WalletBalanceChanged{
-		WalletID:   walletID,
+		WalletID:   wallet.ID,
+		UserID:     wallet.UserID,
 		OldBalance: oldBalance,
-		NewBalance: newBalance,
+		NewBalance: wallet.Balance,
+	})
+}
+
+// appendTransaction records delta in the ledger as a debit if it is negative and as a credit otherwise
+func (w walletService) appendTransaction(
+	walletID uuid.UUID,
+	delta float64,
+	reason model.WalletTransactionReason,
+	orderID *uuid.UUID,
+	referenceID *uuid.UUID,
+) error {
+	transactionID, err := w.transactionRepo.NextID()
+	if err != nil {
+		return err
+	}
+
+	direction := model.TransactionCredit
+	if delta < 0 {
+		direction = model.TransactionDebit
+	}
+	return w.transactionRepo.Append(&model.WalletTransaction{
+		ID:          transactionID,
+		WalletID:    walletID,
+		Amount:      roundToCents(math.Abs(delta)),
+		Direction:   direction,
+		Reason:      reason,
+		OrderID:     orderID,
+		ReferenceID: referenceID,
+		CreatedAt:   time.Now(),
 	})
 }
+
+// roundToCents rounds amount as balances are stored with cents precision
+func roundToCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -34,11 +34,43 @@ func (m *MockWalletRepository) Find(id uuid.UUID) (*model.Wallet, error) {
 	return nil, args.Error(1)
 }
 
+func (m *MockWalletRepository) FindByUserID(userID uuid.UUID) (*model.Wallet, error) {
+	args := m.Called(userID)
+	if wallet, ok := args.Get(0).(*model.Wallet); ok {
+		return wallet, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockWalletRepository) Remove(id uuid.UUID) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
+type MockWalletTransactionRepository struct {
+	mock.Mock
+}
+
+func (m *MockWalletTransactionRepository) NextID() (uuid.UUID, error) {
+	args := m.Called()
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockWalletTransactionRepository) Append(transaction *model.WalletTransaction) error {
+	args := m.Called(transaction)
+	return args.Error(0)
+}
+
+func (m *MockWalletTransactionRepository) Balance(walletID uuid.UUID) (float64, error) {
+	args := m.Called(walletID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockWalletTransactionRepository) HasReference(referenceID uuid.UUID) (bool, error) {
+	args := m.Called(referenceID)
+	return args.Bool(0), args.Error(1)
+}
+
 func newWallet(id, userID uuid.UUID, balance float64) *model.Wallet {
 	now := time.Now()
 	return &model.Wallet{
@@ -52,6 +84,8 @@ func newWallet(id, userID uuid.UUID, balance float64) *model.Wallet {
 
 func TestCreateWallet_Success(t *testing.T) {
 	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
 	eventDispatcher := new(MockEventDispatcher)
 
 	userID := uuid.New()
@@ -66,22 +100,36 @@ func TestCreateWallet_Success(t *testing.T) {
 			wallet.UpdatedAt.Equal(wallet.CreatedAt)
 	})).Return(nil)
 
+	transactionID := uuid.New()
+	transactionRepo.On("NextID").Return(transactionID, nil)
+	transactionRepo.On("Append", mock.MatchedBy(func(tx *model.WalletTransaction) bool {
+		return tx.ID == transactionID &&
+			tx.WalletID == walletID &&
+			tx.Amount == defaultBalance &&
+			tx.Direction == model.TransactionCredit &&
+			tx.Reason == model.ReasonTopUp &&
+			tx.OrderID == nil
+	})).Return(nil)
+
 	eventDispatcher.On("Dispatch", mock.MatchedBy(func(e model.WalletCreated) bool {
 		return e.WalletID == walletID && e.UserID == userID && e.Balance == defaultBalance
 	})).Return(nil)
 
-	svc := NewWalletService(walletRepo, eventDispatcher)
+	svc := NewWalletService(walletRepo, transactionRepo, holdRepo, eventDispatcher)
 
 	id, err := svc.CreateWallet(userID)
 
 	assert.NoError(t, err)
 	assert.Equal(t, walletID, id)
 	walletRepo.AssertExpectations(t)
+	transactionRepo.AssertExpectations(t)
 	eventDispatcher.AssertExpectations(t)
 }
 
 func TestCreateWallet_RepoError(t *testing.T) {
 	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
 	eventDisp := new(MockEventDispatcher)
 
 	userID := uuid.New()
@@ -90,7 +138,7 @@ func TestCreateWallet_RepoError(t *testing.T) {
 	walletRepo.On("NextID").Return(walletID, nil)
 	walletRepo.On("Store", mock.Anything).Return(errors.New("db down"))
 
-	svc := NewWalletService(walletRepo, eventDisp)
+	svc := NewWalletService(walletRepo, transactionRepo, holdRepo, eventDisp)
 
 	_, err := svc.CreateWallet(userID)
 	assert.Error(t, err)
@@ -100,6 +148,8 @@ func TestCreateWallet_RepoError(t *testing.T) {
 
 func TestCreateWallet_EventDispatchError(t *testing.T) {
 	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
 	eventDisp := new(MockEventDispatcher)
 
 	userID := uuid.New()
@@ -107,9 +157,11 @@ func TestCreateWallet_EventDispatchError(t *testing.T) {
 
 	walletRepo.On("NextID").Return(walletID, nil)
 	walletRepo.On("Store", mock.Anything).Return(nil)
+	transactionRepo.On("NextID").Return(uuid.New(), nil)
+	transactionRepo.On("Append", mock.Anything).Return(nil)
 	eventDisp.On("Dispatch", mock.Anything).Return(errors.New("kafka unreachable"))
 
-	svc := NewWalletService(walletRepo, eventDisp)
+	svc := NewWalletService(walletRepo, transactionRepo, holdRepo, eventDisp)
 
 	_, err := svc.CreateWallet(userID)
 	assert.Error(t, err)
@@ -119,6 +171,8 @@ func TestCreateWallet_EventDispatchError(t *testing.T) {
 
 func TestRemoveWallet_Success(t *testing.T) {
 	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
 	eventDisp := new(MockEventDispatcher)
 
 	walletID := uuid.New()
@@ -134,7 +188,7 @@ func TestRemoveWallet_Success(t *testing.T) {
 		return e.WalletID == walletID
 	})).Return(nil)
 
-	svc := NewWalletService(walletRepo, eventDisp)
+	svc := NewWalletService(walletRepo, transactionRepo, holdRepo, eventDisp)
 
 	err := svc.RemoveWallet(walletID)
 	assert.NoError(t, err)
@@ -144,12 +198,14 @@ func TestRemoveWallet_Success(t *testing.T) {
 
 func TestRemoveWallet_NotFound_Idempotent(t *testing.T) {
 	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
 	eventDisp := new(MockEventDispatcher)
 
 	walletID := uuid.New()
 	walletRepo.On("Find", walletID).Return(nil, model.ErrWalletNotFound)
 
-	svc := NewWalletService(walletRepo, eventDisp)
+	svc := NewWalletService(walletRepo, transactionRepo, holdRepo, eventDisp)
 
 	err := svc.RemoveWallet(walletID)
 	assert.NoError(t, err)
@@ -158,12 +214,14 @@ func TestRemoveWallet_NotFound_Idempotent(t *testing.T) {
 
 func TestRemoveWallet_FindError(t *testing.T) {
 	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
 	eventDisp := new(MockEventDispatcher)
 
 	walletID := uuid.New()
 	walletRepo.On("Find", walletID).Return(nil, errors.New("db timeout"))
 
-	svc := NewWalletService(walletRepo, eventDisp)
+	svc := NewWalletService(walletRepo, transactionRepo, holdRepo, eventDisp)
 
 	err := svc.RemoveWallet(walletID)
 	assert.Error(t, err)
@@ -171,6 +229,8 @@ func TestRemoveWallet_FindError(t *testing.T) {
 
 func TestUpdateWalletBalance_Success(t *testing.T) {
 	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
 	eventDisp := new(MockEventDispatcher)
 
 	walletID := uuid.New()
@@ -184,20 +244,32 @@ func TestUpdateWalletBalance_Success(t *testing.T) {
 		return w.ID == walletID && w.Balance == newBalance && w.UpdatedAt.After(w.CreatedAt)
 	})).Return(nil)
 
+	transactionRepo.On("Balance", walletID).Return(oldBalance, nil)
+	transactionRepo.On("NextID").Return(uuid.New(), nil)
+	transactionRepo.On("Append", mock.MatchedBy(func(tx *model.WalletTransaction) bool {
+		return tx.WalletID == walletID &&
+			tx.Amount == newBalance-oldBalance &&
+			tx.Direction == model.TransactionCredit &&
+			tx.Reason == model.ReasonAdjustment
+	})).Return(nil)
+
 	eventDisp.On("Dispatch", mock.MatchedBy(func(e model.WalletBalanceChanged) bool {
 		return e.WalletID == walletID && e.OldBalance == oldBalance && e.NewBalance == newBalance
 	})).Return(nil)
 
-	svc := NewWalletService(walletRepo, eventDisp)
+	svc := NewWalletService(walletRepo, transactionRepo, holdRepo, eventDisp)
 
 	err := svc.UpdateWalletBalance(walletID, newBalance)
 	assert.NoError(t, err)
 	walletRepo.AssertExpectations(t)
+	transactionRepo.AssertExpectations(t)
 	eventDisp.AssertExpectations(t)
 }
 
 func TestUpdateWalletBalance_WalletNotFound(t *testing.T) {
 	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
 	eventDisp := new(MockEventDispatcher)
 
 	walletID := uuid.New()
@@ -205,9 +277,270 @@ func TestUpdateWalletBalance_WalletNotFound(t *testing.T) {
 
 	walletRepo.On("Find", walletID).Return(nil, model.ErrWalletNotFound)
 
-	svc := NewWalletService(walletRepo, eventDisp)
+	svc := NewWalletService(walletRepo, transactionRepo, holdRepo, eventDisp)
 
 	err := svc.UpdateWalletBalance(walletID, newBalance)
 	assert.ErrorIs(t, err, model.ErrWalletNotFound)
 	walletRepo.AssertExpectations(t)
 }
+
+func TestDebit_Success(t *testing.T) {
+	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	walletID := uuid.New()
+	userID := uuid.New()
+
+	walletRepo.On("FindByUserID", userID).Return(newWallet(walletID, userID, 100.0), nil)
+	walletRepo.On("Store", mock.MatchedBy(func(w *model.Wallet) bool {
+		return w.ID == walletID && w.Balance == 70.0
+	})).Return(nil)
+	holdRepo.On("ActiveAmount", walletID).Return(0.0, nil)
+
+	orderID := uuid.New()
+	transactionRepo.On("Balance", walletID).Return(100.0, nil)
+	transactionRepo.On("NextID").Return(uuid.New(), nil)
+	transactionRepo.On("Append", mock.MatchedBy(func(tx *model.WalletTransaction) bool {
+		return tx.WalletID == walletID &&
+			tx.Amount == 30.0 &&
+			tx.Direction == model.TransactionDebit &&
+			tx.Reason == model.ReasonCharge &&
+			tx.OrderID != nil && *tx.OrderID == orderID
+	})).Return(nil)
+
+	eventDisp.On("Dispatch", mock.MatchedBy(func(e model.WalletBalanceChanged) bool {
+		return e.WalletID == walletID && e.UserID == userID && e.OldBalance == 100.0 && e.NewBalance == 70.0
+	})).Return(nil)
+
+	svc := NewWalletService(walletRepo, transactionRepo, holdRepo, eventDisp)
+
+	err := svc.Debit(userID, orderID, 30.0)
+	assert.NoError(t, err)
+	walletRepo.AssertExpectations(t)
+	transactionRepo.AssertExpectations(t)
+	eventDisp.AssertExpectations(t)
+}
+
+func TestDebit_InsufficientFunds(t *testing.T) {
+	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	userID := uuid.New()
+	walletID := uuid.New()
+	walletRepo.On("FindByUserID", userID).Return(newWallet(walletID, userID, 10.0), nil)
+	holdRepo.On("ActiveAmount", walletID).Return(0.0, nil)
+
+	svc := NewWalletService(walletRepo, transactionRepo, holdRepo, eventDisp)
+
+	err := svc.Debit(userID, uuid.New(), 30.0)
+	assert.ErrorIs(t, err, model.ErrInsufficientFunds)
+	walletRepo.AssertNotCalled(t, "Store", mock.Anything)
+	transactionRepo.AssertNotCalled(t, "Append", mock.Anything)
+	eventDisp.AssertNotCalled(t, "Dispatch", mock.Anything)
+}
+
+func TestDebit_FundsHeld(t *testing.T) {
+	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	userID := uuid.New()
+	walletID := uuid.New()
+	walletRepo.On("FindByUserID", userID).Return(newWallet(walletID, userID, 100.0), nil)
+	holdRepo.On("ActiveAmount", walletID).Return(80.0, nil)
+
+	svc := NewWalletService(walletRepo, transactionRepo, holdRepo, eventDisp)
+
+	err := svc.Debit(userID, uuid.New(), 30.0)
+	assert.ErrorIs(t, err, model.ErrInsufficientFunds)
+	walletRepo.AssertNotCalled(t, "Store", mock.Anything)
+	transactionRepo.AssertNotCalled(t, "Append", mock.Anything)
+}
+
+func TestDebit_InvalidAmount(t *testing.T) {
+	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	svc := NewWalletService(walletRepo, transactionRepo, holdRepo, eventDisp)
+
+	err := svc.Debit(uuid.New(), uuid.New(), 0)
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+	walletRepo.AssertNotCalled(t, "FindByUserID", mock.Anything)
+}
+
+func TestCredit_Success(t *testing.T) {
+	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	walletID := uuid.New()
+	userID := uuid.New()
+
+	walletRepo.On("FindByUserID", userID).Return(newWallet(walletID, userID, 10.0), nil)
+	walletRepo.On("Store", mock.MatchedBy(func(w *model.Wallet) bool {
+		return w.ID == walletID && w.Balance == 35.5
+	})).Return(nil)
+
+	orderID := uuid.New()
+	transactionRepo.On("Balance", walletID).Return(10.0, nil)
+	transactionRepo.On("NextID").Return(uuid.New(), nil)
+	transactionRepo.On("Append", mock.MatchedBy(func(tx *model.WalletTransaction) bool {
+		return tx.Amount == 25.5 &&
+			tx.Direction == model.TransactionCredit &&
+			tx.Reason == model.ReasonRefund &&
+			tx.OrderID != nil && *tx.OrderID == orderID
+	})).Return(nil)
+
+	eventDisp.On("Dispatch", mock.MatchedBy(func(e model.WalletBalanceChanged) bool {
+		return e.WalletID == walletID && e.OldBalance == 10.0 && e.NewBalance == 35.5
+	})).Return(nil)
+
+	svc := NewWalletService(walletRepo, transactionRepo, holdRepo, eventDisp)
+
+	err := svc.Credit(userID, orderID, 25.5)
+	assert.NoError(t, err)
+	walletRepo.AssertExpectations(t)
+	transactionRepo.AssertExpectations(t)
+	eventDisp.AssertExpectations(t)
+}
+
+func TestCredit_WalletNotFound(t *testing.T) {
+	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	userID := uuid.New()
+	walletRepo.On("FindByUserID", userID).Return(nil, model.ErrWalletNotFound)
+
+	svc := NewWalletService(walletRepo, transactionRepo, holdRepo, eventDisp)
+
+	err := svc.Credit(userID, uuid.New(), 25.5)
+	assert.ErrorIs(t, err, model.ErrWalletNotFound)
+}
+
+func TestRefundReturn_Success(t *testing.T) {
+	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	walletID, userID, orderID, returnID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	transactionRepo.On("HasReference", returnID).Return(false, nil)
+	walletRepo.On("FindByUserID", userID).Return(newWallet(walletID, userID, 10.0), nil)
+	walletRepo.On("Store", mock.Anything).Return(nil)
+	transactionRepo.On("Balance", walletID).Return(10.0, nil)
+	transactionRepo.On("NextID").Return(uuid.New(), nil)
+	transactionRepo.On("Append", mock.MatchedBy(func(tx *model.WalletTransaction) bool {
+		return tx.Amount == 5.0 &&
+			tx.Reason == model.ReasonRefund &&
+			tx.ReferenceID != nil && *tx.ReferenceID == returnID
+	})).Return(nil)
+	eventDisp.On("Dispatch", mock.Anything).Return(nil)
+
+	svc := NewWalletService(walletRepo, transactionRepo, holdRepo, eventDisp)
+
+	err := svc.RefundReturn(userID, orderID, returnID, 5.0)
+	assert.NoError(t, err)
+	walletRepo.AssertExpectations(t)
+	transactionRepo.AssertExpectations(t)
+}
+
+func TestRefundReturn_Repeated(t *testing.T) {
+	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	returnID := uuid.New()
+	transactionRepo.On("HasReference", returnID).Return(true, nil)
+
+	svc := NewWalletService(walletRepo, transactionRepo, holdRepo, eventDisp)
+
+	err := svc.RefundReturn(uuid.New(), uuid.New(), returnID, 5.0)
+	assert.NoError(t, err)
+	walletRepo.AssertNotCalled(t, "Store", mock.Anything)
+	transactionRepo.AssertNotCalled(t, "Append", mock.Anything)
+}
+
+func TestDebit_LedgerMismatch(t *testing.T) {
+	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	walletID := uuid.New()
+	userID := uuid.New()
+
+	walletRepo.On("FindByUserID", userID).Return(newWallet(walletID, userID, 100.0), nil)
+	holdRepo.On("ActiveAmount", walletID).Return(0.0, nil)
+	transactionRepo.On("Balance", walletID).Return(90.0, nil)
+
+	svc := NewWalletService(walletRepo, transactionRepo, holdRepo, eventDisp)
+
+	err := svc.Debit(userID, uuid.New(), 30.0)
+	assert.ErrorIs(t, err, ErrWalletLedgerMismatch)
+	transactionRepo.AssertNotCalled(t, "Append", mock.Anything)
+	walletRepo.AssertNotCalled(t, "Store", mock.Anything)
+}
+
+func TestTopUpWallet_Success(t *testing.T) {
+	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	walletID := uuid.New()
+	userID := uuid.New()
+
+	walletRepo.On("Find", walletID).Return(newWallet(walletID, userID, 10.0), nil)
+	walletRepo.On("Store", mock.MatchedBy(func(w *model.Wallet) bool {
+		return w.ID == walletID && w.Balance == 60.0
+	})).Return(nil)
+	transactionRepo.On("Balance", walletID).Return(10.0, nil)
+	transactionRepo.On("NextID").Return(uuid.New(), nil)
+	transactionRepo.On("Append", mock.MatchedBy(func(tx *model.WalletTransaction) bool {
+		return tx.Amount == 50.0 &&
+			tx.Direction == model.TransactionCredit &&
+			tx.Reason == model.ReasonTopUp &&
+			tx.OrderID == nil
+	})).Return(nil)
+	eventDisp.On("Dispatch", mock.MatchedBy(func(e model.WalletBalanceChanged) bool {
+		return e.WalletID == walletID && e.OldBalance == 10.0 && e.NewBalance == 60.0
+	})).Return(nil)
+
+	svc := NewWalletService(walletRepo, transactionRepo, holdRepo, eventDisp)
+
+	err := svc.TopUpWallet(walletID, 50.0)
+	assert.NoError(t, err)
+	walletRepo.AssertExpectations(t)
+	transactionRepo.AssertExpectations(t)
+	eventDisp.AssertExpectations(t)
+}
+
+func TestTopUpWallet_Removed(t *testing.T) {
+	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	walletID := uuid.New()
+	wallet := newWallet(walletID, uuid.New(), 10.0)
+	deletedAt := time.Now()
+	wallet.DeletedAt = &deletedAt
+	walletRepo.On("Find", walletID).Return(wallet, nil)
+
+	svc := NewWalletService(walletRepo, transactionRepo, holdRepo, eventDisp)
+
+	err := svc.TopUpWallet(walletID, 50.0)
+	assert.ErrorIs(t, err, model.ErrWalletNotFound)
+	walletRepo.AssertNotCalled(t, "Store", mock.Anything)
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	commonevent "payment/pkg/common/event"
+	"payment/pkg/payment/domain/model"
+)
+
+// defaultHoldTTL is used when the hold is authorized without ttl
+const defaultHoldTTL = 7 * 24 * time.Hour
+
+var (
+	ErrInvalidHoldStatus  = errors.New("invalid wallet hold status")
+	ErrWalletHoldExpired  = errors.New("wallet hold expired")
+	ErrCaptureExceedsHold = errors.New("captured amount exceeds wallet hold")
+	// ErrWalletHoldNotExpired means the hold is expired too early, it is to be retried once ExpiresAt passes
+	ErrWalletHoldNotExpired = errors.New("wallet hold is not expired yet")
+)
+
+type WalletHold interface {
+	// Authorize holds amount of the wallet of the user for the order until ttl passes, zero ttl holds for defaultHoldTTL,
+	// repeated call returns the active or captured hold of the order
+	Authorize(userID, orderID uuid.UUID, amount float64, ttl time.Duration) (uuid.UUID, error)
+	// Capture debits amount of the active hold from the wallet and releases the rest, zero amount captures the whole hold,
+	// repeated call returns the captured amount
+	Capture(holdID uuid.UUID, amount float64) (float64, error)
+	// Void releases the active hold, released hold is not changed
+	Void(holdID uuid.UUID) error
+	// Expire releases the hold if it is still active, ErrWalletHoldNotExpired is returned until its ExpiresAt passes
+	Expire(holdID uuid.UUID) error
+}
+
+func NewWalletHoldService(
+	holdRepo model.WalletHoldRepository,
+	walletRepo model.WalletRepository,
+	walletService Wallet,
+	dispatcher commonevent.Dispatcher,
+) WalletHold {
+	return &walletHoldService{
+		holdRepo:      holdRepo,
+		walletRepo:    walletRepo,
+		walletService: walletService,
+		dispatcher:    dispatcher,
+	}
+}
+
+type walletHoldService struct {
+	holdRepo      model.WalletHoldRepository
+	walletRepo    model.WalletRepository
+	walletService Wallet
+	dispatcher    commonevent.Dispatcher
+}
+
+func (w walletHoldService) Authorize(userID, orderID uuid.UUID, amount float64, ttl time.Duration) (uuid.UUID, error) {
+	if amount <= 0 {
+		return uuid.Nil, ErrInvalidAmount
+	}
+	if ttl <= 0 {
+		ttl = defaultHoldTTL
+	}
+
+	hold, err := w.holdRepo.FindByOrderID(orderID)
+	if err == nil && (hold.Status == model.HoldActive || hold.Status == model.HoldCaptured) {
+		return hold.ID, nil
+	}
+	if err != nil && !errors.Is(err, model.ErrWalletHoldNotFound) {
+		return uuid.Nil, err
+	}
+
+	wallet, err := w.walletRepo.FindByUserID(userID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	held, err := w.holdRepo.ActiveAmount(wallet.ID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	amount = roundToCents(amount)
+	if roundToCents(wallet.Balance-held) < amount {
+		return uuid.Nil, model.ErrInsufficientFunds
+	}
+
+	holdID, err := w.holdRepo.NextID()
+	if err != nil {
+		return uuid.Nil, err
+	}
+	now := time.Now()
+	hold = &model.WalletHold{
+		ID:        holdID,
+		WalletID:  wallet.ID,
+		OrderID:   orderID,
+		Amount:    amount,
+		Status:    model.HoldActive,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err = w.holdRepo.Store(hold); err != nil {
+		return uuid.Nil, err
+	}
+
+	// Wallet version is incremented, so a concurrent debit checking the available balance conflicts with the hold
+	wallet.UpdatedAt = now
+	if err = w.walletRepo.Store(wallet); err != nil {
+		return uuid.Nil, err
+	}
+
+	return holdID, w.dispatcher.Dispatch(model.WalletHoldAuthorized{
+		HoldID:    holdID,
+		WalletID:  wallet.ID,
+		OrderID:   orderID,
+		Amount:    amount,
+		ExpiresAt: hold.ExpiresAt,
+	})
+}
+
+func (w walletHoldService) Capture(holdID uuid.UUID, amount float64) (float64, error) {
+	if amount < 0 {
+		return 0, ErrInvalidAmount
+	}
+
+	hold, err := w.holdRepo.Find(holdID)
+	if err != nil {
+		return 0, err
+	}
+	switch hold.Status {
+	case model.HoldCaptured:
+		return hold.CapturedAmount, nil
+	case model.HoldExpired:
+		return 0, ErrWalletHoldExpired
+	case model.HoldActive:
+	default:
+		return 0, ErrInvalidHoldStatus
+	}
+	now := time.Now()
+	if !now.Before(hold.ExpiresAt) {
+		return 0, ErrWalletHoldExpired
+	}
+
+	if amount == 0 {
+		amount = hold.Amount
+	}
+	amount = roundToCents(amount)
+	if amount > hold.Amount {
+		return 0, ErrCaptureExceedsHold
+	}
+
+	// Hold is captured before the debit, so its own amount is not taken off the available balance
+	hold.Status = model.HoldCaptured
+	hold.CapturedAmount = amount
+	hold.UpdatedAt = now
+	if err = w.holdRepo.Store(hold); err != nil {
+		return 0, err
+	}
+
+	wallet, err := w.walletRepo.Find(hold.WalletID)
+	if err != nil {
+		return 0, err
+	}
+	if err = w.walletService.Debit(wallet.UserID, hold.OrderID, amount); err != nil {
+		return 0, err
+	}
+
+	return amount, w.dispatcher.Dispatch(model.WalletHoldCaptured{
+		HoldID:   holdID,
+		WalletID: hold.WalletID,
+		OrderID:  hold.OrderID,
+		Amount:   amount,
+	})
+}
+
+func (w walletHoldService) Void(holdID uuid.UUID) error {
+	hold, err := w.holdRepo.Find(holdID)
+	if err != nil {
+		return err
+	}
+	switch hold.Status {
+	case model.HoldVoided, model.HoldExpired:
+		return nil
+	case model.HoldActive:
+		return w.release(hold, model.HoldVoided)
+	default:
+		return ErrInvalidHoldStatus
+	}
+}
+
+func (w walletHoldService) Expire(holdID uuid.UUID) error {
+	hold, err := w.holdRepo.Find(holdID)
+	if err != nil {
+		return err
+	}
+	if hold.Status != model.HoldActive {
+		return nil
+	}
+	if time.Now().Before(hold.ExpiresAt) {
+		return ErrWalletHoldNotExpired
+	}
+	return w.release(hold, model.HoldExpired)
+}
+
+func (w walletHoldService) release(hold *model.WalletHold, status model.WalletHoldStatus) error {
+	hold.Status = status
+	hold.UpdatedAt = time.Now()
+	if err := w.holdRepo.Store(hold); err != nil {
+		return err
+	}
+
+	return w.dispatcher.Dispatch(model.WalletHoldReleased{
+		HoldID:   hold.ID,
+		WalletID: hold.WalletID,
+		OrderID:  hold.OrderID,
+		Status:   status,
+	})
+}
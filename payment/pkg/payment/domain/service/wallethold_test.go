@@ -0,0 +1,331 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"payment/pkg/payment/domain/model"
+)
+
+type MockWalletHoldRepository struct {
+	mock.Mock
+}
+
+func (m *MockWalletHoldRepository) NextID() (uuid.UUID, error) {
+	args := m.Called()
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockWalletHoldRepository) Store(hold *model.WalletHold) error {
+	args := m.Called(hold)
+	return args.Error(0)
+}
+
+func (m *MockWalletHoldRepository) Find(id uuid.UUID) (*model.WalletHold, error) {
+	args := m.Called(id)
+	if hold, ok := args.Get(0).(*model.WalletHold); ok {
+		return hold, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockWalletHoldRepository) FindByOrderID(orderID uuid.UUID) (*model.WalletHold, error) {
+	args := m.Called(orderID)
+	if hold, ok := args.Get(0).(*model.WalletHold); ok {
+		return hold, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockWalletHoldRepository) ActiveAmount(walletID uuid.UUID) (float64, error) {
+	args := m.Called(walletID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func newActiveHold(id, walletID, orderID uuid.UUID, amount float64) *model.WalletHold {
+	now := time.Now()
+	return &model.WalletHold{
+		ID:        id,
+		WalletID:  walletID,
+		OrderID:   orderID,
+		Amount:    amount,
+		Status:    model.HoldActive,
+		ExpiresAt: now.Add(time.Hour),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func newWalletHoldService(
+	walletRepo *MockWalletRepository,
+	transactionRepo *MockWalletTransactionRepository,
+	holdRepo *MockWalletHoldRepository,
+	eventDisp *MockEventDispatcher,
+) WalletHold {
+	return NewWalletHoldService(holdRepo, walletRepo, NewWalletService(walletRepo, transactionRepo, holdRepo, eventDisp), eventDisp)
+}
+
+func TestAuthorize_Success(t *testing.T) {
+	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	userID := uuid.New()
+	walletID := uuid.New()
+	orderID := uuid.New()
+	holdID := uuid.New()
+
+	holdRepo.On("FindByOrderID", orderID).Return(nil, model.ErrWalletHoldNotFound)
+	walletRepo.On("FindByUserID", userID).Return(newWallet(walletID, userID, 100.0), nil)
+	holdRepo.On("ActiveAmount", walletID).Return(50.0, nil)
+	holdRepo.On("NextID").Return(holdID, nil)
+	holdRepo.On("Store", mock.MatchedBy(func(h *model.WalletHold) bool {
+		return h.ID == holdID &&
+			h.WalletID == walletID &&
+			h.OrderID == orderID &&
+			h.Amount == 40.0 &&
+			h.Status == model.HoldActive &&
+			h.ExpiresAt.Sub(h.CreatedAt) == time.Hour
+	})).Return(nil)
+	walletRepo.On("Store", mock.MatchedBy(func(w *model.Wallet) bool {
+		return w.ID == walletID && w.Balance == 100.0
+	})).Return(nil)
+	eventDisp.On("Dispatch", mock.MatchedBy(func(e model.WalletHoldAuthorized) bool {
+		return e.HoldID == holdID && e.WalletID == walletID && e.OrderID == orderID && e.Amount == 40.0
+	})).Return(nil)
+
+	svc := newWalletHoldService(walletRepo, transactionRepo, holdRepo, eventDisp)
+
+	id, err := svc.Authorize(userID, orderID, 40.0, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, holdID, id)
+	holdRepo.AssertExpectations(t)
+	walletRepo.AssertExpectations(t)
+	eventDisp.AssertExpectations(t)
+	transactionRepo.AssertNotCalled(t, "Append", mock.Anything)
+}
+
+func TestAuthorize_InsufficientAvailableBalance(t *testing.T) {
+	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	userID := uuid.New()
+	walletID := uuid.New()
+	orderID := uuid.New()
+
+	holdRepo.On("FindByOrderID", orderID).Return(nil, model.ErrWalletHoldNotFound)
+	walletRepo.On("FindByUserID", userID).Return(newWallet(walletID, userID, 100.0), nil)
+	holdRepo.On("ActiveAmount", walletID).Return(70.0, nil)
+
+	svc := newWalletHoldService(walletRepo, transactionRepo, holdRepo, eventDisp)
+
+	_, err := svc.Authorize(userID, orderID, 40.0, time.Hour)
+	assert.ErrorIs(t, err, model.ErrInsufficientFunds)
+	holdRepo.AssertNotCalled(t, "Store", mock.Anything)
+	eventDisp.AssertNotCalled(t, "Dispatch", mock.Anything)
+}
+
+func TestAuthorize_Repeated(t *testing.T) {
+	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	orderID := uuid.New()
+	hold := newActiveHold(uuid.New(), uuid.New(), orderID, 40.0)
+	holdRepo.On("FindByOrderID", orderID).Return(hold, nil)
+
+	svc := newWalletHoldService(walletRepo, transactionRepo, holdRepo, eventDisp)
+
+	id, err := svc.Authorize(uuid.New(), orderID, 40.0, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, hold.ID, id)
+	holdRepo.AssertNotCalled(t, "Store", mock.Anything)
+	walletRepo.AssertNotCalled(t, "FindByUserID", mock.Anything)
+}
+
+func TestCapture_Partial(t *testing.T) {
+	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	userID := uuid.New()
+	walletID := uuid.New()
+	orderID := uuid.New()
+	holdID := uuid.New()
+	wallet := newWallet(walletID, userID, 100.0)
+
+	holdRepo.On("Find", holdID).Return(newActiveHold(holdID, walletID, orderID, 40.0), nil)
+	holdRepo.On("Store", mock.MatchedBy(func(h *model.WalletHold) bool {
+		return h.ID == holdID && h.Status == model.HoldCaptured && h.CapturedAmount == 25.0
+	})).Return(nil)
+	walletRepo.On("Find", walletID).Return(wallet, nil)
+	walletRepo.On("FindByUserID", userID).Return(wallet, nil)
+	holdRepo.On("ActiveAmount", walletID).Return(0.0, nil)
+	transactionRepo.On("Balance", walletID).Return(100.0, nil)
+	transactionRepo.On("NextID").Return(uuid.New(), nil)
+	transactionRepo.On("Append", mock.MatchedBy(func(tx *model.WalletTransaction) bool {
+		return tx.Amount == 25.0 && tx.Direction == model.TransactionDebit && *tx.OrderID == orderID
+	})).Return(nil)
+	walletRepo.On("Store", mock.MatchedBy(func(w *model.Wallet) bool {
+		return w.ID == walletID && w.Balance == 75.0
+	})).Return(nil)
+	eventDisp.On("Dispatch", mock.AnythingOfType("model.WalletBalanceChanged")).Return(nil)
+	eventDisp.On("Dispatch", mock.MatchedBy(func(e model.WalletHoldCaptured) bool {
+		return e.HoldID == holdID && e.Amount == 25.0
+	})).Return(nil)
+
+	svc := newWalletHoldService(walletRepo, transactionRepo, holdRepo, eventDisp)
+
+	captured, err := svc.Capture(holdID, 25.0)
+	assert.NoError(t, err)
+	assert.Equal(t, 25.0, captured)
+	holdRepo.AssertExpectations(t)
+	walletRepo.AssertExpectations(t)
+	transactionRepo.AssertExpectations(t)
+	eventDisp.AssertExpectations(t)
+}
+
+func TestCapture_ExceedsHold(t *testing.T) {
+	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	holdID := uuid.New()
+	holdRepo.On("Find", holdID).Return(newActiveHold(holdID, uuid.New(), uuid.New(), 40.0), nil)
+
+	svc := newWalletHoldService(walletRepo, transactionRepo, holdRepo, eventDisp)
+
+	_, err := svc.Capture(holdID, 40.01)
+	assert.ErrorIs(t, err, ErrCaptureExceedsHold)
+	holdRepo.AssertNotCalled(t, "Store", mock.Anything)
+}
+
+func TestCapture_Expired(t *testing.T) {
+	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	holdID := uuid.New()
+	hold := newActiveHold(holdID, uuid.New(), uuid.New(), 40.0)
+	hold.ExpiresAt = time.Now().Add(-time.Minute)
+	holdRepo.On("Find", holdID).Return(hold, nil)
+
+	svc := newWalletHoldService(walletRepo, transactionRepo, holdRepo, eventDisp)
+
+	_, err := svc.Capture(holdID, 0)
+	assert.ErrorIs(t, err, ErrWalletHoldExpired)
+	holdRepo.AssertNotCalled(t, "Store", mock.Anything)
+}
+
+func TestCapture_Repeated(t *testing.T) {
+	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	holdID := uuid.New()
+	hold := newActiveHold(holdID, uuid.New(), uuid.New(), 40.0)
+	hold.Status = model.HoldCaptured
+	hold.CapturedAmount = 30.0
+	holdRepo.On("Find", holdID).Return(hold, nil)
+
+	svc := newWalletHoldService(walletRepo, transactionRepo, holdRepo, eventDisp)
+
+	captured, err := svc.Capture(holdID, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 30.0, captured)
+	transactionRepo.AssertNotCalled(t, "Append", mock.Anything)
+}
+
+func TestVoid_Success(t *testing.T) {
+	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	holdID := uuid.New()
+	holdRepo.On("Find", holdID).Return(newActiveHold(holdID, uuid.New(), uuid.New(), 40.0), nil)
+	holdRepo.On("Store", mock.MatchedBy(func(h *model.WalletHold) bool {
+		return h.ID == holdID && h.Status == model.HoldVoided
+	})).Return(nil)
+	eventDisp.On("Dispatch", mock.MatchedBy(func(e model.WalletHoldReleased) bool {
+		return e.HoldID == holdID && e.Status == model.HoldVoided
+	})).Return(nil)
+
+	svc := newWalletHoldService(walletRepo, transactionRepo, holdRepo, eventDisp)
+
+	err := svc.Void(holdID)
+	assert.NoError(t, err)
+	holdRepo.AssertExpectations(t)
+	eventDisp.AssertExpectations(t)
+}
+
+func TestVoid_Captured(t *testing.T) {
+	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	holdID := uuid.New()
+	hold := newActiveHold(holdID, uuid.New(), uuid.New(), 40.0)
+	hold.Status = model.HoldCaptured
+	holdRepo.On("Find", holdID).Return(hold, nil)
+
+	svc := newWalletHoldService(walletRepo, transactionRepo, holdRepo, eventDisp)
+
+	err := svc.Void(holdID)
+	assert.ErrorIs(t, err, ErrInvalidHoldStatus)
+	holdRepo.AssertNotCalled(t, "Store", mock.Anything)
+}
+
+func TestExpire_NotExpiredYet(t *testing.T) {
+	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	holdID := uuid.New()
+	holdRepo.On("Find", holdID).Return(newActiveHold(holdID, uuid.New(), uuid.New(), 40.0), nil)
+
+	svc := newWalletHoldService(walletRepo, transactionRepo, holdRepo, eventDisp)
+
+	err := svc.Expire(holdID)
+	assert.ErrorIs(t, err, ErrWalletHoldNotExpired)
+	holdRepo.AssertNotCalled(t, "Store", mock.Anything)
+}
+
+func TestExpire_Success(t *testing.T) {
+	walletRepo := new(MockWalletRepository)
+	transactionRepo := new(MockWalletTransactionRepository)
+	holdRepo := new(MockWalletHoldRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	holdID := uuid.New()
+	hold := newActiveHold(holdID, uuid.New(), uuid.New(), 40.0)
+	hold.ExpiresAt = time.Now().Add(-time.Second)
+	holdRepo.On("Find", holdID).Return(hold, nil)
+	holdRepo.On("Store", mock.MatchedBy(func(h *model.WalletHold) bool {
+		return h.ID == holdID && h.Status == model.HoldExpired
+	})).Return(nil)
+	eventDisp.On("Dispatch", mock.MatchedBy(func(e model.WalletHoldReleased) bool {
+		return e.HoldID == holdID && e.Status == model.HoldExpired
+	})).Return(nil)
+
+	svc := newWalletHoldService(walletRepo, transactionRepo, holdRepo, eventDisp)
+
+	err := svc.Expire(holdID)
+	assert.NoError(t, err)
+	holdRepo.AssertExpectations(t)
+	eventDisp.AssertExpectations(t)
+}
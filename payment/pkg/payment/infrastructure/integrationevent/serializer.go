@@ -2,9 +2,11 @@ package integrationevent
 
 import (
 	"encoding/json"
-	"payment/pkg/payment/domain/model"
 
 	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+	"github.com/pkg/errors"
+
+	"payment/pkg/payment/domain/model"
 )
 
 func NewEventSerializer() outbox.EventSerializer[outbox.Event] {
@@ -14,9 +16,131 @@ func NewEventSerializer() outbox.EventSerializer[outbox.Event] {
 type eventSerializer struct{}
 
 func (s eventSerializer) Serialize(event outbox.Event) (string, error) {
-	if e, ok := event.(*model.UserCreated); ok {
-		b, _ := json.Marshal(e)
-		return string(b), nil
+	switch e := event.(type) {
+	case model.WalletCreated:
+		b, err := json.Marshal(WalletCreated{
+			WalletID: e.WalletID.String(),
+			UserID:   e.UserID.String(),
+			Balance:  e.Balance,
+		})
+		return string(b), errors.WithStack(err)
+	case model.WalletBalanceChanged:
+		b, err := json.Marshal(WalletBalanceChanged{
+			WalletID:   e.WalletID.String(),
+			UserID:     e.UserID.String(),
+			OldBalance: e.OldBalance,
+			NewBalance: e.NewBalance,
+		})
+		return string(b), errors.WithStack(err)
+	case model.WalletRemoved:
+		b, err := json.Marshal(WalletRemoved{
+			WalletID: e.WalletID.String(),
+		})
+		return string(b), errors.WithStack(err)
+	case model.WalletHoldAuthorized:
+		b, err := json.Marshal(WalletHoldAuthorized{
+			HoldID:    e.HoldID.String(),
+			WalletID:  e.WalletID.String(),
+			OrderID:   e.OrderID.String(),
+			Amount:    e.Amount,
+			ExpiresAt: e.ExpiresAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
+	case model.WalletHoldCaptured:
+		b, err := json.Marshal(WalletHoldCaptured{
+			HoldID:   e.HoldID.String(),
+			WalletID: e.WalletID.String(),
+			OrderID:  e.OrderID.String(),
+			Amount:   e.Amount,
+		})
+		return string(b), errors.WithStack(err)
+	case model.WalletHoldReleased:
+		b, err := json.Marshal(WalletHoldReleased{
+			HoldID:   e.HoldID.String(),
+			WalletID: e.WalletID.String(),
+			OrderID:  e.OrderID.String(),
+			Status:   int(e.Status),
+		})
+		return string(b), errors.WithStack(err)
+	case model.PaymentCreated:
+		b, err := json.Marshal(PaymentCreated{
+			PaymentID: e.PaymentID.String(),
+			WalletID:  e.WalletID.String(),
+			OrderID:   e.OrderID.String(),
+			Amount:    e.Amount,
+		})
+		return string(b), errors.WithStack(err)
+	case model.PaymentStatusChanged:
+		b, err := json.Marshal(PaymentStatusChanged{
+			PaymentID: e.PaymentID.String(),
+			OrderID:   e.OrderID.String(),
+			Status:    int(e.To),
+		})
+		return string(b), errors.WithStack(err)
+	case model.PaymentRemoved:
+		b, err := json.Marshal(PaymentRemoved{
+			PaymentID: e.PaymentID.String(),
+		})
+		return string(b), errors.WithStack(err)
+	default:
+		return "", errors.Errorf("unknown event %q", event.Type())
 	}
-	return "", nil
+}
+
+type WalletCreated struct {
+	WalletID string  `json:"wallet_id"`
+	UserID   string  `json:"user_id"`
+	Balance  float64 `json:"balance"`
+}
+
+type WalletBalanceChanged struct {
+	WalletID   string  `json:"wallet_id"`
+	UserID     string  `json:"user_id"`
+	OldBalance float64 `json:"old_balance"`
+	NewBalance float64 `json:"new_balance"`
+}
+
+type WalletRemoved struct {
+	WalletID string `json:"wallet_id"`
+}
+
+type WalletHoldAuthorized struct {
+	HoldID    string  `json:"hold_id"`
+	WalletID  string  `json:"wallet_id"`
+	OrderID   string  `json:"order_id"`
+	Amount    float64 `json:"amount"`
+	ExpiresAt int64   `json:"expires_at"`
+}
+
+type WalletHoldCaptured struct {
+	HoldID   string  `json:"hold_id"`
+	WalletID string  `json:"wallet_id"`
+	OrderID  string  `json:"order_id"`
+	Amount   float64 `json:"amount"`
+}
+
+type WalletHoldReleased struct {
+	HoldID   string `json:"hold_id"`
+	WalletID string `json:"wallet_id"`
+	OrderID  string `json:"order_id"`
+	// Status is either voided or expired status of the hold
+	Status int `json:"status"`
+}
+
+type PaymentCreated struct {
+	PaymentID string  `json:"payment_id"`
+	WalletID  string  `json:"wallet_id"`
+	OrderID   string  `json:"order_id"`
+	Amount    float64 `json:"amount"`
+}
+
+type PaymentStatusChanged struct {
+	PaymentID string `json:"payment_id"`
+	OrderID   string `json:"order_id"`
+	// Status is the new status of the payment
+	Status int `json:"status"`
+}
+
+type PaymentRemoved struct {
+	PaymentID string `json:"payment_id"`
 }
@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion10(client mysql.ClientContext) migrator.Migration {
+	return &version10{
+		client: client,
+	}
+}
+
+type version10 struct {
+	client mysql.ClientContext
+}
+
+func (v version10) Version() int64 {
+	return 10
+}
+
+func (v version10) Description() string {
+	return "Add 'refunded_amount' column to 'payment' table"
+}
+
+func (v version10) Up(ctx context.Context) error {
+	// Payments refunded (status 5) before the column are refunded in full
+	_, err := v.client.ExecContext(ctx, `
+		ALTER TABLE payment
+		    ADD COLUMN refunded_amount DECIMAL(15,2) NOT NULL DEFAULT 0 AFTER amount
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = v.client.ExecContext(ctx, `UPDATE payment SET refunded_amount = amount WHERE status = 5`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion11(client mysql.ClientContext) migrator.Migration {
+	return &version11{
+		client: client,
+	}
+}
+
+type version11 struct {
+	client mysql.ClientContext
+}
+
+func (v version11) Version() int64 {
+	return 11
+}
+
+func (v version11) Description() string {
+	return "Add 'captured_amount' column to 'payment' table"
+}
+
+func (v version11) Up(ctx context.Context) error {
+	// Payments succeeded (status 2) or refunded (status 5) before the column are charged in full
+	_, err := v.client.ExecContext(ctx, `
+		ALTER TABLE payment
+		    ADD COLUMN captured_amount DECIMAL(15,2) NOT NULL DEFAULT 0 AFTER amount
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = v.client.ExecContext(ctx, `UPDATE payment SET captured_amount = amount WHERE status IN (2, 5)`)
+	return errors.WithStack(err)
+}
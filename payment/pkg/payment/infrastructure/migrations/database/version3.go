@@ -0,0 +1,36 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion3(client mysql.ClientContext) migrator.Migration {
+	return &version3{
+		client: client,
+	}
+}
+
+type version3 struct {
+	client mysql.ClientContext
+}
+
+func (v version3) Version() int64 {
+	return 3
+}
+
+func (v version3) Description() string {
+	return "Add 'version' column to 'wallet' and 'payment' tables"
+}
+
+func (v version3) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `ALTER TABLE wallet ADD COLUMN version INT NOT NULL DEFAULT 1`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = v.client.ExecContext(ctx, `ALTER TABLE payment ADD COLUMN version INT NOT NULL DEFAULT 1`)
+	return errors.WithStack(err)
+}
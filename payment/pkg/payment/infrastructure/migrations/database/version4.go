@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion4(client mysql.ClientContext) migrator.Migration {
+	return &version4{
+		client: client,
+	}
+}
+
+type version4 struct {
+	client mysql.ClientContext
+}
+
+func (v version4) Version() int64 {
+	return 4
+}
+
+func (v version4) Description() string {
+	return "Add 'user_id' index to 'wallet' table"
+}
+
+func (v version4) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `CREATE INDEX wallet_user_id_idx ON wallet (user_id)`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion5(client mysql.ClientContext) migrator.Migration {
+	return &version5{
+		client: client,
+	}
+}
+
+type version5 struct {
+	client mysql.ClientContext
+}
+
+func (v version5) Version() int64 {
+	return 5
+}
+
+func (v version5) Description() string {
+	return "Create 'wallet_transaction' table with opening entries of existing wallets"
+}
+
+func (v version5) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE wallet_transaction
+		(
+		    transaction_id VARCHAR(64)   NOT NULL,
+		    wallet_id      VARCHAR(64)   NOT NULL,
+		    amount         DECIMAL(15,2) NOT NULL,
+		    direction      INT           NOT NULL,
+		    reason         INT           NOT NULL,
+		    order_id       VARCHAR(64),
+		    created_at     DATETIME      NOT NULL,
+		    PRIMARY KEY (transaction_id),
+		    INDEX wallet_transaction_wallet_id_idx (wallet_id, transaction_id)
+		)
+		    ENGINE = InnoDB
+		    CHARACTER SET = utf8mb4
+		    COLLATE utf8mb4_unicode_ci
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	// Balances of existing wallets are recorded as adjustments (reason 3) credited (direction 1) to the ledger
+	_, err = v.client.ExecContext(ctx, `
+		INSERT INTO wallet_transaction (transaction_id, wallet_id, amount, direction, reason, order_id, created_at)
+		SELECT UUID(), wallet_id, balance, 1, 3, NULL, NOW()
+		FROM wallet
+		WHERE balance > 0
+	`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion6(client mysql.ClientContext) migrator.Migration {
+	return &version6{
+		client: client,
+	}
+}
+
+type version6 struct {
+	client mysql.ClientContext
+}
+
+func (v version6) Version() int64 {
+	return 6
+}
+
+func (v version6) Description() string {
+	return "Add 'order_id' index to 'payment' table"
+}
+
+func (v version6) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `CREATE INDEX payment_order_id_idx ON payment (order_id)`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion7(client mysql.ClientContext) migrator.Migration {
+	return &version7{
+		client: client,
+	}
+}
+
+type version7 struct {
+	client mysql.ClientContext
+}
+
+func (v version7) Version() int64 {
+	return 7
+}
+
+func (v version7) Description() string {
+	return "Create 'wallet_hold' table"
+}
+
+func (v version7) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE wallet_hold
+		(
+		    hold_id         VARCHAR(64)   NOT NULL,
+		    wallet_id       VARCHAR(64)   NOT NULL,
+		    order_id        VARCHAR(64)   NOT NULL,
+		    amount          DECIMAL(15,2) NOT NULL,
+		    captured_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+		    status          INT           NOT NULL,
+		    expires_at      DATETIME      NOT NULL,
+		    created_at      DATETIME      NOT NULL,
+		    updated_at      DATETIME      NOT NULL,
+		    PRIMARY KEY (hold_id),
+		    INDEX wallet_hold_wallet_id_idx (wallet_id, status),
+		    INDEX wallet_hold_order_id_idx (order_id)
+		)
+		    ENGINE = InnoDB
+		    CHARACTER SET = utf8mb4
+		    COLLATE utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,36 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion8(client mysql.ClientContext) migrator.Migration {
+	return &version8{
+		client: client,
+	}
+}
+
+type version8 struct {
+	client mysql.ClientContext
+}
+
+func (v version8) Version() int64 {
+	return 8
+}
+
+func (v version8) Description() string {
+	return "Index 'wallet_transaction' by creation time"
+}
+
+func (v version8) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		ALTER TABLE wallet_transaction
+		    DROP INDEX wallet_transaction_wallet_id_idx,
+		    ADD INDEX wallet_transaction_wallet_id_idx (wallet_id, created_at, transaction_id)
+	`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,36 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion9(client mysql.ClientContext) migrator.Migration {
+	return &version9{
+		client: client,
+	}
+}
+
+type version9 struct {
+	client mysql.ClientContext
+}
+
+func (v version9) Version() int64 {
+	return 9
+}
+
+func (v version9) Description() string {
+	return "Add 'reference_id' column to 'wallet_transaction' table"
+}
+
+func (v version9) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		ALTER TABLE wallet_transaction
+		    ADD COLUMN reference_id VARCHAR(64) NULL AFTER order_id,
+		    ADD UNIQUE INDEX wallet_transaction_reference_id_idx (reference_id)
+	`)
+	return errors.WithStack(err)
+}
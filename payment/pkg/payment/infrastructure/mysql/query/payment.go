@@ -2,12 +2,17 @@ package query
 
 import (
 	"context"
+	"database/sql"
+	"strings"
+	"time"
 
 	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 
 	"payment/pkg/payment/app/data"
 	"payment/pkg/payment/app/query"
+	"payment/pkg/payment/domain/model"
 )
 
 func NewPaymentQueryService(client mysql.ClientContext) query.PaymentQueryService {
@@ -20,6 +25,97 @@ type paymentQueryService struct {
 	client mysql.ClientContext
 }
 
-func (p *paymentQueryService) FindPayment(_ context.Context, _ uuid.UUID) (*data.Payment, error) {
-	return &data.Payment{}, nil
+type paymentRow struct {
+	ID        uuid.UUID           `db:"payment_id"`
+	WalletID  uuid.UUID           `db:"wallet_id"`
+	OrderID   uuid.UUID           `db:"order_id"`
+	Amount    float64             `db:"amount"`
+	Status    int                 `db:"status"`
+	CreatedAt time.Time           `db:"created_at"`
+	UpdatedAt time.Time           `db:"updated_at"`
+	DeletedAt sql.Null[time.Time] `db:"deleted_at"`
+}
+
+const paymentColumns = `payment_id, wallet_id, order_id, amount, status, created_at, updated_at, deleted_at`
+
+func (p *paymentQueryService) FindPayment(ctx context.Context, paymentID uuid.UUID) (*data.Payment, error) {
+	var row paymentRow
+	err := p.client.GetContext(
+		ctx,
+		&row,
+		`SELECT `+paymentColumns+` FROM payment WHERE payment_id = ?`,
+		paymentID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(model.ErrPaymentNotFound)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	payment := toPayment(row)
+	return &payment, nil
+}
+
+func (p *paymentQueryService) ListPayments(ctx context.Context, spec data.ListPaymentsSpec) (*data.PaymentPage, error) {
+	limit := spec.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	limit = min(limit, maxListLimit)
+
+	conditions := []string{"deleted_at IS NULL"}
+	var args []interface{}
+	if spec.Cursor != "" {
+		lastPaymentID, err := decodeCursor(spec.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, "payment_id > ?")
+		args = append(args, lastPaymentID)
+	}
+	if spec.WalletID != nil {
+		conditions = append(conditions, "wallet_id = ?")
+		args = append(args, *spec.WalletID)
+	}
+	if spec.OrderID != nil {
+		conditions = append(conditions, "order_id = ?")
+		args = append(args, *spec.OrderID)
+	}
+
+	// payment_id is UUIDv7 stored as text, so ordering by it keeps payments in creation order
+	sqlQuery := `SELECT ` + paymentColumns + ` FROM payment
+		WHERE ` + strings.Join(conditions, " AND ") + ` ORDER BY payment_id LIMIT ?`
+	args = append(args, limit+1)
+
+	var rows []paymentRow
+	err := p.client.SelectContext(ctx, &rows, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	page := &data.PaymentPage{}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		page.NextCursor = encodeCursor(rows[len(rows)-1].ID)
+	}
+
+	page.Payments = make([]data.Payment, 0, len(rows))
+	for _, row := range rows {
+		page.Payments = append(page.Payments, toPayment(row))
+	}
+	return page, nil
+}
+
+func toPayment(row paymentRow) data.Payment {
+	return data.Payment{
+		ID:        row.ID,
+		WalletID:  row.WalletID,
+		OrderID:   row.OrderID,
+		Amount:    row.Amount,
+		Status:    data.PaymentStatus(row.Status),
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+		DeletedAt: fromSQLNull(row.DeletedAt),
+	}
 }
@@ -2,12 +2,23 @@ package query
 
 import (
 	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/binary"
+	"time"
 
 	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 
 	"payment/pkg/payment/app/data"
 	"payment/pkg/payment/app/query"
+	"payment/pkg/payment/domain/model"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 500
 )
 
 func NewWalletQueryService(client mysql.ClientContext) query.WalletQueryService {
@@ -20,6 +31,145 @@ type walletQueryService struct {
 	client mysql.ClientContext
 }
 
-func (w *walletQueryService) FindWallet(_ context.Context, _ uuid.UUID) (*data.Wallet, error) {
-	return nil, nil
+type walletRow struct {
+	ID               uuid.UUID           `db:"wallet_id"`
+	UserID           uuid.UUID           `db:"user_id"`
+	Balance          float64             `db:"balance"`
+	AvailableBalance float64             `db:"available_balance"`
+	CreatedAt        time.Time           `db:"created_at"`
+	UpdatedAt        time.Time           `db:"updated_at"`
+	DeletedAt        sql.Null[time.Time] `db:"deleted_at"`
+}
+
+// walletColumns take amounts of active holds off the balance, so the query takes model.HoldActive as the first argument
+const walletColumns = `wallet_id, user_id, balance, created_at, updated_at, deleted_at,
+	balance - COALESCE((SELECT SUM(h.amount) FROM wallet_hold h WHERE h.wallet_id = wallet.wallet_id AND h.status = ?), 0) AS available_balance`
+
+type walletTransactionRow struct {
+	ID        uuid.UUID           `db:"transaction_id"`
+	WalletID  uuid.UUID           `db:"wallet_id"`
+	Amount    float64             `db:"amount"`
+	Direction int                 `db:"direction"`
+	Reason    int                 `db:"reason"`
+	OrderID   sql.Null[uuid.UUID] `db:"order_id"`
+	CreatedAt time.Time           `db:"created_at"`
+}
+
+func (w *walletQueryService) FindWallet(ctx context.Context, walletID uuid.UUID) (*data.Wallet, error) {
+	return w.findWallet(ctx, `SELECT `+walletColumns+` FROM wallet WHERE wallet_id = ?`, model.HoldActive, walletID)
+}
+
+func (w *walletQueryService) FindWalletByUser(ctx context.Context, userID uuid.UUID) (*data.Wallet, error) {
+	return w.findWallet(ctx, `SELECT `+walletColumns+` FROM wallet WHERE user_id = ? AND deleted_at IS NULL`, model.HoldActive, userID)
+}
+
+func (w *walletQueryService) findWallet(ctx context.Context, sqlQuery string, args ...interface{}) (*data.Wallet, error) {
+	var row walletRow
+	err := w.client.GetContext(ctx, &row, sqlQuery, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(model.ErrWalletNotFound)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	return &data.Wallet{
+		ID:               row.ID,
+		UserID:           row.UserID,
+		Balance:          row.Balance,
+		AvailableBalance: row.AvailableBalance,
+		CreatedAt:        row.CreatedAt,
+		UpdatedAt:        row.UpdatedAt,
+		DeletedAt:        fromSQLNull(row.DeletedAt),
+	}, nil
+}
+
+func (w *walletQueryService) ListWalletTransactions(ctx context.Context, spec data.ListWalletTransactionsSpec) (*data.WalletTransactionPage, error) {
+	limit := spec.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	limit = min(limit, maxListLimit)
+
+	sqlQuery := `SELECT transaction_id, wallet_id, amount, direction, reason, order_id, created_at
+		FROM wallet_transaction WHERE wallet_id = ?`
+	args := []interface{}{spec.WalletID}
+	if spec.Cursor != "" {
+		lastCreatedAt, lastTransactionID, err := decodeTransactionCursor(spec.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		sqlQuery += ` AND (created_at > ? OR (created_at = ? AND transaction_id > ?))`
+		args = append(args, lastCreatedAt, lastCreatedAt, lastTransactionID)
+	}
+	// Opening entries backfilled by the migration have no UUIDv7 IDs, so the ledger is ordered by created_at first
+	sqlQuery += ` ORDER BY created_at, transaction_id LIMIT ?`
+	args = append(args, limit+1)
+
+	var rows []walletTransactionRow
+	err := w.client.SelectContext(ctx, &rows, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	page := &data.WalletTransactionPage{}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		page.NextCursor = encodeTransactionCursor(rows[len(rows)-1].CreatedAt, rows[len(rows)-1].ID)
+	}
+
+	page.Transactions = make([]data.WalletTransaction, 0, len(rows))
+	for _, row := range rows {
+		transaction := data.WalletTransaction{
+			ID:        row.ID,
+			WalletID:  row.WalletID,
+			Amount:    row.Amount,
+			Direction: data.WalletTransactionDirection(row.Direction),
+			Reason:    data.WalletTransactionReason(row.Reason),
+			OrderID:   fromSQLNull(row.OrderID),
+			CreatedAt: row.CreatedAt,
+		}
+		page.Transactions = append(page.Transactions, transaction)
+	}
+	return page, nil
+}
+
+func encodeCursor(lastID uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString(lastID[:])
+}
+
+func decodeCursor(cursor string) (uuid.UUID, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return uuid.Nil, errors.WithStack(query.ErrInvalidCursor)
+	}
+	id, err := uuid.FromBytes(b)
+	if err != nil {
+		return uuid.Nil, errors.WithStack(query.ErrInvalidCursor)
+	}
+	return id, nil
+}
+
+func encodeTransactionCursor(lastCreatedAt time.Time, lastID uuid.UUID) string {
+	b := binary.BigEndian.AppendUint64(nil, uint64(lastCreatedAt.Unix())) // #nosec G115
+	return base64.RawURLEncoding.EncodeToString(append(b, lastID[:]...))
+}
+
+func decodeTransactionCursor(cursor string) (time.Time, uuid.UUID, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(b) != 8+len(uuid.UUID{}) {
+		return time.Time{}, uuid.Nil, errors.WithStack(query.ErrInvalidCursor)
+	}
+	id, err := uuid.FromBytes(b[8:])
+	if err != nil {
+		return time.Time{}, uuid.Nil, errors.WithStack(query.ErrInvalidCursor)
+	}
+	return time.Unix(int64(binary.BigEndian.Uint64(b[:8])), 0), id, nil // #nosec G115
+}
+
+func fromSQLNull[T any](v sql.Null[T]) *T {
+	if v.Valid {
+		return &v.V
+	}
+	return nil
 }
@@ -29,46 +29,82 @@ func (p *paymentRepository) NextID() (uuid.UUID, error) {
 }
 
 func (p *paymentRepository) Store(payment *model.Payment) error {
-	_, err := p.client.ExecContext(p.ctx,
+	if payment.Version == 0 {
+		_, err := p.client.ExecContext(p.ctx,
+			`INSERT INTO payment (payment_id, wallet_id, order_id, amount, captured_amount, refunded_amount, status, created_at, updated_at, deleted_at, version) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 1)`,
+			payment.ID,
+			payment.WalletID,
+			payment.OrderID,
+			payment.Amount,
+			payment.CapturedAmount,
+			payment.RefundedAmount,
+			payment.Status,
+			payment.CreatedAt,
+			payment.UpdatedAt,
+			toSQLNull(payment.DeletedAt),
+		)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		payment.Version++
+		return nil
+	}
+
+	res, err := p.client.ExecContext(p.ctx,
 		`
-	INSERT INTO payment (payment_id, wallet_id, order_id, amount, status, created_at, updated_at, deleted_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-	ON DUPLICATE KEY UPDATE
-		wallet_id=VALUES(wallet_id),
-		order_id=VALUES(order_id),
-		amount=VALUES(amount),
-		status=VALUES(status),
-		updated_at=VALUES(updated_at),
-		deleted_at=VALUES(deleted_at)
+	UPDATE payment SET wallet_id = ?, order_id = ?, amount = ?, captured_amount = ?, refunded_amount = ?, status = ?, updated_at = ?, deleted_at = ?, version = version + 1
+	WHERE payment_id = ? AND version = ?
 	`,
-		payment.ID,
 		payment.WalletID,
 		payment.OrderID,
 		payment.Amount,
+		payment.CapturedAmount,
+		payment.RefundedAmount,
 		payment.Status,
-		payment.CreatedAt,
 		payment.UpdatedAt,
 		toSQLNull(payment.DeletedAt),
+		payment.ID,
+		payment.Version,
 	)
-	return errors.WithStack(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return checkVersionedUpdate(res, &payment.Version, model.ErrPaymentVersionConflict)
 }
 
 func (p *paymentRepository) Find(id uuid.UUID) (*model.Payment, error) {
+	return p.find(`SELECT `+paymentColumns+` FROM payment WHERE payment_id = ?`, id)
+}
+
+func (p *paymentRepository) FindByOrderID(orderID uuid.UUID) (*model.Payment, error) {
+	return p.find(
+		`SELECT `+paymentColumns+` FROM payment WHERE order_id = ? AND deleted_at IS NULL ORDER BY payment_id DESC LIMIT 1`,
+		orderID,
+	)
+}
+
+const paymentColumns = `payment_id, wallet_id, order_id, amount, captured_amount, refunded_amount, status, created_at, updated_at, deleted_at, version`
+
+func (p *paymentRepository) find(query string, args ...interface{}) (*model.Payment, error) {
 	paymentRow := struct {
 		ID        uuid.UUID           `db:"payment_id"`
 		WalletID  uuid.UUID           `db:"wallet_id"`
 		OrderID   uuid.UUID           `db:"order_id"`
 		Amount    float64             `db:"amount"`
+		Captured  float64             `db:"captured_amount"`
+		Refunded  float64             `db:"refunded_amount"`
 		Status    int                 `db:"status"`
 		CreatedAt time.Time           `db:"created_at"`
 		UpdatedAt time.Time           `db:"updated_at"`
 		DeletedAt sql.Null[time.Time] `db:"deleted_at"`
+		Version   int                 `db:"version"`
 	}{}
 
 	err := p.client.GetContext(
 		p.ctx,
 		&paymentRow,
-		`SELECT payment_id, wallet_id, order_id, amount, status, created_at, updated_at, deleted_at FROM payment WHERE payment_id = ?`,
-		id,
+		query,
+		args...,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -78,14 +114,17 @@ func (p *paymentRepository) Find(id uuid.UUID) (*model.Payment, error) {
 	}
 
 	return &model.Payment{
-		ID:        paymentRow.ID,
-		WalletID:  paymentRow.WalletID,
-		OrderID:   paymentRow.OrderID,
-		Amount:    paymentRow.Amount,
-		Status:    model.PaymentStatus(paymentRow.Status),
-		CreatedAt: paymentRow.CreatedAt,
-		UpdatedAt: paymentRow.UpdatedAt,
-		DeletedAt: fromSQLNull(paymentRow.DeletedAt),
+		ID:             paymentRow.ID,
+		WalletID:       paymentRow.WalletID,
+		OrderID:        paymentRow.OrderID,
+		Amount:         paymentRow.Amount,
+		CapturedAmount: paymentRow.Captured,
+		RefundedAmount: paymentRow.Refunded,
+		Status:         model.PaymentStatus(paymentRow.Status),
+		CreatedAt:      paymentRow.CreatedAt,
+		UpdatedAt:      paymentRow.UpdatedAt,
+		DeletedAt:      fromSQLNull(paymentRow.DeletedAt),
+		Version:        paymentRow.Version,
 	}, nil
 }
 
@@ -29,26 +29,52 @@ func (w *walletRepository) NextID() (uuid.UUID, error) {
 }
 
 func (w *walletRepository) Store(wallet *model.Wallet) error {
-	_, err := w.client.ExecContext(w.ctx,
+	if wallet.Version == 0 {
+		_, err := w.client.ExecContext(w.ctx,
+			`INSERT INTO wallet (wallet_id, user_id, balance, created_at, updated_at, deleted_at, version) VALUES (?, ?, ?, ?, ?, ?, 1)`,
+			wallet.ID,
+			wallet.UserID,
+			wallet.Balance,
+			wallet.CreatedAt,
+			wallet.UpdatedAt,
+			toSQLNull(wallet.DeletedAt),
+		)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		wallet.Version++
+		return nil
+	}
+
+	res, err := w.client.ExecContext(w.ctx,
 		`
-	INSERT INTO wallet (wallet_id, user_id, balance, created_at, updated_at, deleted_at) VALUES (?, ?, ?, ?, ?, ?)
-	ON DUPLICATE KEY UPDATE
-		user_id=VALUES(user_id),
-		balance=VALUES(balance),
-		updated_at=VALUES(updated_at),
-		deleted_at=VALUES(deleted_at)
+	UPDATE wallet SET user_id = ?, balance = ?, updated_at = ?, deleted_at = ?, version = version + 1
+	WHERE wallet_id = ? AND version = ?
 	`,
-		wallet.ID,
 		wallet.UserID,
 		wallet.Balance,
-		wallet.CreatedAt,
 		wallet.UpdatedAt,
 		toSQLNull(wallet.DeletedAt),
+		wallet.ID,
+		wallet.Version,
 	)
-	return errors.WithStack(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return checkVersionedUpdate(res, &wallet.Version, model.ErrWalletVersionConflict)
 }
 
 func (w *walletRepository) Find(id uuid.UUID) (*model.Wallet, error) {
+	return w.find(`SELECT `+walletColumns+` FROM wallet WHERE wallet_id = ?`, id)
+}
+
+func (w *walletRepository) FindByUserID(userID uuid.UUID) (*model.Wallet, error) {
+	return w.find(`SELECT `+walletColumns+` FROM wallet WHERE user_id = ? AND deleted_at IS NULL`, userID)
+}
+
+const walletColumns = `wallet_id, user_id, balance, created_at, updated_at, deleted_at, version`
+
+func (w *walletRepository) find(query string, args ...interface{}) (*model.Wallet, error) {
 	walletRow := struct {
 		ID        uuid.UUID           `db:"wallet_id"`
 		UserID    uuid.UUID           `db:"user_id"`
@@ -56,13 +82,14 @@ func (w *walletRepository) Find(id uuid.UUID) (*model.Wallet, error) {
 		CreatedAt time.Time           `db:"created_at"`
 		UpdatedAt time.Time           `db:"updated_at"`
 		DeletedAt sql.Null[time.Time] `db:"deleted_at"`
+		Version   int                 `db:"version"`
 	}{}
 
 	err := w.client.GetContext(
 		w.ctx,
 		&walletRow,
-		`SELECT wallet_id, user_id, balance, created_at, updated_at, deleted_at FROM wallet WHERE wallet_id = ?`,
-		id,
+		query,
+		args...,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -78,6 +105,7 @@ func (w *walletRepository) Find(id uuid.UUID) (*model.Wallet, error) {
 		CreatedAt: walletRow.CreatedAt,
 		UpdatedAt: walletRow.UpdatedAt,
 		DeletedAt: fromSQLNull(walletRow.DeletedAt),
+		Version:   walletRow.Version,
 	}, nil
 }
 
@@ -91,6 +119,19 @@ func (w *walletRepository) Remove(id uuid.UUID) error {
 	return errors.WithStack(err)
 }
 
+// checkVersionedUpdate increments version after successful compare-and-swap update
+func checkVersionedUpdate(res sql.Result, version *int, errConflict error) error {
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if rows == 0 {
+		return errors.WithStack(errConflict)
+	}
+	*version++
+	return nil
+}
+
 func fromSQLNull[T any](v sql.Null[T]) *T {
 	if v.Valid {
 		return &v.V
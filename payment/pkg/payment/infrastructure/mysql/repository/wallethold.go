@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"payment/pkg/payment/domain/model"
+)
+
+func NewWalletHoldRepository(ctx context.Context, client mysql.ClientContext) model.WalletHoldRepository {
+	return &walletHoldRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type walletHoldRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+const walletHoldColumns = `hold_id, wallet_id, order_id, amount, captured_amount, status, expires_at, created_at, updated_at`
+
+func (w *walletHoldRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (w *walletHoldRepository) Store(hold *model.WalletHold) error {
+	_, err := w.client.ExecContext(w.ctx,
+		`
+	INSERT INTO wallet_hold (`+walletHoldColumns+`)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON DUPLICATE KEY UPDATE
+		captured_amount=VALUES(captured_amount),
+		status=VALUES(status),
+		updated_at=VALUES(updated_at)
+	`,
+		hold.ID,
+		hold.WalletID,
+		hold.OrderID,
+		hold.Amount,
+		hold.CapturedAmount,
+		hold.Status,
+		hold.ExpiresAt,
+		hold.CreatedAt,
+		hold.UpdatedAt,
+	)
+	return errors.WithStack(err)
+}
+
+func (w *walletHoldRepository) Find(id uuid.UUID) (*model.WalletHold, error) {
+	return w.find(`SELECT `+walletHoldColumns+` FROM wallet_hold WHERE hold_id = ?`, id)
+}
+
+func (w *walletHoldRepository) FindByOrderID(orderID uuid.UUID) (*model.WalletHold, error) {
+	return w.find(`SELECT `+walletHoldColumns+` FROM wallet_hold WHERE order_id = ? ORDER BY hold_id DESC LIMIT 1`, orderID)
+}
+
+func (w *walletHoldRepository) ActiveAmount(walletID uuid.UUID) (float64, error) {
+	var amount float64
+	err := w.client.GetContext(
+		w.ctx,
+		&amount,
+		`SELECT COALESCE(SUM(amount), 0) FROM wallet_hold WHERE wallet_id = ? AND status = ?`,
+		walletID,
+		model.HoldActive,
+	)
+	return amount, errors.WithStack(err)
+}
+
+func (w *walletHoldRepository) find(query string, args ...interface{}) (*model.WalletHold, error) {
+	holdRow := struct {
+		ID             uuid.UUID `db:"hold_id"`
+		WalletID       uuid.UUID `db:"wallet_id"`
+		OrderID        uuid.UUID `db:"order_id"`
+		Amount         float64   `db:"amount"`
+		CapturedAmount float64   `db:"captured_amount"`
+		Status         int       `db:"status"`
+		ExpiresAt      time.Time `db:"expires_at"`
+		CreatedAt      time.Time `db:"created_at"`
+		UpdatedAt      time.Time `db:"updated_at"`
+	}{}
+
+	err := w.client.GetContext(w.ctx, &holdRow, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(model.ErrWalletHoldNotFound)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	return &model.WalletHold{
+		ID:             holdRow.ID,
+		WalletID:       holdRow.WalletID,
+		OrderID:        holdRow.OrderID,
+		Amount:         holdRow.Amount,
+		CapturedAmount: holdRow.CapturedAmount,
+		Status:         model.WalletHoldStatus(holdRow.Status),
+		ExpiresAt:      holdRow.ExpiresAt,
+		CreatedAt:      holdRow.CreatedAt,
+		UpdatedAt:      holdRow.UpdatedAt,
+	}, nil
+}
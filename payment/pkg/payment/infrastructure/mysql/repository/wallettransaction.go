@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"payment/pkg/payment/domain/model"
+)
+
+func NewWalletTransactionRepository(ctx context.Context, client mysql.ClientContext) model.WalletTransactionRepository {
+	return &walletTransactionRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type walletTransactionRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+func (w *walletTransactionRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (w *walletTransactionRepository) Append(transaction *model.WalletTransaction) error {
+	_, err := w.client.ExecContext(w.ctx,
+		`
+	INSERT INTO wallet_transaction (transaction_id, wallet_id, amount, direction, reason, order_id, reference_id, created_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		transaction.ID,
+		transaction.WalletID,
+		transaction.Amount,
+		transaction.Direction,
+		transaction.Reason,
+		toSQLNull(transaction.OrderID),
+		toSQLNull(transaction.ReferenceID),
+		transaction.CreatedAt,
+	)
+	return errors.WithStack(err)
+}
+
+func (w *walletTransactionRepository) Balance(walletID uuid.UUID) (float64, error) {
+	var balance float64
+	err := w.client.GetContext(
+		w.ctx,
+		&balance,
+		`SELECT COALESCE(SUM(CASE direction WHEN ? THEN amount ELSE -amount END), 0) FROM wallet_transaction WHERE wallet_id = ?`,
+		model.TransactionCredit,
+		walletID,
+	)
+	return balance, errors.WithStack(err)
+}
+
+func (w *walletTransactionRepository) HasReference(referenceID uuid.UUID) (bool, error) {
+	var count int
+	err := w.client.GetContext(
+		w.ctx,
+		&count,
+		`SELECT COUNT(*) FROM wallet_transaction WHERE reference_id = ?`,
+		referenceID,
+	)
+	return count > 0, errors.WithStack(err)
+}
@@ -22,6 +22,14 @@ func (r *repositoryProvider) WalletRepository(ctx context.Context) model.WalletR
 	return repository.NewWalletRepository(ctx, r.client)
 }
 
+func (r *repositoryProvider) WalletTransactionRepository(ctx context.Context) model.WalletTransactionRepository {
+	return repository.NewWalletTransactionRepository(ctx, r.client)
+}
+
+func (r *repositoryProvider) WalletHoldRepository(ctx context.Context) model.WalletHoldRepository {
+	return repository.NewWalletHoldRepository(ctx, r.client)
+}
+
 func (r *repositoryProvider) PaymentRepository(ctx context.Context) model.PaymentRepository {
 	return repository.NewPaymentRepository(ctx, r.client)
 }
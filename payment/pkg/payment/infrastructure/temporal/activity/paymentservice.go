@@ -4,8 +4,11 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.temporal.io/sdk/temporal"
 
 	"payment/pkg/payment/app/service"
+	"payment/pkg/payment/domain/model"
 )
 
 func NewActivities(
@@ -26,3 +29,83 @@ type Activities struct {
 func (a *Activities) CreateWallet(ctx context.Context, userID uuid.UUID) (uuid.UUID, error) {
 	return a.walletService.CreateWallet(ctx, userID)
 }
+
+// CreatePayment returns ID of the payment of the order
+func (a *Activities) CreatePayment(ctx context.Context, userIDStr, orderIDStr string, amount float64) (string, error) {
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return "", err
+	}
+	orderID, err := uuid.Parse(orderIDStr)
+	if err != nil {
+		return "", err
+	}
+
+	paymentID, err := a.paymentService.CreatePayment(ctx, userID, orderID, amount)
+	if err != nil {
+		return "", err
+	}
+	return paymentID.String(), nil
+}
+
+func (a *Activities) ChargePayment(ctx context.Context, paymentIDStr string) error {
+	paymentID, err := uuid.Parse(paymentIDStr)
+	if err != nil {
+		return err
+	}
+
+	err = a.paymentService.ChargePayment(ctx, paymentID)
+	if errors.Is(err, model.ErrPaymentFailed) {
+		// Failed payment is final, so retrying does not help
+		return temporal.NewNonRetryableApplicationError(err.Error(), "PaymentFailed", err)
+	}
+	return err
+}
+
+// CapturePayment returns the amount captured off the hold of the payment, zero amount captures the whole hold
+func (a *Activities) CapturePayment(ctx context.Context, paymentIDStr, holdIDStr string, amount float64) (float64, error) {
+	paymentID, err := uuid.Parse(paymentIDStr)
+	if err != nil {
+		return 0, err
+	}
+	holdID, err := uuid.Parse(holdIDStr)
+	if err != nil {
+		return 0, err
+	}
+
+	captured, err := a.paymentService.CapturePayment(ctx, paymentID, holdID, amount)
+	if errors.Is(err, model.ErrPaymentFailed) {
+		// Failed payment is final, so retrying does not help
+		return 0, temporal.NewNonRetryableApplicationError(err.Error(), "PaymentFailed", err)
+	}
+	return captured, err
+}
+
+func (a *Activities) CancelPayment(ctx context.Context, paymentIDStr string) error {
+	paymentID, err := uuid.Parse(paymentIDStr)
+	if err != nil {
+		return err
+	}
+	return a.paymentService.CancelPayment(ctx, paymentID)
+}
+
+func (a *Activities) RefundPayment(ctx context.Context, paymentIDStr string) error {
+	paymentID, err := uuid.Parse(paymentIDStr)
+	if err != nil {
+		return err
+	}
+	return a.paymentService.RefundPayment(ctx, paymentID)
+}
+
+// RefundReturn credits amount of the order return off the order payment, retried call with the same return does not refund twice
+func (a *Activities) RefundReturn(ctx context.Context, orderIDStr, returnIDStr string, amount float64) error {
+	orderID, err := uuid.Parse(orderIDStr)
+	if err != nil {
+		return err
+	}
+	returnID, err := uuid.Parse(returnIDStr)
+	if err != nil {
+		return err
+	}
+	return a.paymentService.RefundReturn(ctx, orderID, returnID, amount)
+}
@@ -0,0 +1,82 @@
+package activity
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.temporal.io/sdk/temporal"
+
+	"payment/pkg/payment/app/service"
+	"payment/pkg/payment/domain/model"
+	domainservice "payment/pkg/payment/domain/service"
+)
+
+func NewWalletHoldActivities(walletHoldService service.WalletHoldService) *WalletHoldActivities {
+	return &WalletHoldActivities{
+		walletHoldService: walletHoldService,
+	}
+}
+
+type WalletHoldActivities struct {
+	walletHoldService service.WalletHoldService
+}
+
+// AuthorizeWallet returns ID of the hold of the order, zero ttl holds for the default time
+func (a *WalletHoldActivities) AuthorizeWallet(ctx context.Context, userIDStr, orderIDStr string, amount float64, ttl time.Duration) (string, error) {
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return "", err
+	}
+	orderID, err := uuid.Parse(orderIDStr)
+	if err != nil {
+		return "", err
+	}
+
+	hold, err := a.walletHoldService.AuthorizeWallet(ctx, userID, orderID, amount, ttl)
+	if errors.Is(err, model.ErrInsufficientFunds) || errors.Is(err, model.ErrWalletNotFound) {
+		return "", temporal.NewNonRetryableApplicationError(err.Error(), "AuthorizationFailed", err)
+	}
+	if err != nil {
+		return "", err
+	}
+	return hold.ID.String(), nil
+}
+
+// CaptureHold returns the captured amount, zero amount captures the whole hold
+func (a *WalletHoldActivities) CaptureHold(ctx context.Context, holdIDStr string, amount float64) (float64, error) {
+	holdID, err := uuid.Parse(holdIDStr)
+	if err != nil {
+		return 0, err
+	}
+
+	hold, err := a.walletHoldService.CaptureHold(ctx, holdID, amount)
+	switch {
+	case errors.Is(err, domainservice.ErrWalletHoldExpired),
+		errors.Is(err, domainservice.ErrInvalidHoldStatus),
+		errors.Is(err, domainservice.ErrCaptureExceedsHold),
+		errors.Is(err, model.ErrInsufficientFunds):
+		// Hold can not be captured any more, so retrying does not help
+		return 0, temporal.NewNonRetryableApplicationError(err.Error(), "CaptureFailed", err)
+	case err != nil:
+		return 0, err
+	}
+	return hold.CapturedAmount, nil
+}
+
+func (a *WalletHoldActivities) VoidHold(ctx context.Context, holdIDStr string) error {
+	holdID, err := uuid.Parse(holdIDStr)
+	if err != nil {
+		return err
+	}
+	return a.walletHoldService.VoidHold(ctx, holdID)
+}
+
+func (a *WalletHoldActivities) ExpireWalletHold(ctx context.Context, holdIDStr string) error {
+	holdID, err := uuid.Parse(holdIDStr)
+	if err != nil {
+		return err
+	}
+	return a.walletHoldService.ExpireHold(ctx, holdID)
+}
@@ -24,12 +24,42 @@ func (a *WalletServiceActivities) CreateWallet(ctx context.Context, userID uuid.
 	return a.walletService.CreateWallet(ctx, userID)
 }
 
+// ChargeWallet keeps arguments of the activity called by sagas started before CreateOrderSaga was reworked,
+// the charge is not linked to an order
 func (a *WalletServiceActivities) ChargeWallet(ctx context.Context, userIDStr string, amount float64) error {
-	uid, _ := uuid.Parse(userIDStr)
-	return a.walletService.UpdateWalletBalance(ctx, uid, -amount)
+	uid, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return err
+	}
+	return a.walletService.Debit(ctx, uid, uuid.Nil, amount)
+}
+
+// ChargeOrderWallet debits amount of the order from the wallet of the user
+func (a *WalletServiceActivities) ChargeOrderWallet(ctx context.Context, userIDStr, orderIDStr string, amount float64) error {
+	uid, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return err
+	}
+	orderID, err := uuid.Parse(orderIDStr)
+	if err != nil {
+		return err
+	}
+	return a.walletService.Debit(ctx, uid, orderID, amount)
 }
 
-func (a *WalletServiceActivities) RefundWallet(ctx context.Context, userIDStr string, amount float64) error {
-	uid, _ := uuid.Parse(userIDStr)
-	return a.walletService.UpdateWalletBalance(ctx, uid, amount)
+// RefundWallet credits amount of the order return, retried call with the same return does not refund twice
+func (a *WalletServiceActivities) RefundWallet(ctx context.Context, userIDStr, orderIDStr, returnIDStr string, amount float64) error {
+	uid, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return err
+	}
+	orderID, err := uuid.Parse(orderIDStr)
+	if err != nil {
+		return err
+	}
+	returnID, err := uuid.Parse(returnIDStr)
+	if err != nil {
+		return err
+	}
+	return a.walletService.RefundReturn(ctx, uid, orderID, returnID, amount)
 }
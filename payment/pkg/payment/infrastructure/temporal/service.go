@@ -3,7 +3,11 @@ package temporal
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.temporal.io/api/serviceerror"
 	"go.temporal.io/sdk/client"
 
 	"payment/pkg/payment/domain/model"
@@ -14,6 +18,8 @@ const TaskQueue = "payment_task_queue"
 
 type WorkflowService interface {
 	RunCreateWalletWorkflow(ctx context.Context, id string, event model.UserCreated) error
+	// ScheduleHoldExpiry starts ExpireWalletHoldWorkflow unless it is running for the hold already
+	ScheduleHoldExpiry(ctx context.Context, holdID uuid.UUID, expiresAt time.Time) error
 }
 
 func NewWorkflowService(temporalClient client.Client) WorkflowService {
@@ -38,3 +44,19 @@ func (s *workflowService) RunCreateWalletWorkflow(ctx context.Context, id string
 	)
 	return err
 }
+
+func (s *workflowService) ScheduleHoldExpiry(ctx context.Context, holdID uuid.UUID, expiresAt time.Time) error {
+	_, err := s.temporalClient.ExecuteWorkflow(
+		ctx,
+		client.StartWorkflowOptions{
+			ID:        workflows.ExpireWalletHoldWorkflowID(holdID.String()),
+			TaskQueue: TaskQueue,
+		},
+		workflows.ExpireWalletHoldWorkflow, holdID.String(), expiresAt,
+	)
+	var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+	if errors.As(err, &alreadyStarted) {
+		return nil
+	}
+	return errors.WithStack(err)
+}
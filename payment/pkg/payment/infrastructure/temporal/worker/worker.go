@@ -18,6 +18,8 @@ func InterruptChannel() <-chan interface{} {
 func NewWorker(
 	temporalClient client.Client,
 	walletService service.WalletService,
+	paymentService service.PaymentService,
+	walletHoldService service.WalletHoldService,
 ) worker.Worker {
 	w := worker.New(temporalClient, temporal.TaskQueue, worker.Options{})
 
@@ -26,8 +28,24 @@ func NewWorker(
 	// Explicitly register activities with string names
 	w.RegisterActivityWithOptions(acts.CreateWallet, activity.RegisterOptions{Name: "CreateWallet"})
 	w.RegisterActivityWithOptions(acts.ChargeWallet, activity.RegisterOptions{Name: "ChargeWallet"})
+	w.RegisterActivityWithOptions(acts.ChargeOrderWallet, activity.RegisterOptions{Name: "ChargeOrderWallet"})
 	w.RegisterActivityWithOptions(acts.RefundWallet, activity.RegisterOptions{Name: "RefundWallet"})
 
+	paymentActs := appactivity.NewActivities(paymentService, walletService)
+	w.RegisterActivityWithOptions(paymentActs.CreatePayment, activity.RegisterOptions{Name: "CreatePayment"})
+	w.RegisterActivityWithOptions(paymentActs.ChargePayment, activity.RegisterOptions{Name: "ChargePayment"})
+	w.RegisterActivityWithOptions(paymentActs.CapturePayment, activity.RegisterOptions{Name: "CapturePayment"})
+	w.RegisterActivityWithOptions(paymentActs.CancelPayment, activity.RegisterOptions{Name: "CancelPayment"})
+	w.RegisterActivityWithOptions(paymentActs.RefundPayment, activity.RegisterOptions{Name: "RefundPayment"})
+	w.RegisterActivityWithOptions(paymentActs.RefundReturn, activity.RegisterOptions{Name: "RefundReturn"})
+
+	holdActs := appactivity.NewWalletHoldActivities(walletHoldService)
+	w.RegisterActivityWithOptions(holdActs.AuthorizeWallet, activity.RegisterOptions{Name: "AuthorizeWallet"})
+	w.RegisterActivityWithOptions(holdActs.CaptureHold, activity.RegisterOptions{Name: "CaptureHold"})
+	w.RegisterActivityWithOptions(holdActs.VoidHold, activity.RegisterOptions{Name: "VoidHold"})
+	w.RegisterActivityWithOptions(holdActs.ExpireWalletHold, activity.RegisterOptions{Name: "ExpireWalletHold"})
+
 	w.RegisterWorkflow(workflows.CreateWalletWorkflow)
+	w.RegisterWorkflow(workflows.ExpireWalletHoldWorkflow)
 	return w
 }
@@ -0,0 +1,33 @@
+package workflows
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+func ExpireWalletHoldWorkflowID(holdID string) string {
+	return "wallet-hold-expiry-" + holdID
+}
+
+// ExpireWalletHoldWorkflow releases the hold once it expires, captured or voided hold is not changed
+func ExpireWalletHoldWorkflow(ctx workflow.Context, holdID string, expiresAt time.Time) error {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		TaskQueue:           "payment_task_queue",
+		StartToCloseTimeout: time.Minute,
+		// Worker clock may lag behind the timer, so the hold which is not expired yet is retried
+		RetryPolicy: &temporal.RetryPolicy{
+			InitialInterval: time.Second,
+		},
+	})
+
+	if d := expiresAt.Sub(workflow.Now(ctx)); d > 0 {
+		if err := workflow.Sleep(ctx, d); err != nil {
+			return err
+		}
+	}
+
+	// CALL BY EXPLICIT STRING NAME "ExpireWalletHold"
+	return workflow.ExecuteActivity(ctx, "ExpireWalletHold", holdID).Get(ctx, nil)
+}
@@ -0,0 +1,103 @@
+package transport
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"payment/pkg/payment/app/query"
+	"payment/pkg/payment/domain/model"
+	"payment/pkg/payment/domain/service"
+)
+
+type errorSet map[error]struct{}
+
+func newErrorSet(errs ...error) errorSet {
+	s := make(errorSet)
+	for _, err := range errs {
+		s[err] = struct{}{}
+	}
+	return s
+}
+
+func (s errorSet) Has(err error) bool {
+	_, ok := s[err]
+	return ok
+}
+
+var badRequestErrorCodes = newErrorSet(
+	service.ErrInvalidWalletBalance,
+	service.ErrInvalidAmount,
+	service.ErrCaptureExceedsHold,
+	query.ErrInvalidCursor,
+)
+
+var notFoundErrorCodes = newErrorSet(
+	model.ErrWalletNotFound,
+	model.ErrPaymentNotFound,
+	model.ErrWalletHoldNotFound,
+)
+
+var failedPreconditionErrorCodes = newErrorSet(
+	model.ErrInsufficientFunds,
+	service.ErrInvalidPaymentStatus,
+	service.ErrInvalidHoldStatus,
+	service.ErrWalletHoldExpired,
+	service.ErrWalletHoldNotExpired,
+)
+
+var abortedErrorCodes = newErrorSet(
+	model.ErrWalletVersionConflict,
+	model.ErrPaymentVersionConflict,
+)
+
+var internalErrorCodes = newErrorSet(
+	service.ErrWalletLedgerMismatch,
+)
+
+// NewGRPCErrorMiddleware converts domain errors returned by handlers to GRPC status errors
+func NewGRPCErrorMiddleware() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		// if already a GRPC error return unchanged
+		if _, ok := status.FromError(err); ok {
+			return resp, err
+		}
+		return resp, status.Error(getGRPCCode(err), err.Error())
+	}
+}
+
+// getGRPCCode returns GRPC code by the cause of the error
+func getGRPCCode(err error) codes.Code {
+	cause := errors.Cause(err)
+
+	switch {
+	case cause == nil:
+		return codes.OK
+	case badRequestErrorCodes.Has(cause):
+		return codes.InvalidArgument
+	case notFoundErrorCodes.Has(cause):
+		return codes.NotFound
+	case failedPreconditionErrorCodes.Has(cause):
+		return codes.FailedPrecondition
+	case abortedErrorCodes.Has(cause):
+		return codes.Aborted
+	case internalErrorCodes.Has(cause):
+		return codes.Internal
+	}
+
+	switch cause {
+	case context.DeadlineExceeded:
+		return codes.DeadlineExceeded
+	case context.Canceled:
+		return codes.Canceled
+	default:
+		return codes.Unknown
+	}
+}
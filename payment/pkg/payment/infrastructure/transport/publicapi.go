@@ -1,7 +1,16 @@
 package transport
 
 import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
 	"payment/api/server/paymentinternal"
+	"payment/pkg/payment/app/data"
 	"payment/pkg/payment/app/query"
 	"payment/pkg/payment/app/service"
 )
@@ -11,12 +20,14 @@ func NewPaymentInternalAPI(
 	paymentService service.PaymentService,
 	walletQueryService query.WalletQueryService,
 	walletService service.WalletService,
+	walletHoldService service.WalletHoldService,
 ) paymentinternal.PaymentInternalAPIServer {
 	return &paymentInternalAPI{
 		paymentQueryService: paymentQueryService,
 		paymentService:      paymentService,
 		walletQueryService:  walletQueryService,
 		walletService:       walletService,
+		walletHoldService:   walletHoldService,
 	}
 }
 
@@ -25,6 +36,241 @@ type paymentInternalAPI struct {
 	paymentService      service.PaymentService
 	walletQueryService  query.WalletQueryService
 	walletService       service.WalletService
+	walletHoldService   service.WalletHoldService
 
 	paymentinternal.UnsafePaymentInternalAPIServer
 }
+
+func (p *paymentInternalAPI) FindWallet(ctx context.Context, request *paymentinternal.FindWalletRequest) (*paymentinternal.WalletResponse, error) {
+	walletID, err := uuid.Parse(request.WalletID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.WalletID)
+	}
+
+	wallet, err := p.walletQueryService.FindWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	return toWalletResponse(wallet), nil
+}
+
+func (p *paymentInternalAPI) FindWalletByUser(ctx context.Context, request *paymentinternal.FindWalletByUserRequest) (*paymentinternal.WalletResponse, error) {
+	userID, err := uuid.Parse(request.UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.UserID)
+	}
+
+	wallet, err := p.walletQueryService.FindWalletByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toWalletResponse(wallet), nil
+}
+
+func (p *paymentInternalAPI) TopUpWallet(ctx context.Context, request *paymentinternal.TopUpWalletRequest) (*emptypb.Empty, error) {
+	walletID, err := uuid.Parse(request.WalletID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.WalletID)
+	}
+
+	err = p.walletService.TopUpWallet(ctx, walletID, request.Amount)
+	if err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (p *paymentInternalAPI) AuthorizeWallet(ctx context.Context, request *paymentinternal.AuthorizeWalletRequest) (*paymentinternal.WalletHoldResponse, error) {
+	userID, err := uuid.Parse(request.UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.UserID)
+	}
+	orderID, err := uuid.Parse(request.OrderID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.OrderID)
+	}
+
+	hold, err := p.walletHoldService.AuthorizeWallet(ctx, userID, orderID, request.Amount, time.Duration(request.TtlSeconds)*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	return toWalletHoldResponse(hold), nil
+}
+
+func (p *paymentInternalAPI) CaptureHold(ctx context.Context, request *paymentinternal.CaptureHoldRequest) (*paymentinternal.WalletHoldResponse, error) {
+	holdID, err := uuid.Parse(request.HoldID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.HoldID)
+	}
+
+	hold, err := p.walletHoldService.CaptureHold(ctx, holdID, request.GetAmount())
+	if err != nil {
+		return nil, err
+	}
+	return toWalletHoldResponse(hold), nil
+}
+
+func (p *paymentInternalAPI) VoidHold(ctx context.Context, request *paymentinternal.VoidHoldRequest) (*emptypb.Empty, error) {
+	holdID, err := uuid.Parse(request.HoldID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.HoldID)
+	}
+
+	err = p.walletHoldService.VoidHold(ctx, holdID)
+	if err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func toWalletHoldResponse(hold data.WalletHold) *paymentinternal.WalletHoldResponse {
+	return &paymentinternal.WalletHoldResponse{
+		HoldID:         hold.ID.String(),
+		WalletID:       hold.WalletID.String(),
+		OrderID:        hold.OrderID.String(),
+		Amount:         hold.Amount,
+		CapturedAmount: hold.CapturedAmount,
+		Status:         paymentinternal.WalletHoldStatus(hold.Status), // #nosec G115
+		ExpiresAt:      hold.ExpiresAt.Format(time.RFC3339),
+		CreatedAt:      hold.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      hold.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func (p *paymentInternalAPI) ListWalletTransactions(
+	ctx context.Context,
+	request *paymentinternal.ListWalletTransactionsRequest,
+) (*paymentinternal.ListWalletTransactionsResponse, error) {
+	walletID, err := uuid.Parse(request.WalletID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.WalletID)
+	}
+
+	page, err := p.walletQueryService.ListWalletTransactions(ctx, data.ListWalletTransactionsSpec{
+		WalletID: walletID,
+		Limit:    int(request.Limit),
+		Cursor:   request.Cursor,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &paymentinternal.ListWalletTransactionsResponse{
+		Transactions: make([]*paymentinternal.WalletTransaction, 0, len(page.Transactions)),
+		NextCursor:   page.NextCursor,
+	}
+	for _, transaction := range page.Transactions {
+		response.Transactions = append(response.Transactions, toWalletTransaction(transaction))
+	}
+	return response, nil
+}
+
+func toWalletTransaction(transaction data.WalletTransaction) *paymentinternal.WalletTransaction {
+	response := &paymentinternal.WalletTransaction{
+		TransactionID: transaction.ID.String(),
+		WalletID:      transaction.WalletID.String(),
+		Amount:        transaction.Amount,
+		Direction:     paymentinternal.WalletTransactionDirection(transaction.Direction), // #nosec G115
+		Reason:        paymentinternal.WalletTransactionReason(transaction.Reason),       // #nosec G115
+		CreatedAt:     transaction.CreatedAt.Format(time.RFC3339),
+	}
+	if transaction.OrderID != nil {
+		orderID := transaction.OrderID.String()
+		response.OrderID = &orderID
+	}
+	return response
+}
+
+func (p *paymentInternalAPI) ListPayments(ctx context.Context, request *paymentinternal.ListPaymentsRequest) (*paymentinternal.ListPaymentsResponse, error) {
+	spec := data.ListPaymentsSpec{
+		Limit:  int(request.Limit),
+		Cursor: request.Cursor,
+	}
+	if request.WalletID != nil {
+		walletID, err := uuid.Parse(*request.WalletID)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", *request.WalletID)
+		}
+		spec.WalletID = &walletID
+	}
+	if request.OrderID != nil {
+		orderID, err := uuid.Parse(*request.OrderID)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", *request.OrderID)
+		}
+		spec.OrderID = &orderID
+	}
+
+	page, err := p.paymentQueryService.ListPayments(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &paymentinternal.ListPaymentsResponse{
+		Payments:   make([]*paymentinternal.PaymentResponse, 0, len(page.Payments)),
+		NextCursor: page.NextCursor,
+	}
+	for _, payment := range page.Payments {
+		response.Payments = append(response.Payments, toPaymentResponse(&payment))
+	}
+	return response, nil
+}
+
+func (p *paymentInternalAPI) FindPayment(ctx context.Context, request *paymentinternal.FindPaymentRequest) (*paymentinternal.PaymentResponse, error) {
+	paymentID, err := uuid.Parse(request.PaymentID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.PaymentID)
+	}
+
+	payment, err := p.paymentQueryService.FindPayment(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	return toPaymentResponse(payment), nil
+}
+
+func (p *paymentInternalAPI) RefundPayment(ctx context.Context, request *paymentinternal.RefundPaymentRequest) (*emptypb.Empty, error) {
+	paymentID, err := uuid.Parse(request.PaymentID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.PaymentID)
+	}
+
+	err = p.paymentService.RefundPayment(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func toWalletResponse(wallet *data.Wallet) *paymentinternal.WalletResponse {
+	response := &paymentinternal.WalletResponse{
+		WalletID:         wallet.ID.String(),
+		UserID:           wallet.UserID.String(),
+		Balance:          wallet.Balance,
+		AvailableBalance: wallet.AvailableBalance,
+		CreatedAt:        wallet.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:        wallet.UpdatedAt.Format(time.RFC3339),
+	}
+	if wallet.DeletedAt != nil {
+		deletedAtStr := wallet.DeletedAt.Format(time.RFC3339)
+		response.DeletedAt = &deletedAtStr
+	}
+	return response
+}
+
+func toPaymentResponse(payment *data.Payment) *paymentinternal.PaymentResponse {
+	response := &paymentinternal.PaymentResponse{
+		PaymentID: payment.ID.String(),
+		WalletID:  payment.WalletID.String(),
+		OrderID:   payment.OrderID.String(),
+		Amount:    payment.Amount,
+		Status:    paymentinternal.PaymentStatus(payment.Status), // #nosec G115
+		CreatedAt: payment.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: payment.UpdatedAt.Format(time.RFC3339),
+	}
+	if payment.DeletedAt != nil {
+		deletedAtStr := payment.DeletedAt.Format(time.RFC3339)
+		response.DeletedAt = &deletedAtStr
+	}
+	return response
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/logging"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/amqp"
+)
+
+func newAMQPConnection(config *config, logger logging.Logger) amqp.Connection {
+	return amqp.NewAMQPConnection(appID, &amqp.ConnectionConfig{
+		User:           config.AMQPUser,
+		Password:       config.AMQPPassword,
+		Host:           config.AMQPHost,
+		ConnectTimeout: config.AMQPConnectTimeout,
+	}, logger)
+}
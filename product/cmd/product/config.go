@@ -31,6 +31,15 @@ type config struct {
 	TestGRPCAddress string `envconfig:"test_grpc_address" default:"test:8081"`
 
 	TemporalHost string `envconfig:"temporal_host" default:"temporal:7233"`
+
+	// ReservationTTL is time an order has to confirm reserved stock before the sweep releases it
+	ReservationTTL           time.Duration `envconfig:"reservation_ttl" default:"30m"`
+	ReservationSweepInterval time.Duration `envconfig:"reservation_sweep_interval" default:"1m"`
+
+	AMQPHost           string        `envconfig:"amqp_host"`
+	AMQPUser           string        `envconfig:"amqp_user"`
+	AMQPPassword       string        `envconfig:"amqp_password"`
+	AMQPConnectTimeout time.Duration `envconfig:"amqp_connect_timeout"`
 }
 
 func (c *config) buildDSN() string {
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -36,8 +37,9 @@ func (m *multiCloser) Close() error {
 }
 
 func newConnectionsContainer(
+	ctx context.Context,
 	config *config,
-	_ *log.Logger,
+	logger *log.Logger,
 	multiCloser *multiCloser,
 ) (container *connectionsContainer, err error) {
 	containerBuilder := func() error {
@@ -47,13 +49,15 @@ func newConnectionsContainer(
 		if err != nil {
 			return fmt.Errorf("failed to init DB for migrations: %w", err)
 		}
-		defer db.Close()
+		multiCloser.Add(db)
 
 		if err = applyMigrations(db.DB, pathToMigrations); err != nil {
 			return fmt.Errorf("migration failed: %w", err)
 		}
+		if err = applyOutboxMigrations(ctx, db, newLibLogger(logger)); err != nil {
+			return fmt.Errorf("outbox migration failed: %w", err)
+		}
 		log.Infof("Migrations applied successfully")
-		multiCloser.Add(db)
 		container.db = db
 
 		testConnection, err := grpc.NewClient(
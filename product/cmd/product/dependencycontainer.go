@@ -1,16 +1,45 @@
 package main
 
-import "github.com/jmoiron/sqlx"
+import (
+	libmysql "gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/outbox"
+	"github.com/jmoiron/sqlx"
+
+	appquery "product/pkg/product/app/query"
+	appservice "product/pkg/product/app/service"
+	"product/pkg/product/infrastructure/integrationevent"
+	inframysql "product/pkg/product/infrastructure/mysql"
+	infraquery "product/pkg/product/infrastructure/mysql/query"
+)
 
 func newDependencyContainer(
-	_ *config,
+	config *config,
 	connContainer *connectionsContainer,
 ) (*dependencyContainer, error) {
 	return &dependencyContainer{
-		db: connContainer.db,
+		db:                  connContainer.db,
+		productService:      newProductService(config, connContainer.db),
+		productQueryService: infraquery.NewProductQueryService(libmysql.NewTransactionalClientFromSQLx(connContainer.db)),
 	}, nil
 }
 
 type dependencyContainer struct {
-	db *sqlx.DB
+	db                  *sqlx.DB
+	productService      *appservice.ProductService
+	productQueryService appquery.ProductQueryService
+}
+
+func newProductService(config *config, db *sqlx.DB) *appservice.ProductService {
+	databaseConnectionPool := libmysql.NewConnectionPool(libmysql.NewTransactionalClientFromSQLx(db))
+
+	libUoW := libmysql.NewUnitOfWork(databaseConnectionPool, inframysql.NewRepositoryProvider)
+	libLUow := libmysql.NewLockableUnitOfWork(libUoW, libmysql.NewLocker(databaseConnectionPool))
+	eventDispatcher := outbox.NewEventDispatcher(appID, integrationevent.TransportName, integrationevent.NewEventSerializer(), libUoW)
+
+	return appservice.NewProductService(
+		inframysql.NewUnitOfWork(libUoW),
+		inframysql.NewLockableUnitOfWork(libLUow),
+		eventDispatcher,
+		config.ReservationTTL,
+	)
 }
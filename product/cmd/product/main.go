@@ -56,7 +56,8 @@ func runApp(
 		Commands: []*cli.Command{
 			service(config, logger, closer),
 			migrate(config, logger),
-			runWorker(config),
+			runWorker(config, logger),
+			messageHandler(config, logger, closer),
 		},
 	}
 
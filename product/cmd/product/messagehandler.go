@@ -0,0 +1,57 @@
+package main
+
+import (
+	libio "gitea.xscloud.ru/xscloud/golib/pkg/common/io"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/amqp"
+	libmysql "gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/outbox"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+
+	"product/pkg/product/infrastructure/integrationevent"
+)
+
+// messageHandler publishes events stored in the outbox, the service does not consume events of other services
+func messageHandler(
+	config *config,
+	logger *log.Logger,
+	closer *multiCloser,
+) *cli.Command {
+	return &cli.Command{
+		Name:  "message-handler",
+		Usage: "Publishes domain events to AMQP",
+		Action: func(c *cli.Context) error {
+			connContainer, err := newConnectionsContainer(c.Context, config, logger, closer)
+			if err != nil {
+				return errors.Wrap(err, "failed to init connections")
+			}
+			databaseConnectionPool := libmysql.NewConnectionPool(libmysql.NewTransactionalClientFromSQLx(connContainer.db))
+			libLogger := newLibLogger(logger)
+
+			amqpConnection := newAMQPConnection(config, libLogger)
+			amqpEventProducer := amqpConnection.Producer(
+				&amqp.ExchangeConfig{
+					Name:    integrationevent.ExchangeName,
+					Kind:    integrationevent.ExchangeKind,
+					Durable: true,
+				},
+				nil,
+				nil,
+			)
+			err = amqpConnection.Start()
+			if err != nil {
+				return err
+			}
+			closer.Add(libio.CloserFunc(amqpConnection.Stop))
+
+			outboxEventHandler := outbox.NewEventHandler(outbox.EventHandlerConfig{
+				TransportName:  integrationevent.TransportName,
+				Transport:      integrationevent.NewOutboxTransport(libLogger, amqpEventProducer),
+				ConnectionPool: databaseConnectionPool,
+				Logger:         libLogger,
+			})
+			return outboxEventHandler.Start(c.Context)
+		},
+	}
+}
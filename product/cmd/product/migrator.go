@@ -1,18 +1,25 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/logging"
+	libmysql "gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	outboxmigrations "gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/outbox/migrations"
 	_ "github.com/go-sql-driver/mysql"
 	migrator "github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/mysql"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jmoiron/sqlx"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
+
+	"product/pkg/product/infrastructure/integrationevent"
 )
 
 const pathToMigrations = "data/mysql/migrations"
@@ -24,7 +31,7 @@ func migrate(
 	return &cli.Command{
 		Name:  "migrate",
 		Usage: "Apply database migrations",
-		Action: func(_ *cli.Context) error {
+		Action: func(c *cli.Context) error {
 			db, err := initMySQL(config)
 			if err != nil {
 				return err
@@ -33,6 +40,9 @@ func migrate(
 			if err := applyMigrations(db.DB, pathToMigrations); err != nil {
 				return fmt.Errorf("migration failed: %w", err)
 			}
+			if err := applyOutboxMigrations(c.Context, db, newLibLogger(logger)); err != nil {
+				return fmt.Errorf("outbox migration failed: %w", err)
+			}
 
 			logger.Infof("Migrations applied successfully")
 			return nil
@@ -72,3 +82,16 @@ func applyMigrations(db *sql.DB, migrationsDir string) error {
 
 	return nil
 }
+
+func applyOutboxMigrations(ctx context.Context, db *sqlx.DB, logger logging.Logger) (err error) {
+	pool := libmysql.NewConnectionPool(libmysql.NewTransactionalClientFromSQLx(db))
+	migrator, release, err := outboxmigrations.NewOutboxMigrator(ctx, pool, logger, integrationevent.TransportName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, release())
+	}()
+
+	return migrator.Migrate()
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	appservice "product/pkg/product/app/service"
+)
+
+// runReservationSweep releases expired stock reservations until ctx is done
+func runReservationSweep(ctx context.Context, svc *appservice.ProductService, interval time.Duration, logger *log.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			released, err := svc.ReleaseExpiredReservations(ctx)
+			if err != nil {
+				logger.WithError(err).Error("failed to release expired stock reservations")
+			}
+			if released > 0 {
+				logger.Infof("released %d expired stock reservations", released)
+			}
+		}
+	}
+}
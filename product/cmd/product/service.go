@@ -25,7 +25,7 @@ func service(
 		Name:  "service",
 		Usage: "Runs the gRPC service",
 		Action: func(c *cli.Context) error {
-			connContainer, err := newConnectionsContainer(config, logger, closer)
+			connContainer, err := newConnectionsContainer(c.Context, config, logger, closer)
 			if err != nil {
 				return errors.Wrap(err, "failed to init connections")
 			}
@@ -43,11 +43,14 @@ func startGRPCServer(
 	ctx context.Context,
 	config *config,
 	logger *log.Logger,
-	_ *dependencyContainer,
+	container *dependencyContainer,
 ) error {
 	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(makeGrpcUnaryInterceptor(logger)))
 
-	api.RegisterProductInternalServiceServer(grpcServer, transport2.NewInternalAPI())
+	api.RegisterProductInternalServiceServer(grpcServer, transport2.NewInternalAPI(
+		container.productService,
+		container.productQueryService,
+	))
 
 	listener, err := net.Listen("tcp", config.ServeGRPCAddress)
 	if err != nil {
@@ -1,30 +1,25 @@
 package main
 
 import (
-	"log"
-
+	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
 	"go.temporal.io/sdk/activity"
 	"go.temporal.io/sdk/client"
 	"go.temporal.io/sdk/worker"
 
-	appservice "product/pkg/product/app/service"
-	inframysql "product/pkg/product/infrastructure/mysql/repository"
 	infratemporal "product/pkg/product/infrastructure/temporal"
 )
 
-func runWorker(config *config) *cli.Command {
+func runWorker(config *config, logger *log.Logger) *cli.Command {
 	return &cli.Command{
 		Name: "worker",
-		Action: func(_ *cli.Context) error {
+		Action: func(c *cli.Context) error {
 			// Init DB
 			db, err := initMySQL(config)
 			if err != nil {
 				return err
 			}
-			repo := inframysql.NewProductRepository(db)
-
-			svc := appservice.NewProductService(repo)
+			svc := newProductService(config, db)
 
 			activities := infratemporal.NewProductActivities(svc)
 
@@ -47,8 +42,18 @@ func runWorker(config *config) *cli.Command {
 			// Explicitly register activities with string names used in Saga
 			w.RegisterActivityWithOptions(activities.ReserveProduct, activity.RegisterOptions{Name: "ReserveProduct"})
 			w.RegisterActivityWithOptions(activities.ReleaseProduct, activity.RegisterOptions{Name: "ReleaseProduct"})
-
-			log.Println("Starting Product Temporal Worker...")
+			w.RegisterActivityWithOptions(activities.ReserveOrderProduct, activity.RegisterOptions{Name: "ReserveOrderProduct"})
+			w.RegisterActivityWithOptions(activities.ReleaseOrderProduct, activity.RegisterOptions{Name: "ReleaseOrderProduct"})
+			w.RegisterActivityWithOptions(activities.RestockReturnedProduct, activity.RegisterOptions{Name: "RestockReturnedProduct"})
+			w.RegisterActivityWithOptions(activities.ConfirmProduct, activity.RegisterOptions{Name: "ConfirmProduct"})
+			w.RegisterActivityWithOptions(activities.ReserveProducts, activity.RegisterOptions{Name: "ReserveProducts"})
+			w.RegisterActivityWithOptions(activities.ReserveAvailableProducts, activity.RegisterOptions{Name: "ReserveAvailableProducts"})
+			w.RegisterActivityWithOptions(activities.ReleaseProducts, activity.RegisterOptions{Name: "ReleaseProducts"})
+			w.RegisterActivityWithOptions(activities.ConfirmProducts, activity.RegisterOptions{Name: "ConfirmProducts"})
+
+			go runReservationSweep(c.Context, svc, config.ReservationSweepInterval, logger)
+
+			logger.Info("Starting Product Temporal Worker...")
 			return w.Run(worker.InterruptCh())
 		},
 	}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS product_reservations;
\ No newline at end of file
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS stock_reservations
+(
+    `order_id`   VARCHAR(36) NOT NULL,
+    `product_id` VARCHAR(36) NOT NULL,
+    `quantity`   INT         NOT NULL,
+    `state`      TINYINT     NOT NULL,
+    `expires_at` DATETIME    NOT NULL,
+    `created_at` DATETIME    NOT NULL,
+    `updated_at` DATETIME    NOT NULL,
+    PRIMARY KEY (`order_id`, `product_id`),
+    INDEX `stock_reservations_state_expires_at_idx` (`state`, `expires_at`),
+    INDEX `stock_reservations_product_id_state_idx` (`product_id`, `state`)
+    ) ENGINE = InnoDB
+    CHARACTER SET = utf8mb4
+    COLLATE utf8mb4_unicode_ci;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS product_reservations
+(
+    `reservation_id` VARCHAR(36) NOT NULL,
+    `product_id`     VARCHAR(36) NOT NULL,
+    `quantity`       INT         NOT NULL,
+    `created_at`     DATETIME    NOT NULL,
+    `released_at`    DATETIME    NULL,
+    PRIMARY KEY (`reservation_id`, `product_id`)
+    ) ENGINE = InnoDB
+    CHARACTER SET = utf8mb4
+    COLLATE utf8mb4_unicode_ci;
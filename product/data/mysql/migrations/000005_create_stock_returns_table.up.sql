@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS stock_returns
+(
+    `return_id`  VARCHAR(36) NOT NULL,
+    `order_id`   VARCHAR(36) NOT NULL,
+    `product_id` VARCHAR(36) NOT NULL,
+    `quantity`   INT         NOT NULL,
+    `created_at` DATETIME    NOT NULL,
+    PRIMARY KEY (`return_id`, `product_id`)
+    ) ENGINE = InnoDB
+    CHARACTER SET = utf8mb4
+    COLLATE utf8mb4_unicode_ci;
@@ -0,0 +1,35 @@
+package data
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Product struct {
+	ID    uuid.UUID
+	Name  string
+	Price float64
+	// Quantity is available stock, reserved stock is already taken off it
+	Quantity         int
+	ReservedQuantity int
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	DeletedAt        *time.Time
+}
+
+type ListProductsSpec struct {
+	// Name selects products whose name contains the substring
+	Name     *string
+	MinPrice *float64
+	MaxPrice *float64
+	Limit    int
+	// Cursor is an opaque token returned as ProductPage.NextCursor, empty for the first page
+	Cursor string
+}
+
+type ProductPage struct {
+	Products []Product
+	// NextCursor is empty when there are no more products
+	NextCursor string
+}
@@ -0,0 +1,18 @@
+package query
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"product/pkg/product/app/data"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type ProductQueryService interface {
+	FindProduct(ctx context.Context, productID uuid.UUID) (*data.Product, error)
+	// ListProducts returns products which are not removed
+	ListProducts(ctx context.Context, spec data.ListProductsSpec) (*data.ProductPage, error)
+}
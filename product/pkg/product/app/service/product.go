@@ -2,38 +2,196 @@ package service
 
 import (
 	"context"
+	"errors"
+	"slices"
 	"time"
 
-	"product/pkg/product/domain/model"
-
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
 	"github.com/google/uuid"
+
+	"product/pkg/product/domain/model"
+	domainservice "product/pkg/product/domain/service"
 )
 
+// expiredReservationsBatch limits reservations released by one ReleaseExpiredReservations call
+const expiredReservationsBatch = 100
+
 type ProductService struct {
-	repo model.ProductRepository
+	uow             UnitOfWork
+	luow            LockableUnitOfWork
+	eventDispatcher outbox.EventDispatcher[outbox.Event]
+	reservationTTL  time.Duration
+}
+
+func NewProductService(
+	uow UnitOfWork,
+	luow LockableUnitOfWork,
+	eventDispatcher outbox.EventDispatcher[outbox.Event],
+	reservationTTL time.Duration,
+) *ProductService {
+	return &ProductService{
+		uow:             uow,
+		luow:            luow,
+		eventDispatcher: eventDispatcher,
+		reservationTTL:  reservationTTL,
+	}
+}
+
+func (s *ProductService) CreateProduct(ctx context.Context, name string, price float64, quantity int) (uuid.UUID, error) {
+	var productID uuid.UUID
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		id, err := s.productDomainService(ctx, provider.ProductRepository(ctx)).CreateProduct(name, price, quantity)
+		if err != nil {
+			return err
+		}
+		productID = id
+		return nil
+	})
+	return productID, err
+}
+
+func (s *ProductService) UpdateProduct(ctx context.Context, id uuid.UUID, name string, price float64) error {
+	return s.luow.Execute(ctx, []string{productLock(id)}, func(provider RepositoryProvider) error {
+		return s.productDomainService(ctx, provider.ProductRepository(ctx)).UpdateProduct(id, name, price)
+	})
+}
+
+func (s *ProductService) RemoveProduct(ctx context.Context, id uuid.UUID) error {
+	return s.luow.Execute(ctx, []string{productLock(id)}, func(provider RepositoryProvider) error {
+		return s.productDomainService(ctx, provider.ProductRepository(ctx)).RemoveProduct(id)
+	})
+}
+
+func (s *ProductService) AdjustStock(ctx context.Context, id uuid.UUID, delta int) error {
+	return s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		return s.productDomainService(ctx, provider.ProductRepository(ctx)).AdjustStock(id, delta)
+	})
+}
+
+// ReserveStock holds stock of the product for the order, repeated call with the same order and product does nothing
+func (s *ProductService) ReserveStock(ctx context.Context, orderID, productID uuid.UUID, quantity int) error {
+	return s.luow.Execute(ctx, []string{stockReservationLock(orderID)}, func(provider RepositoryProvider) error {
+		return s.stockReservationDomainService(ctx, provider).Reserve(orderID, productID, quantity, s.reservationTTL)
+	})
+}
+
+func (s *ProductService) ReleaseStock(ctx context.Context, orderID, productID uuid.UUID) error {
+	return s.luow.Execute(ctx, []string{stockReservationLock(orderID)}, func(provider RepositoryProvider) error {
+		return s.stockReservationDomainService(ctx, provider).Release(orderID, productID)
+	})
+}
+
+// ReleaseReturnedStock puts back stock of the returned product, repeated call with the same return does nothing
+func (s *ProductService) ReleaseReturnedStock(ctx context.Context, orderID, productID, returnID uuid.UUID, quantity int) error {
+	return s.luow.Execute(ctx, []string{stockReservationLock(orderID)}, func(provider RepositoryProvider) error {
+		return s.stockReservationDomainService(ctx, provider).ReleaseReturned(orderID, productID, returnID, quantity)
+	})
 }
 
-func NewProductService(repo model.ProductRepository) *ProductService {
-	return &ProductService{repo: repo}
+func (s *ProductService) ConfirmStock(ctx context.Context, orderID, productID uuid.UUID) error {
+	return s.luow.Execute(ctx, []string{stockReservationLock(orderID)}, func(provider RepositoryProvider) error {
+		return s.stockReservationDomainService(ctx, provider).Confirm(orderID, productID)
+	})
 }
 
-func (s *ProductService) CreateProduct(_ context.Context, name string, price float64, quantity int) (uuid.UUID, error) {
-	id, _ := s.repo.NextID()
-	p := &model.Product{
-		ID:        id,
-		Name:      name,
-		Price:     price,
-		Quantity:  quantity,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+// ReleaseExpiredReservations releases a batch of reservations whose orders were not confirmed in time
+// and returns the number of released ones
+func (s *ProductService) ReleaseExpiredReservations(ctx context.Context) (int, error) {
+	var expired []model.StockReservation
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		var err error
+		expired, err = provider.StockReservationRepository(ctx).FindExpired(time.Now(), expiredReservationsBatch)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	released := 0
+	for _, reservation := range expired {
+		err = s.ReleaseStock(ctx, reservation.OrderID, reservation.ProductID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		released++
 	}
-	return id, s.repo.Store(p)
+	return released, errors.Join(errs...)
+}
+
+// ReserveProducts reserves all lines for the order or none of them, repeated call does nothing
+func (s *ProductService) ReserveProducts(ctx context.Context, orderID uuid.UUID, lines []model.ReservationLine) error {
+	return s.luow.Execute(ctx, []string{stockReservationLock(orderID)}, func(provider RepositoryProvider) error {
+		return s.stockReservationDomainService(ctx, provider).ReserveOrder(orderID, mergeLines(lines), s.reservationTTL)
+	})
+}
+
+// ReserveAvailableProducts reserves what is in stock for the order and returns reserved lines
+func (s *ProductService) ReserveAvailableProducts(ctx context.Context, orderID uuid.UUID, lines []model.ReservationLine) ([]model.ReservationLine, error) {
+	var reserved []model.ReservationLine
+	err := s.luow.Execute(ctx, []string{stockReservationLock(orderID)}, func(provider RepositoryProvider) error {
+		var err error
+		reserved, err = s.stockReservationDomainService(ctx, provider).ReserveAvailable(orderID, mergeLines(lines), s.reservationTTL)
+		return err
+	})
+	return reserved, err
+}
+
+// ReleaseProducts puts back all stock reserved for the order, repeated call does nothing
+func (s *ProductService) ReleaseProducts(ctx context.Context, orderID uuid.UUID) error {
+	return s.luow.Execute(ctx, []string{stockReservationLock(orderID)}, func(provider RepositoryProvider) error {
+		return s.stockReservationDomainService(ctx, provider).ReleaseOrder(orderID)
+	})
+}
+
+// ConfirmProducts keeps all stock reserved for the order taken once it is paid
+func (s *ProductService) ConfirmProducts(ctx context.Context, orderID uuid.UUID) error {
+	return s.luow.Execute(ctx, []string{stockReservationLock(orderID)}, func(provider RepositoryProvider) error {
+		return s.stockReservationDomainService(ctx, provider).ConfirmOrder(orderID)
+	})
 }
 
-func (s *ProductService) Reserve(_ context.Context, id uuid.UUID, qty int) error {
-	return s.repo.ReserveStock(id, qty)
+func (s *ProductService) productDomainService(ctx context.Context, repository model.ProductRepository) domainservice.Product {
+	return domainservice.NewProductService(repository, &domainEventDispatcher{
+		ctx:             ctx,
+		eventDispatcher: s.eventDispatcher,
+	})
 }
 
-func (s *ProductService) Release(_ context.Context, id uuid.UUID, qty int) error {
-	return s.repo.ReleaseStock(id, qty)
+func (s *ProductService) stockReservationDomainService(ctx context.Context, provider RepositoryProvider) domainservice.StockReservation {
+	return domainservice.NewStockReservationService(
+		provider.StockReservationRepository(ctx),
+		provider.ProductRepository(ctx),
+	)
+}
+
+const (
+	baseProductLock          = "product_"
+	baseStockReservationLock = "stock_reservation_"
+)
+
+func productLock(id uuid.UUID) string {
+	return baseProductLock + id.String()
+}
+
+// stockReservationLock covers all reservations of the order, lock names longer than 64 characters are truncated
+func stockReservationLock(orderID uuid.UUID) string {
+	return baseStockReservationLock + orderID.String()
+}
+
+// mergeLines merges and sorts lines of the same product, so concurrent reservations lock rows in the same order
+func mergeLines(lines []model.ReservationLine) []model.ReservationLine {
+	quantities := make(map[uuid.UUID]int, len(lines))
+	for _, line := range lines {
+		quantities[line.ProductID] += line.Quantity
+	}
+	merged := make([]model.ReservationLine, 0, len(quantities))
+	for productID, quantity := range quantities {
+		merged = append(merged, model.ReservationLine{ProductID: productID, Quantity: quantity})
+	}
+	slices.SortFunc(merged, func(a, b model.ReservationLine) int {
+		return slices.Compare(a.ProductID[:], b.ProductID[:])
+	})
+	return merged
 }
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+
+	"product/pkg/product/domain/model"
+)
+
+type RepositoryProvider interface {
+	ProductRepository(ctx context.Context) model.ProductRepository
+	StockReservationRepository(ctx context.Context) model.StockReservationRepository
+}
+
+type LockableUnitOfWork interface {
+	Execute(ctx context.Context, lockNames []string, f func(provider RepositoryProvider) error) error
+}
+type UnitOfWork interface {
+	Execute(ctx context.Context, f func(provider RepositoryProvider) error) error
+}
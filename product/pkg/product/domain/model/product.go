@@ -10,6 +10,9 @@ import (
 var (
 	ErrProductNotFound   = errors.New("product not found")
 	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrInvalidName       = errors.New("product name is empty")
+	ErrInvalidPrice      = errors.New("product price is negative")
+	ErrInvalidQuantity   = errors.New("product quantity is negative")
 )
 
 type Product struct {
@@ -24,10 +27,20 @@ type Product struct {
 
 type ProductRepository interface {
 	NextID() (uuid.UUID, error)
+	// Store keeps quantity of the stored product, it is changed by stock methods only
 	Store(product *Product) error
 	Find(id uuid.UUID) (*Product, error)
 	Remove(id uuid.UUID) error
 
+	// ReserveStock takes quantity off the stock, ErrInsufficientStock is returned when it is not enough
 	ReserveStock(id uuid.UUID, quantity int) error
+	// ReleaseStock puts quantity back to the stock
 	ReleaseStock(id uuid.UUID, quantity int) error
+	// AdjustStock adds delta to the quantity, ErrInsufficientStock is returned when it would become negative
+	AdjustStock(id uuid.UUID, delta int) error
+}
+
+type ReservationLine struct {
+	ProductID uuid.UUID
+	Quantity  int
 }
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrStockReservationNotFound  = errors.New("stock reservation not found")
+	ErrStockReservationReleased  = errors.New("stock reservation is released")
+	ErrStockReservationConfirmed = errors.New("stock reservation is confirmed")
+)
+
+type StockReservationState int
+
+const (
+	// StockReserved holds stock until the order confirms it or the reservation expires
+	StockReserved StockReservationState = iota
+	StockConfirmed
+	StockReleased
+)
+
+// StockReservation is stock of the product held for the order, an order has one reservation per product
+type StockReservation struct {
+	OrderID   uuid.UUID
+	ProductID uuid.UUID
+	Quantity  int
+	State     StockReservationState
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// StockReturn is stock of the product put back for the order return, a return is restocked once per product
+type StockReturn struct {
+	ReturnID  uuid.UUID
+	OrderID   uuid.UUID
+	ProductID uuid.UUID
+	Quantity  int
+	CreatedAt time.Time
+}
+
+type StockReservationRepository interface {
+	Store(reservation *StockReservation) error
+	Find(orderID, productID uuid.UUID) (*StockReservation, error)
+	// FindByOrderID returns all reservations of the order ordered by product
+	FindByOrderID(orderID uuid.UUID) ([]StockReservation, error)
+	// FindExpired returns reserved reservations which expire before the time
+	FindExpired(before time.Time, limit int) ([]StockReservation, error)
+
+	StoreReturn(stockReturn *StockReturn) error
+	// HasReturn tells whether the product of the return is restocked already
+	HasReturn(returnID, productID uuid.UUID) (bool, error)
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,9 +12,12 @@ import (
 )
 
 type Product interface {
-	CreateProduct(name string, price float64) (uuid.UUID, error)
+	CreateProduct(name string, price float64, quantity int) (uuid.UUID, error)
+	// UpdateProduct changes name and price, removed product is not found
 	UpdateProduct(productID uuid.UUID, name string, price float64) error
 	RemoveProduct(productID uuid.UUID) error
+	// AdjustStock adds delta to the product quantity, negative delta writes stock off
+	AdjustStock(productID uuid.UUID, delta int) error
 }
 
 func NewProductService(repo model.ProductRepository, dispatcher commonevent.Dispatcher) Product {
@@ -28,7 +32,14 @@ type productService struct {
 	dispatcher commonevent.Dispatcher
 }
 
-func (p productService) CreateProduct(name string, price float64) (uuid.UUID, error) {
+func (p productService) CreateProduct(name string, price float64, quantity int) (uuid.UUID, error) {
+	if err := validateProduct(name, price); err != nil {
+		return uuid.Nil, err
+	}
+	if quantity < 0 {
+		return uuid.Nil, model.ErrInvalidQuantity
+	}
+
 	productID, err := p.repo.NextID()
 	if err != nil {
 		return uuid.Nil, err
@@ -39,6 +50,7 @@ func (p productService) CreateProduct(name string, price float64) (uuid.UUID, er
 		ID:        productID,
 		Name:      name,
 		Price:     price,
+		Quantity:  quantity,
 		CreatedAt: currentTime,
 		UpdatedAt: currentTime,
 	})
@@ -54,10 +66,17 @@ func (p productService) CreateProduct(name string, price float64) (uuid.UUID, er
 }
 
 func (p productService) UpdateProduct(productID uuid.UUID, name string, price float64) error {
+	if err := validateProduct(name, price); err != nil {
+		return err
+	}
+
 	product, err := p.repo.Find(productID)
 	if err != nil {
 		return err
 	}
+	if product.DeletedAt != nil {
+		return model.ErrProductNotFound
+	}
 
 	product.Name = name
 	product.Price = price
@@ -80,6 +99,9 @@ func (p productService) RemoveProduct(productID uuid.UUID) error {
 		}
 		return err
 	}
+	if product.DeletedAt != nil {
+		return nil
+	}
 
 	now := time.Now()
 	product.DeletedAt = &now
@@ -93,3 +115,35 @@ func (p productService) RemoveProduct(productID uuid.UUID) error {
 		ProductID: productID,
 	})
 }
+
+func (p productService) AdjustStock(productID uuid.UUID, delta int) error {
+	if delta == 0 {
+		return nil
+	}
+
+	product, err := p.repo.Find(productID)
+	if err != nil {
+		return err
+	}
+	if product.DeletedAt != nil {
+		return model.ErrProductNotFound
+	}
+
+	if err = p.repo.AdjustStock(productID, delta); err != nil {
+		return err
+	}
+
+	return p.dispatcher.Dispatch(model.ProductUpdated{
+		ProductID: productID,
+	})
+}
+
+func validateProduct(name string, price float64) error {
+	if strings.TrimSpace(name) == "" {
+		return model.ErrInvalidName
+	}
+	if price < 0 {
+		return model.ErrInvalidPrice
+	}
+	return nil
+}
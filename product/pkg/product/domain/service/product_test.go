@@ -52,6 +52,11 @@ func (m *MockProductRepository) ReleaseStock(id uuid.UUID, quantity int) error {
 	return args.Error(0)
 }
 
+func (m *MockProductRepository) AdjustStock(id uuid.UUID, delta int) error {
+	args := m.Called(id, delta)
+	return args.Error(0)
+}
+
 type MockEventDispatcher struct {
 	mock.Mock
 }
@@ -78,6 +83,7 @@ func TestCreateProduct_Success(t *testing.T) {
 
 	name := testName
 	price := 99.99
+	quantity := 10
 	productID := uuid.New()
 
 	productRepo.On("NextID").Return(productID, nil)
@@ -85,6 +91,7 @@ func TestCreateProduct_Success(t *testing.T) {
 		return product.ID == productID &&
 			product.Name == name &&
 			product.Price == price &&
+			product.Quantity == quantity &&
 			!product.CreatedAt.IsZero() &&
 			product.UpdatedAt.Equal(product.CreatedAt) &&
 			product.DeletedAt == nil
@@ -96,7 +103,7 @@ func TestCreateProduct_Success(t *testing.T) {
 
 	svc := NewProductService(productRepo, eventDispatcher)
 
-	id, err := svc.CreateProduct(name, price)
+	id, err := svc.CreateProduct(name, price, quantity)
 
 	assert.NoError(t, err)
 	assert.Equal(t, productID, id)
@@ -104,6 +111,22 @@ func TestCreateProduct_Success(t *testing.T) {
 	eventDispatcher.AssertExpectations(t)
 }
 
+func TestCreateProduct_Invalid(t *testing.T) {
+	productRepo := new(MockProductRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	svc := NewProductService(productRepo, eventDisp)
+
+	_, err := svc.CreateProduct(" ", 99.99, 1)
+	assert.ErrorIs(t, err, model.ErrInvalidName)
+	_, err = svc.CreateProduct(testName, -1, 1)
+	assert.ErrorIs(t, err, model.ErrInvalidPrice)
+	_, err = svc.CreateProduct(testName, 99.99, -1)
+	assert.ErrorIs(t, err, model.ErrInvalidQuantity)
+	productRepo.AssertExpectations(t)
+	eventDisp.AssertExpectations(t)
+}
+
 func TestCreateProduct_RepoError(t *testing.T) {
 	productRepo := new(MockProductRepository)
 	eventDisp := new(MockEventDispatcher)
@@ -117,7 +140,7 @@ func TestCreateProduct_RepoError(t *testing.T) {
 
 	svc := NewProductService(productRepo, eventDisp)
 
-	_, err := svc.CreateProduct(name, price)
+	_, err := svc.CreateProduct(name, price, 0)
 	assert.Error(t, err)
 	productRepo.AssertExpectations(t)
 	eventDisp.AssertExpectations(t)
@@ -137,7 +160,7 @@ func TestCreateProduct_EventDispatchError(t *testing.T) {
 
 	svc := NewProductService(productRepo, eventDisp)
 
-	_, err := svc.CreateProduct(name, price)
+	_, err := svc.CreateProduct(name, price, 0)
 	assert.Error(t, err)
 	productRepo.AssertExpectations(t)
 	eventDisp.AssertExpectations(t)
@@ -186,6 +209,24 @@ func TestUpdateProduct_ProductNotFound(t *testing.T) {
 	productRepo.AssertExpectations(t)
 }
 
+func TestUpdateProduct_Removed(t *testing.T) {
+	productRepo := new(MockProductRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	productID := uuid.New()
+	product := newProduct(productID, "Removed", 49.99)
+	deletedAt := time.Now()
+	product.DeletedAt = &deletedAt
+	productRepo.On("Find", productID).Return(product, nil)
+
+	svc := NewProductService(productRepo, eventDisp)
+
+	err := svc.UpdateProduct(productID, testName, 149.99)
+	assert.ErrorIs(t, err, model.ErrProductNotFound)
+	productRepo.AssertExpectations(t)
+	eventDisp.AssertExpectations(t)
+}
+
 func TestRemoveProduct_Success(t *testing.T) {
 	productRepo := new(MockProductRepository)
 	eventDisp := new(MockEventDispatcher)
@@ -236,3 +277,36 @@ func TestRemoveProduct_FindError(t *testing.T) {
 	err := svc.RemoveProduct(productID)
 	assert.Error(t, err)
 }
+
+func TestAdjustStock_Success(t *testing.T) {
+	productRepo := new(MockProductRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	productID := uuid.New()
+	productRepo.On("Find", productID).Return(newProduct(productID, testName, 29.99), nil)
+	productRepo.On("AdjustStock", productID, -3).Return(nil)
+	eventDisp.On("Dispatch", model.ProductUpdated{ProductID: productID}).Return(nil)
+
+	svc := NewProductService(productRepo, eventDisp)
+
+	err := svc.AdjustStock(productID, -3)
+	assert.NoError(t, err)
+	productRepo.AssertExpectations(t)
+	eventDisp.AssertExpectations(t)
+}
+
+func TestAdjustStock_InsufficientStock(t *testing.T) {
+	productRepo := new(MockProductRepository)
+	eventDisp := new(MockEventDispatcher)
+
+	productID := uuid.New()
+	productRepo.On("Find", productID).Return(newProduct(productID, testName, 29.99), nil)
+	productRepo.On("AdjustStock", productID, -3).Return(model.ErrInsufficientStock)
+
+	svc := NewProductService(productRepo, eventDisp)
+
+	err := svc.AdjustStock(productID, -3)
+	assert.ErrorIs(t, err, model.ErrInsufficientStock)
+	productRepo.AssertExpectations(t)
+	eventDisp.AssertExpectations(t)
+}
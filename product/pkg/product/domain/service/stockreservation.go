@@ -0,0 +1,271 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"product/pkg/product/domain/model"
+)
+
+var ErrInvalidReservationQuantity = errors.New("reservation quantity must be positive")
+
+type StockReservation interface {
+	// Reserve holds stock of the product for the order until it is confirmed or expires, repeated call does nothing
+	Reserve(orderID, productID uuid.UUID, quantity int, ttl time.Duration) error
+	// Release puts reserved stock back, repeated call and call without reservation do nothing
+	Release(orderID, productID uuid.UUID) error
+	// Confirm keeps reserved stock taken, confirmed reservation does not expire
+	Confirm(orderID, productID uuid.UUID) error
+
+	// ReserveOrder reserves all lines for the order or none of them within the transaction, repeated call does nothing
+	ReserveOrder(orderID uuid.UUID, lines []model.ReservationLine, ttl time.Duration) error
+	// ReserveAvailable reserves up to the quantity of every line which is in stock and returns reserved lines,
+	// lines reserved by a previous call are returned as they are
+	ReserveAvailable(orderID uuid.UUID, lines []model.ReservationLine, ttl time.Duration) ([]model.ReservationLine, error)
+	// ReleaseOrder puts back stock of all reservations of the order, confirmed ones included
+	ReleaseOrder(orderID uuid.UUID) error
+	// ReleaseReturned puts back quantity of the product returned by the order return, repeated call with the same return does nothing,
+	// orders reserved before the reservation ledger have no reservation and are restocked as well
+	ReleaseReturned(orderID, productID, returnID uuid.UUID, quantity int) error
+	// ConfirmOrder confirms all reservations of the order, ErrStockReservationReleased is returned when any of them expired
+	ConfirmOrder(orderID uuid.UUID) error
+}
+
+func NewStockReservationService(
+	reservationRepo model.StockReservationRepository,
+	productRepo model.ProductRepository,
+) StockReservation {
+	return &stockReservationService{
+		reservationRepo: reservationRepo,
+		productRepo:     productRepo,
+	}
+}
+
+type stockReservationService struct {
+	reservationRepo model.StockReservationRepository
+	productRepo     model.ProductRepository
+}
+
+func (s stockReservationService) Reserve(orderID, productID uuid.UUID, quantity int, ttl time.Duration) error {
+	if quantity <= 0 {
+		return ErrInvalidReservationQuantity
+	}
+
+	reservation, err := s.reservationRepo.Find(orderID, productID)
+	if err != nil && !errors.Is(err, model.ErrStockReservationNotFound) {
+		return err
+	}
+	if reservation != nil {
+		if reservation.State == model.StockReleased {
+			return model.ErrStockReservationReleased
+		}
+		return nil
+	}
+
+	product, err := s.productRepo.Find(productID)
+	if err != nil {
+		return err
+	}
+	if product.DeletedAt != nil {
+		return model.ErrProductNotFound
+	}
+	return s.reserve(orderID, productID, quantity, ttl)
+}
+
+func (s stockReservationService) ReserveOrder(orderID uuid.UUID, lines []model.ReservationLine, ttl time.Duration) error {
+	for _, line := range mergeLines(lines) {
+		if err := s.Reserve(orderID, line.ProductID, line.Quantity, ttl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s stockReservationService) ReserveAvailable(orderID uuid.UUID, lines []model.ReservationLine, ttl time.Duration) ([]model.ReservationLine, error) {
+	lines = mergeLines(lines)
+	reserved := make([]model.ReservationLine, 0, len(lines))
+	for _, line := range lines {
+		reservation, err := s.reservationRepo.Find(orderID, line.ProductID)
+		if err != nil && !errors.Is(err, model.ErrStockReservationNotFound) {
+			return nil, err
+		}
+		if reservation != nil {
+			if reservation.State != model.StockReleased {
+				reserved = append(reserved, model.ReservationLine{ProductID: line.ProductID, Quantity: reservation.Quantity})
+			}
+			continue
+		}
+
+		product, err := s.productRepo.Find(line.ProductID)
+		if errors.Is(err, model.ErrProductNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		quantity := min(product.Quantity, line.Quantity)
+		if product.DeletedAt != nil || quantity <= 0 {
+			continue
+		}
+		err = s.reserve(orderID, line.ProductID, quantity, ttl)
+		// Stock may be taken by a concurrent order since the product was found
+		if errors.Is(err, model.ErrInsufficientStock) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		reserved = append(reserved, model.ReservationLine{ProductID: line.ProductID, Quantity: quantity})
+	}
+	return reserved, nil
+}
+
+// mergeLines sums up quantities of lines with the same product, the order has one reservation per product
+func mergeLines(lines []model.ReservationLine) []model.ReservationLine {
+	merged := make([]model.ReservationLine, 0, len(lines))
+	for _, line := range lines {
+		i := slices.IndexFunc(merged, func(m model.ReservationLine) bool {
+			return m.ProductID == line.ProductID
+		})
+		if i >= 0 {
+			merged[i].Quantity += line.Quantity
+			continue
+		}
+		merged = append(merged, line)
+	}
+	return merged
+}
+
+func (s stockReservationService) reserve(orderID, productID uuid.UUID, quantity int, ttl time.Duration) error {
+	if err := s.productRepo.ReserveStock(productID, quantity); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return s.reservationRepo.Store(&model.StockReservation{
+		OrderID:   orderID,
+		ProductID: productID,
+		Quantity:  quantity,
+		State:     model.StockReserved,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+}
+
+func (s stockReservationService) Release(orderID, productID uuid.UUID) error {
+	reservation, err := s.reservationRepo.Find(orderID, productID)
+	if errors.Is(err, model.ErrStockReservationNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch reservation.State {
+	case model.StockReleased:
+		return nil
+	case model.StockConfirmed:
+		return model.ErrStockReservationConfirmed
+	}
+
+	return s.release(reservation)
+}
+
+func (s stockReservationService) ReleaseOrder(orderID uuid.UUID) error {
+	reservations, err := s.reservationRepo.FindByOrderID(orderID)
+	if err != nil {
+		return err
+	}
+	for i := range reservations {
+		if reservations[i].State == model.StockReleased {
+			continue
+		}
+		if err = s.release(&reservations[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s stockReservationService) ReleaseReturned(orderID, productID, returnID uuid.UUID, quantity int) error {
+	if quantity <= 0 {
+		return ErrInvalidReservationQuantity
+	}
+
+	returned, err := s.reservationRepo.HasReturn(returnID, productID)
+	if err != nil || returned {
+		return err
+	}
+
+	reservation, err := s.reservationRepo.Find(orderID, productID)
+	if err != nil && !errors.Is(err, model.ErrStockReservationNotFound) {
+		return err
+	}
+	// Released stock is back already, so it is not restocked twice
+	if reservation != nil && reservation.State == model.StockReleased {
+		return model.ErrStockReservationReleased
+	}
+
+	if err = s.productRepo.ReleaseStock(productID, quantity); err != nil {
+		return err
+	}
+	return s.reservationRepo.StoreReturn(&model.StockReturn{
+		ReturnID:  returnID,
+		OrderID:   orderID,
+		ProductID: productID,
+		Quantity:  quantity,
+		CreatedAt: time.Now(),
+	})
+}
+
+func (s stockReservationService) release(reservation *model.StockReservation) error {
+	if err := s.productRepo.ReleaseStock(reservation.ProductID, reservation.Quantity); err != nil {
+		return err
+	}
+	reservation.State = model.StockReleased
+	reservation.UpdatedAt = time.Now()
+	return s.reservationRepo.Store(reservation)
+}
+
+func (s stockReservationService) Confirm(orderID, productID uuid.UUID) error {
+	reservation, err := s.reservationRepo.Find(orderID, productID)
+	if err != nil {
+		return err
+	}
+
+	switch reservation.State {
+	case model.StockConfirmed:
+		return nil
+	case model.StockReleased:
+		return model.ErrStockReservationReleased
+	}
+
+	reservation.State = model.StockConfirmed
+	reservation.UpdatedAt = time.Now()
+	return s.reservationRepo.Store(reservation)
+}
+
+func (s stockReservationService) ConfirmOrder(orderID uuid.UUID) error {
+	reservations, err := s.reservationRepo.FindByOrderID(orderID)
+	if err != nil {
+		return err
+	}
+	for i := range reservations {
+		switch reservations[i].State {
+		case model.StockConfirmed:
+			continue
+		case model.StockReleased:
+			return model.ErrStockReservationReleased
+		}
+		reservations[i].State = model.StockConfirmed
+		reservations[i].UpdatedAt = time.Now()
+		if err = s.reservationRepo.Store(&reservations[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,354 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"product/pkg/product/domain/model"
+)
+
+type MockStockReservationRepository struct {
+	mock.Mock
+}
+
+func (m *MockStockReservationRepository) Store(reservation *model.StockReservation) error {
+	args := m.Called(reservation)
+	return args.Error(0)
+}
+
+func (m *MockStockReservationRepository) Find(orderID, productID uuid.UUID) (*model.StockReservation, error) {
+	args := m.Called(orderID, productID)
+	if r, ok := args.Get(0).(*model.StockReservation); ok {
+		return r, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockStockReservationRepository) FindByOrderID(orderID uuid.UUID) ([]model.StockReservation, error) {
+	args := m.Called(orderID)
+	if r, ok := args.Get(0).([]model.StockReservation); ok {
+		return r, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockStockReservationRepository) FindExpired(before time.Time, limit int) ([]model.StockReservation, error) {
+	args := m.Called(before, limit)
+	if r, ok := args.Get(0).([]model.StockReservation); ok {
+		return r, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockStockReservationRepository) StoreReturn(stockReturn *model.StockReturn) error {
+	args := m.Called(stockReturn)
+	return args.Error(0)
+}
+
+func (m *MockStockReservationRepository) HasReturn(returnID, productID uuid.UUID) (bool, error) {
+	args := m.Called(returnID, productID)
+	return args.Bool(0), args.Error(1)
+}
+
+func newStockReservation(orderID, productID uuid.UUID, quantity int, state model.StockReservationState) *model.StockReservation {
+	now := time.Now()
+	return &model.StockReservation{
+		OrderID:   orderID,
+		ProductID: productID,
+		Quantity:  quantity,
+		State:     state,
+		ExpiresAt: now.Add(time.Minute),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func TestReserve_Success(t *testing.T) {
+	reservationRepo := new(MockStockReservationRepository)
+	productRepo := new(MockProductRepository)
+
+	orderID, productID := uuid.New(), uuid.New()
+	reservationRepo.On("Find", orderID, productID).Return(nil, model.ErrStockReservationNotFound)
+	productRepo.On("Find", productID).Return(newProduct(productID, testName, 10), nil)
+	productRepo.On("ReserveStock", productID, 3).Return(nil)
+	reservationRepo.On("Store", mock.MatchedBy(func(r *model.StockReservation) bool {
+		return r.OrderID == orderID &&
+			r.ProductID == productID &&
+			r.Quantity == 3 &&
+			r.State == model.StockReserved &&
+			r.ExpiresAt.After(r.CreatedAt)
+	})).Return(nil)
+
+	svc := NewStockReservationService(reservationRepo, productRepo)
+
+	err := svc.Reserve(orderID, productID, 3, time.Minute)
+	assert.NoError(t, err)
+	reservationRepo.AssertExpectations(t)
+	productRepo.AssertExpectations(t)
+}
+
+func TestReserve_Repeated(t *testing.T) {
+	reservationRepo := new(MockStockReservationRepository)
+	productRepo := new(MockProductRepository)
+
+	orderID, productID := uuid.New(), uuid.New()
+	reservationRepo.On("Find", orderID, productID).Return(newStockReservation(orderID, productID, 3, model.StockReserved), nil)
+
+	svc := NewStockReservationService(reservationRepo, productRepo)
+
+	err := svc.Reserve(orderID, productID, 3, time.Minute)
+	assert.NoError(t, err)
+	reservationRepo.AssertExpectations(t)
+	productRepo.AssertNotCalled(t, "ReserveStock", mock.Anything, mock.Anything)
+}
+
+func TestReserve_Released(t *testing.T) {
+	reservationRepo := new(MockStockReservationRepository)
+	productRepo := new(MockProductRepository)
+
+	orderID, productID := uuid.New(), uuid.New()
+	reservationRepo.On("Find", orderID, productID).Return(newStockReservation(orderID, productID, 3, model.StockReleased), nil)
+
+	svc := NewStockReservationService(reservationRepo, productRepo)
+
+	err := svc.Reserve(orderID, productID, 3, time.Minute)
+	assert.ErrorIs(t, err, model.ErrStockReservationReleased)
+	productRepo.AssertNotCalled(t, "ReserveStock", mock.Anything, mock.Anything)
+}
+
+func TestReserve_InsufficientStock(t *testing.T) {
+	reservationRepo := new(MockStockReservationRepository)
+	productRepo := new(MockProductRepository)
+
+	orderID, productID := uuid.New(), uuid.New()
+	reservationRepo.On("Find", orderID, productID).Return(nil, model.ErrStockReservationNotFound)
+	productRepo.On("Find", productID).Return(newProduct(productID, testName, 10), nil)
+	productRepo.On("ReserveStock", productID, 3).Return(model.ErrInsufficientStock)
+
+	svc := NewStockReservationService(reservationRepo, productRepo)
+
+	err := svc.Reserve(orderID, productID, 3, time.Minute)
+	assert.ErrorIs(t, err, model.ErrInsufficientStock)
+	reservationRepo.AssertNotCalled(t, "Store", mock.Anything)
+}
+
+func TestReserveOrder_SameProduct(t *testing.T) {
+	reservationRepo := new(MockStockReservationRepository)
+	productRepo := new(MockProductRepository)
+
+	orderID, productID := uuid.New(), uuid.New()
+	reservationRepo.On("Find", orderID, productID).Return(nil, model.ErrStockReservationNotFound)
+	productRepo.On("Find", productID).Return(newProduct(productID, testName, 10), nil)
+	productRepo.On("ReserveStock", productID, 5).Return(nil)
+	reservationRepo.On("Store", mock.MatchedBy(func(r *model.StockReservation) bool {
+		return r.ProductID == productID && r.Quantity == 5
+	})).Return(nil)
+
+	svc := NewStockReservationService(reservationRepo, productRepo)
+
+	err := svc.ReserveOrder(orderID, []model.ReservationLine{
+		{ProductID: productID, Quantity: 2},
+		{ProductID: productID, Quantity: 3},
+	}, time.Minute)
+	assert.NoError(t, err)
+	reservationRepo.AssertExpectations(t)
+	productRepo.AssertExpectations(t)
+}
+
+func TestRelease_Success(t *testing.T) {
+	reservationRepo := new(MockStockReservationRepository)
+	productRepo := new(MockProductRepository)
+
+	orderID, productID := uuid.New(), uuid.New()
+	reservationRepo.On("Find", orderID, productID).Return(newStockReservation(orderID, productID, 3, model.StockReserved), nil)
+	productRepo.On("ReleaseStock", productID, 3).Return(nil)
+	reservationRepo.On("Store", mock.MatchedBy(func(r *model.StockReservation) bool {
+		return r.State == model.StockReleased
+	})).Return(nil)
+
+	svc := NewStockReservationService(reservationRepo, productRepo)
+
+	err := svc.Release(orderID, productID)
+	assert.NoError(t, err)
+	reservationRepo.AssertExpectations(t)
+	productRepo.AssertExpectations(t)
+}
+
+func TestRelease_Idempotent(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		reservation *model.StockReservation
+		findErr     error
+	}{
+		{name: "released", reservation: newStockReservation(uuid.Nil, uuid.Nil, 3, model.StockReleased)},
+		{name: "not found", findErr: model.ErrStockReservationNotFound},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			reservationRepo := new(MockStockReservationRepository)
+			productRepo := new(MockProductRepository)
+
+			orderID, productID := uuid.New(), uuid.New()
+			reservationRepo.On("Find", orderID, productID).Return(tc.reservation, tc.findErr)
+
+			svc := NewStockReservationService(reservationRepo, productRepo)
+
+			err := svc.Release(orderID, productID)
+			assert.NoError(t, err)
+			productRepo.AssertNotCalled(t, "ReleaseStock", mock.Anything, mock.Anything)
+			reservationRepo.AssertNotCalled(t, "Store", mock.Anything)
+		})
+	}
+}
+
+func TestRelease_Confirmed(t *testing.T) {
+	reservationRepo := new(MockStockReservationRepository)
+	productRepo := new(MockProductRepository)
+
+	orderID, productID := uuid.New(), uuid.New()
+	reservationRepo.On("Find", orderID, productID).Return(newStockReservation(orderID, productID, 3, model.StockConfirmed), nil)
+
+	svc := NewStockReservationService(reservationRepo, productRepo)
+
+	err := svc.Release(orderID, productID)
+	assert.ErrorIs(t, err, model.ErrStockReservationConfirmed)
+	productRepo.AssertNotCalled(t, "ReleaseStock", mock.Anything, mock.Anything)
+}
+
+func TestConfirm_Success(t *testing.T) {
+	reservationRepo := new(MockStockReservationRepository)
+	productRepo := new(MockProductRepository)
+
+	orderID, productID := uuid.New(), uuid.New()
+	reservationRepo.On("Find", orderID, productID).Return(newStockReservation(orderID, productID, 3, model.StockReserved), nil)
+	reservationRepo.On("Store", mock.MatchedBy(func(r *model.StockReservation) bool {
+		return r.State == model.StockConfirmed
+	})).Return(nil)
+
+	svc := NewStockReservationService(reservationRepo, productRepo)
+
+	err := svc.Confirm(orderID, productID)
+	assert.NoError(t, err)
+	reservationRepo.AssertExpectations(t)
+}
+
+func TestReserveAvailable_PartialStock(t *testing.T) {
+	reservationRepo := new(MockStockReservationRepository)
+	productRepo := new(MockProductRepository)
+
+	orderID, inStockID, outOfStockID := uuid.New(), uuid.New(), uuid.New()
+	inStock := newProduct(inStockID, testName, 10)
+	inStock.Quantity = 2
+	reservationRepo.On("Find", orderID, inStockID).Return(nil, model.ErrStockReservationNotFound)
+	reservationRepo.On("Find", orderID, outOfStockID).Return(nil, model.ErrStockReservationNotFound)
+	productRepo.On("Find", inStockID).Return(inStock, nil)
+	productRepo.On("Find", outOfStockID).Return(newProduct(outOfStockID, testName, 10), nil)
+	productRepo.On("ReserveStock", inStockID, 2).Return(nil)
+	reservationRepo.On("Store", mock.MatchedBy(func(r *model.StockReservation) bool {
+		return r.ProductID == inStockID && r.Quantity == 2 && r.State == model.StockReserved
+	})).Return(nil)
+
+	svc := NewStockReservationService(reservationRepo, productRepo)
+
+	reserved, err := svc.ReserveAvailable(orderID, []model.ReservationLine{
+		{ProductID: inStockID, Quantity: 3},
+		{ProductID: outOfStockID, Quantity: 1},
+	}, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.ReservationLine{{ProductID: inStockID, Quantity: 2}}, reserved)
+	reservationRepo.AssertExpectations(t)
+	productRepo.AssertExpectations(t)
+}
+
+func TestReserveAvailable_Repeated(t *testing.T) {
+	reservationRepo := new(MockStockReservationRepository)
+	productRepo := new(MockProductRepository)
+
+	orderID, productID := uuid.New(), uuid.New()
+	reservationRepo.On("Find", orderID, productID).Return(newStockReservation(orderID, productID, 2, model.StockReserved), nil)
+
+	svc := NewStockReservationService(reservationRepo, productRepo)
+
+	reserved, err := svc.ReserveAvailable(orderID, []model.ReservationLine{{ProductID: productID, Quantity: 3}}, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.ReservationLine{{ProductID: productID, Quantity: 2}}, reserved)
+	productRepo.AssertNotCalled(t, "ReserveStock", mock.Anything, mock.Anything)
+	reservationRepo.AssertNotCalled(t, "Store", mock.Anything)
+}
+
+func TestReleaseOrder_ReleasesConfirmed(t *testing.T) {
+	reservationRepo := new(MockStockReservationRepository)
+	productRepo := new(MockProductRepository)
+
+	orderID, confirmedID, releasedID := uuid.New(), uuid.New(), uuid.New()
+	reservationRepo.On("FindByOrderID", orderID).Return([]model.StockReservation{
+		*newStockReservation(orderID, confirmedID, 3, model.StockConfirmed),
+		*newStockReservation(orderID, releasedID, 1, model.StockReleased),
+	}, nil)
+	productRepo.On("ReleaseStock", confirmedID, 3).Return(nil)
+	reservationRepo.On("Store", mock.MatchedBy(func(r *model.StockReservation) bool {
+		return r.ProductID == confirmedID && r.State == model.StockReleased
+	})).Return(nil)
+
+	svc := NewStockReservationService(reservationRepo, productRepo)
+
+	err := svc.ReleaseOrder(orderID)
+	assert.NoError(t, err)
+	reservationRepo.AssertExpectations(t)
+	productRepo.AssertExpectations(t)
+	productRepo.AssertNotCalled(t, "ReleaseStock", releasedID, mock.Anything)
+}
+
+func TestConfirmOrder_Released(t *testing.T) {
+	reservationRepo := new(MockStockReservationRepository)
+	productRepo := new(MockProductRepository)
+
+	orderID := uuid.New()
+	reservationRepo.On("FindByOrderID", orderID).Return([]model.StockReservation{
+		*newStockReservation(orderID, uuid.New(), 3, model.StockReleased),
+	}, nil)
+
+	svc := NewStockReservationService(reservationRepo, productRepo)
+
+	err := svc.ConfirmOrder(orderID)
+	assert.ErrorIs(t, err, model.ErrStockReservationReleased)
+	reservationRepo.AssertNotCalled(t, "Store", mock.Anything)
+}
+
+func TestReleaseReturned_Success(t *testing.T) {
+	reservationRepo := new(MockStockReservationRepository)
+	productRepo := new(MockProductRepository)
+
+	orderID, productID, returnID := uuid.New(), uuid.New(), uuid.New()
+	reservationRepo.On("HasReturn", returnID, productID).Return(false, nil)
+	reservationRepo.On("Find", orderID, productID).Return(newStockReservation(orderID, productID, 3, model.StockConfirmed), nil)
+	productRepo.On("ReleaseStock", productID, 2).Return(nil)
+	reservationRepo.On("StoreReturn", mock.MatchedBy(func(r *model.StockReturn) bool {
+		return r.ReturnID == returnID && r.ProductID == productID && r.Quantity == 2
+	})).Return(nil)
+
+	svc := NewStockReservationService(reservationRepo, productRepo)
+
+	err := svc.ReleaseReturned(orderID, productID, returnID, 2)
+	assert.NoError(t, err)
+	reservationRepo.AssertExpectations(t)
+	productRepo.AssertExpectations(t)
+}
+
+func TestReleaseReturned_Repeated(t *testing.T) {
+	reservationRepo := new(MockStockReservationRepository)
+	productRepo := new(MockProductRepository)
+
+	orderID, productID, returnID := uuid.New(), uuid.New(), uuid.New()
+	reservationRepo.On("HasReturn", returnID, productID).Return(true, nil)
+
+	svc := NewStockReservationService(reservationRepo, productRepo)
+
+	err := svc.ReleaseReturned(orderID, productID, returnID, 2)
+	assert.NoError(t, err)
+	productRepo.AssertNotCalled(t, "ReleaseStock", mock.Anything, mock.Anything)
+	reservationRepo.AssertNotCalled(t, "StoreReturn", mock.Anything)
+}
@@ -0,0 +1,158 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"product/pkg/product/app/data"
+	"product/pkg/product/app/query"
+	"product/pkg/product/domain/model"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 500
+)
+
+func NewProductQueryService(client mysql.ClientContext) query.ProductQueryService {
+	return &productQueryService{
+		client: client,
+	}
+}
+
+type productQueryService struct {
+	client mysql.ClientContext
+}
+
+type productRow struct {
+	ID               string              `db:"id"`
+	Name             string              `db:"name"`
+	Price            float64             `db:"price"`
+	Quantity         int                 `db:"quantity"`
+	ReservedQuantity int                 `db:"reserved_quantity"`
+	CreatedAt        time.Time           `db:"created_at"`
+	UpdatedAt        time.Time           `db:"updated_at"`
+	DeletedAt        sql.Null[time.Time] `db:"deleted_at"`
+}
+
+// productColumns selects reserved quantity as the sum of reservations not confirmed or released yet
+var productColumns = `id, name, price, quantity, created_at, updated_at, deleted_at,
+	COALESCE((SELECT SUM(r.quantity) FROM stock_reservations r WHERE r.product_id = products.id AND r.state = ` +
+	strconv.Itoa(int(model.StockReserved)) + `), 0) AS reserved_quantity`
+
+func (p *productQueryService) FindProduct(ctx context.Context, productID uuid.UUID) (*data.Product, error) {
+	var row productRow
+	err := p.client.GetContext(
+		ctx,
+		&row,
+		`SELECT `+productColumns+` FROM products WHERE id = ?`,
+		productID.String(),
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(model.ErrProductNotFound)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	product := toProduct(row)
+	return &product, nil
+}
+
+func (p *productQueryService) ListProducts(ctx context.Context, spec data.ListProductsSpec) (*data.ProductPage, error) {
+	limit := spec.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	limit = min(limit, maxListLimit)
+
+	conditions := []string{"deleted_at IS NULL"}
+	var args []interface{}
+	if spec.Cursor != "" {
+		lastProductID, err := decodeCursor(spec.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, "id > ?")
+		args = append(args, lastProductID.String())
+	}
+	if spec.Name != nil {
+		conditions = append(conditions, "name LIKE ?")
+		args = append(args, "%"+escapeLike(*spec.Name)+"%")
+	}
+	if spec.MinPrice != nil {
+		conditions = append(conditions, "price >= ?")
+		args = append(args, *spec.MinPrice)
+	}
+	if spec.MaxPrice != nil {
+		conditions = append(conditions, "price <= ?")
+		args = append(args, *spec.MaxPrice)
+	}
+
+	// id is UUIDv7 stored as text, so ordering by it keeps products in creation order and gives a stable cursor
+	sqlQuery := `SELECT ` + productColumns + ` FROM products
+		WHERE ` + strings.Join(conditions, " AND ") + ` ORDER BY id LIMIT ?`
+	args = append(args, limit+1)
+
+	var rows []productRow
+	err := p.client.SelectContext(ctx, &rows, sqlQuery, args...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	page := &data.ProductPage{}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		page.NextCursor = encodeCursor(uuid.MustParse(rows[len(rows)-1].ID))
+	}
+
+	page.Products = make([]data.Product, 0, len(rows))
+	for _, row := range rows {
+		page.Products = append(page.Products, toProduct(row))
+	}
+	return page, nil
+}
+
+func toProduct(row productRow) data.Product {
+	product := data.Product{
+		ID:               uuid.MustParse(row.ID),
+		Name:             row.Name,
+		Price:            row.Price,
+		Quantity:         row.Quantity,
+		ReservedQuantity: row.ReservedQuantity,
+		CreatedAt:        row.CreatedAt,
+		UpdatedAt:        row.UpdatedAt,
+	}
+	if row.DeletedAt.Valid {
+		product.DeletedAt = &row.DeletedAt.V
+	}
+	return product
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func encodeCursor(lastProductID uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString(lastProductID[:])
+}
+
+func decodeCursor(cursor string) (uuid.UUID, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return uuid.Nil, errors.WithStack(query.ErrInvalidCursor)
+	}
+	id, err := uuid.FromBytes(b)
+	if err != nil {
+		return uuid.Nil, errors.WithStack(query.ErrInvalidCursor)
+	}
+	return id, nil
+}
@@ -1,22 +1,27 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"time"
 
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
 
 	"product/pkg/product/domain/model"
 )
 
-type productRepository struct {
-	db *sqlx.DB
+func NewProductRepository(ctx context.Context, client mysql.ClientContext) model.ProductRepository {
+	return &productRepository{
+		ctx:    ctx,
+		client: client,
+	}
 }
 
-func NewProductRepository(db *sqlx.DB) model.ProductRepository {
-	return &productRepository{db: db}
+type productRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
 }
 
 func (r *productRepository) NextID() (uuid.UUID, error) {
@@ -24,14 +29,13 @@ func (r *productRepository) NextID() (uuid.UUID, error) {
 }
 
 func (r *productRepository) Store(p *model.Product) error {
-	// Обновлен запрос: добавлено поле deleted_at
-	_, err := r.db.Exec(`
+	// quantity is not updated, concurrent reservations change it atomically
+	_, err := r.client.ExecContext(r.ctx, `
 		INSERT INTO products (id, name, price, quantity, created_at, updated_at, deleted_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?)
 		ON DUPLICATE KEY UPDATE
 			name=VALUES(name),
 			price=VALUES(price),
-			quantity=VALUES(quantity),
 			updated_at=VALUES(updated_at),
 			deleted_at=VALUES(deleted_at)
 	`, p.ID.String(), p.Name, p.Price, p.Quantity, p.CreatedAt, p.UpdatedAt, toSQLNullTime(p.DeletedAt))
@@ -51,9 +55,9 @@ func (r *productRepository) Find(id uuid.UUID) (*model.Product, error) {
 	}
 
 	// Обновлен запрос: добавлено поле deleted_at
-	err := r.db.QueryRowx(`
-		SELECT id, name, price, quantity, created_at, updated_at, deleted_at 
-		FROM products WHERE id = ?`, id.String()).StructScan(&row)
+	err := r.client.GetContext(r.ctx, &row, `
+		SELECT id, name, price, quantity, created_at, updated_at, deleted_at
+		FROM products WHERE id = ?`, id.String())
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -76,14 +80,14 @@ func (r *productRepository) Find(id uuid.UUID) (*model.Product, error) {
 // --- ДОБАВЛЕН МЕТОД REMOVE ---
 func (r *productRepository) Remove(id uuid.UUID) error {
 	// Реализуем Soft Delete
-	_, err := r.db.Exec(`UPDATE products SET deleted_at = ? WHERE id = ?`, time.Now(), id.String())
+	_, err := r.client.ExecContext(r.ctx, `UPDATE products SET deleted_at = ? WHERE id = ?`, time.Now(), id.String())
 	return errors.WithStack(err)
 }
 
 // -----------------------------
 
 func (r *productRepository) ReserveStock(id uuid.UUID, quantity int) error {
-	res, err := r.db.Exec(`UPDATE products SET quantity = quantity - ? WHERE id = ? AND quantity >= ?`, quantity, id.String(), quantity)
+	res, err := r.client.ExecContext(r.ctx, `UPDATE products SET quantity = quantity - ? WHERE id = ? AND quantity >= ?`, quantity, id.String(), quantity)
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -95,10 +99,29 @@ func (r *productRepository) ReserveStock(id uuid.UUID, quantity int) error {
 }
 
 func (r *productRepository) ReleaseStock(id uuid.UUID, quantity int) error {
-	_, err := r.db.Exec(`UPDATE products SET quantity = quantity + ? WHERE id = ?`, quantity, id.String())
+	_, err := r.client.ExecContext(r.ctx, `UPDATE products SET quantity = quantity + ? WHERE id = ?`, quantity, id.String())
 	return errors.WithStack(err)
 }
 
+func (r *productRepository) AdjustStock(id uuid.UUID, delta int) error {
+	res, err := r.client.ExecContext(
+		r.ctx,
+		`UPDATE products SET quantity = quantity + ?, updated_at = ? WHERE id = ? AND quantity + ? >= 0`,
+		delta, time.Now(), id.String(), delta,
+	)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if rows == 0 {
+		return model.ErrInsufficientStock
+	}
+	return nil
+}
+
 // Вспомогательные функции для конвертации *time.Time <-> sql.NullTime
 
 func toSQLNullTime(t *time.Time) sql.NullTime {
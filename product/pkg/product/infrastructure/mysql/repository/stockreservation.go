@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"product/pkg/product/domain/model"
+)
+
+func NewStockReservationRepository(ctx context.Context, client mysql.ClientContext) model.StockReservationRepository {
+	return &stockReservationRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type stockReservationRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+type stockReservationRow struct {
+	OrderID   string    `db:"order_id"`
+	ProductID string    `db:"product_id"`
+	Quantity  int       `db:"quantity"`
+	State     int       `db:"state"`
+	ExpiresAt time.Time `db:"expires_at"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+const stockReservationColumns = `order_id, product_id, quantity, state, expires_at, created_at, updated_at`
+
+func (r *stockReservationRepository) Store(reservation *model.StockReservation) error {
+	_, err := r.client.ExecContext(r.ctx,
+		`
+		INSERT INTO stock_reservations (`+stockReservationColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			state=VALUES(state),
+			updated_at=VALUES(updated_at)
+		`,
+		reservation.OrderID.String(),
+		reservation.ProductID.String(),
+		reservation.Quantity,
+		int(reservation.State),
+		reservation.ExpiresAt,
+		reservation.CreatedAt,
+		reservation.UpdatedAt,
+	)
+	return errors.WithStack(err)
+}
+
+func (r *stockReservationRepository) Find(orderID, productID uuid.UUID) (*model.StockReservation, error) {
+	var row stockReservationRow
+	err := r.client.GetContext(
+		r.ctx,
+		&row,
+		`SELECT `+stockReservationColumns+` FROM stock_reservations WHERE order_id = ? AND product_id = ? FOR UPDATE`,
+		orderID.String(),
+		productID.String(),
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(model.ErrStockReservationNotFound)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	reservation, err := toStockReservation(row)
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+func (r *stockReservationRepository) FindByOrderID(orderID uuid.UUID) ([]model.StockReservation, error) {
+	var rows []stockReservationRow
+	err := r.client.SelectContext(
+		r.ctx,
+		&rows,
+		`SELECT `+stockReservationColumns+` FROM stock_reservations WHERE order_id = ? ORDER BY product_id FOR UPDATE`,
+		orderID.String(),
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return toStockReservations(rows)
+}
+
+func (r *stockReservationRepository) FindExpired(before time.Time, limit int) ([]model.StockReservation, error) {
+	var rows []stockReservationRow
+	err := r.client.SelectContext(
+		r.ctx,
+		&rows,
+		`SELECT `+stockReservationColumns+` FROM stock_reservations WHERE state = ? AND expires_at < ? ORDER BY expires_at LIMIT ?`,
+		int(model.StockReserved),
+		before,
+		limit,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return toStockReservations(rows)
+}
+
+func (r *stockReservationRepository) StoreReturn(stockReturn *model.StockReturn) error {
+	_, err := r.client.ExecContext(r.ctx,
+		`
+		INSERT INTO stock_returns (return_id, order_id, product_id, quantity, created_at)
+		VALUES (?, ?, ?, ?, ?)
+		`,
+		stockReturn.ReturnID.String(),
+		stockReturn.OrderID.String(),
+		stockReturn.ProductID.String(),
+		stockReturn.Quantity,
+		stockReturn.CreatedAt,
+	)
+	return errors.WithStack(err)
+}
+
+func (r *stockReservationRepository) HasReturn(returnID, productID uuid.UUID) (bool, error) {
+	var count int
+	err := r.client.GetContext(
+		r.ctx,
+		&count,
+		`SELECT COUNT(*) FROM stock_returns WHERE return_id = ? AND product_id = ?`,
+		returnID.String(),
+		productID.String(),
+	)
+	return count > 0, errors.WithStack(err)
+}
+
+func toStockReservations(rows []stockReservationRow) ([]model.StockReservation, error) {
+	reservations := make([]model.StockReservation, 0, len(rows))
+	for _, row := range rows {
+		reservation, err := toStockReservation(row)
+		if err != nil {
+			return nil, err
+		}
+		reservations = append(reservations, reservation)
+	}
+	return reservations, nil
+}
+
+func toStockReservation(row stockReservationRow) (model.StockReservation, error) {
+	orderID, err := uuid.Parse(row.OrderID)
+	if err != nil {
+		return model.StockReservation{}, errors.WithStack(err)
+	}
+	productID, err := uuid.Parse(row.ProductID)
+	if err != nil {
+		return model.StockReservation{}, errors.WithStack(err)
+	}
+	return model.StockReservation{
+		OrderID:   orderID,
+		ProductID: productID,
+		Quantity:  row.Quantity,
+		State:     model.StockReservationState(row.State),
+		ExpiresAt: row.ExpiresAt,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}, nil
+}
@@ -3,10 +3,16 @@ package temporal
 import (
 	"context"
 	"product/pkg/product/app/service"
+	"product/pkg/product/domain/model"
 
 	"github.com/google/uuid"
 )
 
+type ReserveProductsItem struct {
+	ProductID string
+	Quantity  int
+}
+
 type ProductActivities struct {
 	svc *service.ProductService
 }
@@ -15,18 +21,134 @@ func NewProductActivities(svc *service.ProductService) *ProductActivities {
 	return &ProductActivities{svc: svc}
 }
 
+// ReserveProduct takes quantity off the product stock without a reservation,
+// it is kept with its arguments for workflows started before the reservation ledger
 func (a *ProductActivities) ReserveProduct(ctx context.Context, productID string, quantity int) error {
 	id, err := uuid.Parse(productID)
 	if err != nil {
 		return err
 	}
-	return a.svc.Reserve(ctx, id, quantity)
+	return a.svc.AdjustStock(ctx, id, -quantity)
 }
 
+// ReleaseProduct puts quantity back to the product stock without a reservation,
+// it is kept with its arguments for workflows started before the reservation ledger
 func (a *ProductActivities) ReleaseProduct(ctx context.Context, productID string, quantity int) error {
 	id, err := uuid.Parse(productID)
 	if err != nil {
 		return err
 	}
-	return a.svc.Release(ctx, id, quantity)
+	return a.svc.AdjustStock(ctx, id, quantity)
+}
+
+// ReserveOrderProduct holds stock of the product for the order until ConfirmProduct, ReleaseOrderProduct or expiry,
+// retried call does not reserve twice
+func (a *ProductActivities) ReserveOrderProduct(ctx context.Context, orderID, productID string, quantity int) error {
+	oid, pid, err := parseReservationKey(orderID, productID)
+	if err != nil {
+		return err
+	}
+	return a.svc.ReserveStock(ctx, oid, pid, quantity)
+}
+
+// ReleaseOrderProduct puts stock reserved for the order back, retried call does not restock twice
+func (a *ProductActivities) ReleaseOrderProduct(ctx context.Context, orderID, productID string) error {
+	oid, pid, err := parseReservationKey(orderID, productID)
+	if err != nil {
+		return err
+	}
+	return a.svc.ReleaseStock(ctx, oid, pid)
+}
+
+// RestockReturnedProduct puts quantity returned by the order return back, retried call does not restock twice
+func (a *ProductActivities) RestockReturnedProduct(ctx context.Context, orderID, productID, returnID string, quantity int) error {
+	oid, pid, err := parseReservationKey(orderID, productID)
+	if err != nil {
+		return err
+	}
+	rid, err := uuid.Parse(returnID)
+	if err != nil {
+		return err
+	}
+	return a.svc.ReleaseReturnedStock(ctx, oid, pid, rid, quantity)
+}
+
+func (a *ProductActivities) ConfirmProduct(ctx context.Context, orderID, productID string) error {
+	oid, pid, err := parseReservationKey(orderID, productID)
+	if err != nil {
+		return err
+	}
+	return a.svc.ConfirmStock(ctx, oid, pid)
+}
+
+// ReserveProducts reserves all items for the order at once, retried call does not reserve twice
+func (a *ProductActivities) ReserveProducts(ctx context.Context, orderID string, items []ReserveProductsItem) error {
+	oid, lines, err := parseReservationLines(orderID, items)
+	if err != nil {
+		return err
+	}
+	return a.svc.ReserveProducts(ctx, oid, lines)
+}
+
+// ReserveAvailableProducts reserves items in stock for the order and returns reserved quantities, items out of stock are skipped
+func (a *ProductActivities) ReserveAvailableProducts(ctx context.Context, orderID string, items []ReserveProductsItem) ([]ReserveProductsItem, error) {
+	oid, lines, err := parseReservationLines(orderID, items)
+	if err != nil {
+		return nil, err
+	}
+	reserved, err := a.svc.ReserveAvailableProducts(ctx, oid, lines)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]ReserveProductsItem, len(reserved))
+	for i, line := range reserved {
+		result[i] = ReserveProductsItem{ProductID: line.ProductID.String(), Quantity: line.Quantity}
+	}
+	return result, nil
+}
+
+// ReleaseProducts puts back all stock reserved for the order, retried call does not restock twice
+func (a *ProductActivities) ReleaseProducts(ctx context.Context, orderID string) error {
+	id, err := uuid.Parse(orderID)
+	if err != nil {
+		return err
+	}
+	return a.svc.ReleaseProducts(ctx, id)
+}
+
+// ConfirmProducts keeps stock reserved for the paid order, so its reservations do not expire
+func (a *ProductActivities) ConfirmProducts(ctx context.Context, orderID string) error {
+	id, err := uuid.Parse(orderID)
+	if err != nil {
+		return err
+	}
+	return a.svc.ConfirmProducts(ctx, id)
+}
+
+func parseReservationLines(orderID string, items []ReserveProductsItem) (uuid.UUID, []model.ReservationLine, error) {
+	oid, err := uuid.Parse(orderID)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	lines := make([]model.ReservationLine, len(items))
+	for i, item := range items {
+		id, err := uuid.Parse(item.ProductID)
+		if err != nil {
+			return uuid.Nil, nil, err
+		}
+		lines[i] = model.ReservationLine{ProductID: id, Quantity: item.Quantity}
+	}
+	return oid, lines, nil
+}
+
+func parseReservationKey(orderID, productID string) (uuid.UUID, uuid.UUID, error) {
+	oid, err := uuid.Parse(orderID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	pid, err := uuid.Parse(productID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return oid, pid, nil
 }
@@ -5,6 +5,8 @@ import (
 
 	"github.com/pkg/errors"
 	"google.golang.org/grpc/codes"
+
+	"product/pkg/product/domain/model"
 )
 
 type errorSet map[error]struct{}
@@ -22,9 +24,19 @@ func (s errorSet) Has(err error) bool {
 	return ok
 }
 
-var badRequestErrorCodes = newErrorSet()
+var badRequestErrorCodes = newErrorSet(
+	model.ErrInvalidName,
+	model.ErrInvalidPrice,
+	model.ErrInvalidQuantity,
+)
 
-var notFoundErrorCodes = newErrorSet()
+var notFoundErrorCodes = newErrorSet(
+	model.ErrProductNotFound,
+)
+
+var failedPreconditionErrorCodes = newErrorSet(
+	model.ErrInsufficientStock,
+)
 
 var unauthorizedErrorCodes = newErrorSet()
 
@@ -43,6 +55,8 @@ func getGRPCCode(err error) codes.Code {
 		return codes.InvalidArgument
 	case isNotFoundError(cause):
 		return codes.NotFound
+	case isFailedPreconditionError(cause):
+		return codes.FailedPrecondition
 	case isUnauthorizedError(cause):
 		return codes.Unauthenticated
 	case isPermissionDeniedError(cause):
@@ -84,6 +98,10 @@ func isNotFoundError(cause error) bool {
 	return notFoundErrorCodes.Has(cause)
 }
 
+func isFailedPreconditionError(cause error) bool {
+	return failedPreconditionErrorCodes.Has(cause)
+}
+
 func isUnauthorizedError(cause error) bool {
 	return unauthorizedErrorCodes.Has(cause)
 }
@@ -2,15 +2,35 @@ package transport
 
 import (
 	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
 
 	api "product/api/server/productinternal"
+	"product/pkg/product/app/data"
+	appquery "product/pkg/product/app/query"
+	appservice "product/pkg/product/app/service"
 )
 
-func NewInternalAPI() api.ProductInternalServiceServer {
-	return &internalAPI{}
+func NewInternalAPI(
+	productService *appservice.ProductService,
+	productQueryService appquery.ProductQueryService,
+) api.ProductInternalServiceServer {
+	return &internalAPI{
+		productService:      productService,
+		productQueryService: productQueryService,
+	}
 }
 
 type internalAPI struct {
+	productService      *appservice.ProductService
+	productQueryService appquery.ProductQueryService
+
+	api.UnimplementedProductInternalServiceServer
 }
 
 func (i *internalAPI) Ping(_ context.Context, _ *api.PingRequest) (*api.PingResponse, error) {
@@ -18,3 +38,108 @@ func (i *internalAPI) Ping(_ context.Context, _ *api.PingRequest) (*api.PingResp
 		Message: "pong",
 	}, nil
 }
+
+func (i *internalAPI) FindProduct(ctx context.Context, request *api.FindProductRequest) (*api.FindProductResponse, error) {
+	productID, err := uuid.Parse(request.ProductID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.ProductID)
+	}
+
+	product, err := i.productQueryService.FindProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toFindProductResponse(product), nil
+}
+
+func (i *internalAPI) CreateProduct(ctx context.Context, request *api.CreateProductRequest) (*api.CreateProductResponse, error) {
+	productID, err := i.productService.CreateProduct(ctx, request.Name, request.Price, int(request.Quantity))
+	if err != nil {
+		return nil, err
+	}
+	return &api.CreateProductResponse{
+		ProductID: productID.String(),
+	}, nil
+}
+
+func (i *internalAPI) UpdateProduct(ctx context.Context, request *api.UpdateProductRequest) (*emptypb.Empty, error) {
+	productID, err := uuid.Parse(request.ProductID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.ProductID)
+	}
+
+	err = i.productService.UpdateProduct(ctx, productID, request.Name, request.Price)
+	if err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (i *internalAPI) ListProducts(ctx context.Context, request *api.ListProductsRequest) (*api.ListProductsResponse, error) {
+	page, err := i.productQueryService.ListProducts(ctx, data.ListProductsSpec{
+		Name:     request.Name,
+		MinPrice: request.MinPrice,
+		MaxPrice: request.MaxPrice,
+		Limit:    int(request.Limit),
+		Cursor:   request.Cursor,
+	})
+	if err != nil {
+		if errors.Is(err, appquery.ErrInvalidCursor) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid cursor %q", request.Cursor)
+		}
+		return nil, err
+	}
+
+	response := &api.ListProductsResponse{
+		Products:   make([]*api.FindProductResponse, 0, len(page.Products)),
+		NextCursor: page.NextCursor,
+	}
+	for _, product := range page.Products {
+		response.Products = append(response.Products, toFindProductResponse(&product))
+	}
+	return response, nil
+}
+
+func (i *internalAPI) RemoveProduct(ctx context.Context, request *api.RemoveProductRequest) (*emptypb.Empty, error) {
+	productID, err := uuid.Parse(request.ProductID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.ProductID)
+	}
+
+	err = i.productService.RemoveProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (i *internalAPI) AdjustStock(ctx context.Context, request *api.AdjustStockRequest) (*emptypb.Empty, error) {
+	productID, err := uuid.Parse(request.ProductID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.ProductID)
+	}
+
+	err = i.productService.AdjustStock(ctx, productID, int(request.Delta))
+	if err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func toFindProductResponse(product *data.Product) *api.FindProductResponse {
+	response := &api.FindProductResponse{
+		ProductID:        product.ID.String(),
+		Name:             product.Name,
+		Price:            product.Price,
+		Quantity:         int32(product.Quantity),         // #nosec G115
+		ReservedQuantity: int32(product.ReservedQuantity), // #nosec G115
+		CreatedAt:        product.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:        product.UpdatedAt.Format(time.RFC3339),
+	}
+	if product.DeletedAt != nil {
+		deletedAtStr := product.DeletedAt.Format(time.RFC3339)
+		response.DeletedAt = &deletedAtStr
+	}
+	return response
+}
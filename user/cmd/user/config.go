@@ -18,6 +18,8 @@ type Service struct {
 
 	GRPCAddress string `envconfig:"grpc_address" default:":8081"`
 	HTTPAddress string `envconfig:"http_address" default:":8082"`
+
+	IdempotencyKeyRetention time.Duration `envconfig:"idempotency_key_retention" default:"24h"`
 }
 
 type Database struct {
@@ -80,7 +80,8 @@ func service(logger logging.Logger) *cli.Command {
 
 			userPublicAPIServer := transport.NewUserInternalAPI(
 				query.NewUserQueryService(databaseConnector.TransactionalClient()),
-				appservice.NewUserService(uow, luow, eventDispatcher),
+				appservice.NewUserService(uow, luow, eventDispatcher, cnf.Service.IdempotencyKeyRetention),
+				appservice.NewAddressService(uow),
 			)
 
 			errGroup := errgroup.Group{}
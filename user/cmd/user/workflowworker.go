@@ -64,7 +64,7 @@ func workflowWorker(logger logging.Logger) *cli.Command {
 
 			errGroup := errgroup.Group{}
 			errGroup.Go(func() error {
-				w := worker.NewWorker(temporalClient, appservice.NewUserService(uow, luow, eventDispatcher))
+				w := worker.NewWorker(temporalClient, appservice.NewUserService(uow, luow, eventDispatcher, cnf.Service.IdempotencyKeyRetention))
 				logger.Info("Worker created, starting...")
 				err := w.Run(worker.InterruptChannel())
 				if err != nil {
@@ -1,6 +1,10 @@
 package data
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type User struct {
 	UserID   uuid.UUID
@@ -15,3 +19,15 @@ type UserUpdate struct {
 	Email    *string
 	Telegram *string
 }
+
+type Address struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Recipient  string
+	Phone      string
+	Country    string
+	City       string
+	Street     string
+	PostalCode string
+	CreatedAt  time.Time
+}
@@ -10,4 +10,6 @@ import (
 
 type UserQueryService interface {
 	FindUser(ctx context.Context, userID uuid.UUID) (*appmodel.User, error)
+	FindAddress(ctx context.Context, addressID uuid.UUID) (*appmodel.Address, error)
+	ListAddresses(ctx context.Context, userID uuid.UUID) ([]appmodel.Address, error)
 }
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	appdata "user/pkg/user/app/data"
+	"user/pkg/user/domain/model"
+	"user/pkg/user/domain/service"
+)
+
+type AddressService interface {
+	AddAddress(ctx context.Context, userID uuid.UUID, address appdata.Address) (uuid.UUID, error)
+	RemoveAddress(ctx context.Context, userID, addressID uuid.UUID) error
+}
+
+func NewAddressService(uow UnitOfWork) AddressService {
+	return &addressService{
+		uow: uow,
+	}
+}
+
+type addressService struct {
+	uow UnitOfWork
+}
+
+func (s *addressService) AddAddress(ctx context.Context, userID uuid.UUID, address appdata.Address) (uuid.UUID, error) {
+	var addressID uuid.UUID
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		var err error
+		addressID, err = s.domainService(ctx, provider).AddAddress(userID, model.Address{
+			Recipient:  address.Recipient,
+			Phone:      address.Phone,
+			Country:    address.Country,
+			City:       address.City,
+			Street:     address.Street,
+			PostalCode: address.PostalCode,
+		})
+		return err
+	})
+	return addressID, err
+}
+
+func (s *addressService) RemoveAddress(ctx context.Context, userID, addressID uuid.UUID) error {
+	return s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider).RemoveAddress(userID, addressID)
+	})
+}
+
+func (s *addressService) domainService(ctx context.Context, provider RepositoryProvider) service.AddressService {
+	return service.NewAddressService(provider.UserRepository(ctx), provider.AddressRepository(ctx))
+}
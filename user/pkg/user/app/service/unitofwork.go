@@ -3,11 +3,14 @@ package service
 import (
 	"context"
 
+	"user/pkg/common/idempotency"
 	"user/pkg/user/domain/model"
 )
 
 type RepositoryProvider interface {
 	UserRepository(ctx context.Context) model.UserRepository
+	AddressRepository(ctx context.Context) model.AddressRepository
+	IdempotencyRepository(ctx context.Context) idempotency.Repository
 }
 
 type LockableUnitOfWork interface {
@@ -2,18 +2,22 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 
 	"user/pkg/common/domain"
+	"user/pkg/common/idempotency"
 	appdata "user/pkg/user/app/data"
 	"user/pkg/user/domain/model"
 	"user/pkg/user/domain/service"
 )
 
 type UserService interface {
-	CreateUser(ctx context.Context, user appdata.User) (uuid.UUID, error)
+	// CreateUser returns ID of the user created by the first call with the same non-empty idempotencyKey
+	CreateUser(ctx context.Context, user appdata.User, idempotencyKey string) (uuid.UUID, error)
 	UpdateUser(ctx context.Context, userID uuid.UUID, update appdata.UserUpdate) error
 	BlockUser(ctx context.Context, userID uuid.UUID) error
 	DeleteUser(ctx context.Context, userID uuid.UUID) error
@@ -24,22 +28,38 @@ func NewUserService(
 	uow UnitOfWork,
 	luow LockableUnitOfWork,
 	eventDispatcher outbox.EventDispatcher[outbox.Event],
+	idempotencyKeyRetention time.Duration,
 ) UserService {
 	return &userService{
-		uow:             uow,
-		luow:            luow,
-		eventDispatcher: eventDispatcher,
+		uow:                     uow,
+		luow:                    luow,
+		eventDispatcher:         eventDispatcher,
+		idempotencyKeyRetention: idempotencyKeyRetention,
 	}
 }
 
 type userService struct {
-	uow             UnitOfWork
-	luow            LockableUnitOfWork
-	eventDispatcher outbox.EventDispatcher[outbox.Event]
+	uow                     UnitOfWork
+	luow                    LockableUnitOfWork
+	eventDispatcher         outbox.EventDispatcher[outbox.Event]
+	idempotencyKeyRetention time.Duration
 }
 
-func (s *userService) CreateUser(ctx context.Context, user appdata.User) (uuid.UUID, error) {
+const createUserIdempotencyScope = "create_user"
+
+func (s *userService) CreateUser(ctx context.Context, user appdata.User, idempotencyKey string) (uuid.UUID, error) {
+	var (
+		fingerprint string
+		err         error
+	)
 	var lockNames []string
+	if idempotencyKey != "" {
+		fingerprint, err = idempotency.Fingerprint(user)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		lockNames = append(lockNames, idempotency.Lock(createUserIdempotencyScope, idempotencyKey))
+	}
 	lockNames = append(lockNames, userLoginLock(user.Login))
 	if user.Email != nil {
 		lockNames = append(lockNames, userEmailLock(*user.Email))
@@ -49,7 +69,18 @@ func (s *userService) CreateUser(ctx context.Context, user appdata.User) (uuid.U
 	}
 
 	var userID uuid.UUID
-	err := s.luow.Execute(ctx, lockNames, func(provider RepositoryProvider) error {
+	err = s.luow.Execute(ctx, lockNames, func(provider RepositoryProvider) error {
+		if idempotencyKey != "" {
+			response, ok, err := idempotency.Replay(provider.IdempotencyRepository(ctx), createUserIdempotencyScope, idempotencyKey, fingerprint, s.idempotencyKeyRetention)
+			if err != nil {
+				return err
+			}
+			if ok {
+				userID, err = uuid.Parse(response)
+				return errors.WithStack(err)
+			}
+		}
+
 		domainService := s.domainService(ctx, provider.UserRepository(ctx))
 		uID, err := domainService.CreateUser(user.Login)
 		if err != nil {
@@ -57,6 +88,13 @@ func (s *userService) CreateUser(ctx context.Context, user appdata.User) (uuid.U
 		}
 		userID = uID
 
+		if idempotencyKey != "" {
+			err = idempotency.Remember(provider.IdempotencyRepository(ctx), createUserIdempotencyScope, idempotencyKey, fingerprint, userID.String())
+			if err != nil {
+				return err
+			}
+		}
+
 		// Собираем все обновления в одну структуру
 		updateParams := struct {
 			Status   *model.UserStatus
@@ -86,8 +124,8 @@ func (s *userService) CreateUser(ctx context.Context, user appdata.User) (uuid.U
 }
 
 func (s *userService) UpdateUser(ctx context.Context, userID uuid.UUID, update appdata.UserUpdate) error {
+	// Locks keep email and telegram unique, concurrent updates of the user itself are detected by version
 	var lockNames []string
-	lockNames = append(lockNames, userLock(userID))
 	if update.Email != nil && *update.Email != "" {
 		lockNames = append(lockNames, userEmailLock(*update.Email))
 	}
@@ -95,15 +133,21 @@ func (s *userService) UpdateUser(ctx context.Context, userID uuid.UUID, update a
 		lockNames = append(lockNames, userTelegramLock(*update.Telegram))
 	}
 
-	return s.luow.Execute(ctx, lockNames, func(provider RepositoryProvider) error {
+	updateUser := func(provider RepositoryProvider) error {
 		domainService := s.domainService(ctx, provider.UserRepository(ctx))
 		params := s.convertToUpdateParams(update)
 		return domainService.UpdateUser(userID, params) // ← только публикация события
+	}
+	return retryOnConflict(func() error {
+		if len(lockNames) == 0 {
+			return s.uow.Execute(ctx, updateUser)
+		}
+		return s.luow.Execute(ctx, lockNames, updateUser)
 	})
 }
 
 func (s *userService) BlockUser(ctx context.Context, userID uuid.UUID) error {
-	return s.luow.Execute(ctx, []string{userLock(userID)}, func(provider RepositoryProvider) error {
+	return s.executeWithRetry(ctx, func(provider RepositoryProvider) error {
 		domainService := s.domainService(ctx, provider.UserRepository(ctx))
 		status := model.Blocked
 		return domainService.UpdateUser(userID, struct {
@@ -115,14 +159,14 @@ func (s *userService) BlockUser(ctx context.Context, userID uuid.UUID) error {
 }
 
 func (s *userService) DeleteUser(ctx context.Context, userID uuid.UUID) error {
-	return s.luow.Execute(ctx, []string{userLock(userID)}, func(provider RepositoryProvider) error {
+	return s.executeWithRetry(ctx, func(provider RepositoryProvider) error {
 		return s.domainService(ctx, provider.UserRepository(ctx)).DeleteUser(userID, false)
 	})
 }
 
 func (s *userService) FindUser(ctx context.Context, userID uuid.UUID) (appdata.User, error) {
 	var user appdata.User
-	err := s.luow.Execute(ctx, []string{userLock(userID)}, func(provider RepositoryProvider) error {
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
 		domainUser, err := provider.UserRepository(ctx).Find(model.FindSpec{UserID: &userID})
 		if err != nil {
 			return err
@@ -161,12 +205,28 @@ func (s *userService) domainEventDispatcher(ctx context.Context) domain.EventDis
 	}
 }
 
-const baseUserLock = "user_"
+// executeWithRetry runs f in a new transaction again when the user is changed concurrently
+func (s *userService) executeWithRetry(ctx context.Context, f func(provider RepositoryProvider) error) error {
+	return retryOnConflict(func() error {
+		return s.uow.Execute(ctx, f)
+	})
+}
 
-func userLock(id uuid.UUID) string {
-	return baseUserLock + id.String()
+const maxConflictRetries = 3
+
+func retryOnConflict(f func() error) error {
+	var err error
+	for range maxConflictRetries {
+		err = f()
+		if !errors.Is(err, model.ErrUserVersionConflict) {
+			return err
+		}
+	}
+	return err
 }
 
+const baseUserLock = "user_"
+
 func userLoginLock(login string) string {
 	return baseUserLock + "login_" + login
 }
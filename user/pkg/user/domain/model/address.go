@@ -0,0 +1,30 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrAddressNotFound = errors.New("address not found")
+
+// Address is a shipping address saved by the user, orders take a snapshot of it
+type Address struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Recipient  string
+	Phone      string
+	Country    string
+	City       string
+	Street     string
+	PostalCode string
+	CreatedAt  time.Time
+}
+
+type AddressRepository interface {
+	NextID() (uuid.UUID, error)
+	Store(address Address) error
+	Find(id uuid.UUID) (*Address, error)
+	Remove(id uuid.UUID) error
+}
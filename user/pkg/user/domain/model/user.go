@@ -12,6 +12,8 @@ var (
 	ErrUserLoginAlreadyUsed    = errors.New("user login already used")
 	ErrUserEmailAlreadyUsed    = errors.New("user email already used")
 	ErrUserTelegramAlreadyUsed = errors.New("user telegram already used")
+	// ErrUserVersionConflict means the user was changed concurrently since it was found
+	ErrUserVersionConflict = errors.New("user version conflict")
 )
 
 type UserStatus int
@@ -31,6 +33,8 @@ type User struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt *time.Time
+	// Version is zero for a new user, Store updates only the version the user was found with
+	Version int
 }
 
 type FindSpec struct {
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"user/pkg/user/domain/model"
+)
+
+var ErrInvalidAddress = errors.New("address country, city and street are required")
+
+type AddressService interface {
+	AddAddress(userID uuid.UUID, address model.Address) (uuid.UUID, error)
+	// RemoveAddress returns model.ErrAddressNotFound when the address belongs to another user
+	RemoveAddress(userID, addressID uuid.UUID) error
+}
+
+func NewAddressService(
+	userRepository model.UserRepository,
+	addressRepository model.AddressRepository,
+) AddressService {
+	return &addressService{
+		userRepository:    userRepository,
+		addressRepository: addressRepository,
+	}
+}
+
+type addressService struct {
+	userRepository    model.UserRepository
+	addressRepository model.AddressRepository
+}
+
+func (a addressService) AddAddress(userID uuid.UUID, address model.Address) (uuid.UUID, error) {
+	if strings.TrimSpace(address.Country) == "" ||
+		strings.TrimSpace(address.City) == "" ||
+		strings.TrimSpace(address.Street) == "" {
+		return uuid.Nil, ErrInvalidAddress
+	}
+
+	user, err := a.userRepository.Find(model.FindSpec{UserID: &userID})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if user.Status == model.Deleted {
+		return uuid.Nil, model.ErrUserNotFound
+	}
+
+	addressID, err := a.addressRepository.NextID()
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	address.ID = addressID
+	address.UserID = userID
+	address.CreatedAt = time.Now()
+	return addressID, a.addressRepository.Store(address)
+}
+
+func (a addressService) RemoveAddress(userID, addressID uuid.UUID) error {
+	address, err := a.addressRepository.Find(addressID)
+	if err != nil {
+		return err
+	}
+	if address.UserID != userID {
+		return model.ErrAddressNotFound
+	}
+	return a.addressRepository.Remove(addressID)
+}
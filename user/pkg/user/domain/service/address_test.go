@@ -0,0 +1,98 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"user/pkg/user/domain/model"
+)
+
+type MockAddressRepository struct {
+	mock.Mock
+}
+
+func (m *MockAddressRepository) NextID() (uuid.UUID, error) {
+	args := m.Called()
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockAddressRepository) Store(address model.Address) error {
+	args := m.Called(address)
+	return args.Error(0)
+}
+
+func (m *MockAddressRepository) Find(id uuid.UUID) (*model.Address, error) {
+	args := m.Called(id)
+	if address, ok := args.Get(0).(*model.Address); ok {
+		return address, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAddressRepository) Remove(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func TestAddAddress_Success(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	addressRepo := new(MockAddressRepository)
+
+	userID := uuid.New()
+	addressID := uuid.New()
+
+	userRepo.On("Find", model.FindSpec{UserID: &userID}).Return(&model.User{UserID: userID, Status: model.Active}, nil)
+	addressRepo.On("NextID").Return(addressID, nil)
+	addressRepo.On("Store", mock.MatchedBy(func(a model.Address) bool {
+		return a.ID == addressID && a.UserID == userID && a.City == "Moscow" && !a.CreatedAt.IsZero()
+	})).Return(nil)
+
+	svc := NewAddressService(userRepo, addressRepo)
+	id, err := svc.AddAddress(userID, model.Address{Country: "Russia", City: "Moscow", Street: "Tverskaya 1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, addressID, id)
+	addressRepo.AssertExpectations(t)
+}
+
+func TestAddAddress_Invalid(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	addressRepo := new(MockAddressRepository)
+
+	svc := NewAddressService(userRepo, addressRepo)
+	_, err := svc.AddAddress(uuid.New(), model.Address{Country: "Russia", City: " "})
+
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+	userRepo.AssertNotCalled(t, "Find", mock.Anything)
+	addressRepo.AssertNotCalled(t, "Store", mock.Anything)
+}
+
+func TestAddAddress_DeletedUser(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	addressRepo := new(MockAddressRepository)
+
+	userID := uuid.New()
+	userRepo.On("Find", model.FindSpec{UserID: &userID}).Return(&model.User{UserID: userID, Status: model.Deleted}, nil)
+
+	svc := NewAddressService(userRepo, addressRepo)
+	_, err := svc.AddAddress(userID, model.Address{Country: "Russia", City: "Moscow", Street: "Tverskaya 1"})
+
+	assert.ErrorIs(t, err, model.ErrUserNotFound)
+	addressRepo.AssertNotCalled(t, "Store", mock.Anything)
+}
+
+func TestRemoveAddress_OtherUser(t *testing.T) {
+	addressRepo := new(MockAddressRepository)
+
+	addressID := uuid.New()
+	addressRepo.On("Find", addressID).Return(&model.Address{ID: addressID, UserID: uuid.New()}, nil)
+
+	svc := NewAddressService(new(MockUserRepository), addressRepo)
+	err := svc.RemoveAddress(uuid.New(), addressID)
+
+	assert.ErrorIs(t, err, model.ErrAddressNotFound)
+	addressRepo.AssertNotCalled(t, "Remove", mock.Anything)
+}
@@ -212,18 +212,15 @@ func (u userService) DeleteUser(userID uuid.UUID, hard bool) error {
 		return err
 	}
 
+	currentTime := time.Now()
 	if hard {
 		err = u.userRepository.HardDelete(userID)
-		if err != nil {
-			return err
-		}
+	} else {
+		user.Status = model.Deleted
+		user.UpdatedAt = currentTime
+		user.DeletedAt = &currentTime
+		err = u.userRepository.Store(*user)
 	}
-
-	currentTime := time.Now()
-	user.Status = model.Deleted
-	user.UpdatedAt = currentTime
-	user.DeletedAt = &currentTime
-	err = u.userRepository.Store(*user)
 	if err != nil {
 		return err
 	}
@@ -44,4 +44,7 @@ func NewDatabaseMigrator(
 
 var builderFunctions = []MigrationBuilderFunc{
 	NewVersion1722266003,
+	NewVersion1792287043,
+	NewVersion1792312566,
+	NewVersion1792398127,
 }
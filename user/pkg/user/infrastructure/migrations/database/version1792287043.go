@@ -0,0 +1,45 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1792287043(client mysql.ClientContext) migrator.Migration {
+	return &version1792287043{
+		client: client,
+	}
+}
+
+type version1792287043 struct {
+	client mysql.ClientContext
+}
+
+func (v version1792287043) Version() int64 {
+	return 1792287043
+}
+
+func (v version1792287043) Description() string {
+	return "Create 'idempotency_keys' table"
+}
+
+func (v version1792287043) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS idempotency_keys
+		(
+		    scope           VARCHAR(64)  NOT NULL,
+		    idempotency_key VARCHAR(255) NOT NULL,
+		    fingerprint     VARCHAR(64)  NOT NULL,
+		    response        TEXT         NOT NULL,
+		    created_at      DATETIME     NOT NULL,
+		    PRIMARY KEY (scope, idempotency_key)
+		)
+		    ENGINE = InnoDB
+		    CHARACTER SET = utf8mb4
+		    COLLATE utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
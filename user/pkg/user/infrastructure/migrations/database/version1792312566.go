@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1792312566(client mysql.ClientContext) migrator.Migration {
+	return &version1792312566{
+		client: client,
+	}
+}
+
+type version1792312566 struct {
+	client mysql.ClientContext
+}
+
+func (v version1792312566) Version() int64 {
+	return 1792312566
+}
+
+func (v version1792312566) Description() string {
+	return "Add 'version' column to 'user' table"
+}
+
+func (v version1792312566) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `ALTER TABLE user ADD COLUMN version INT NOT NULL DEFAULT 1`)
+	return errors.WithStack(err)
+}
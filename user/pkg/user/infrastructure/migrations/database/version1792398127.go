@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1792398127(client mysql.ClientContext) migrator.Migration {
+	return &version1792398127{
+		client: client,
+	}
+}
+
+type version1792398127 struct {
+	client mysql.ClientContext
+}
+
+func (v version1792398127) Version() int64 {
+	return 1792398127
+}
+
+func (v version1792398127) Description() string {
+	return "Create 'user_address' table"
+}
+
+func (v version1792398127) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS user_address
+		(
+		    address_id  VARCHAR(64)  NOT NULL,
+		    user_id     VARCHAR(64)  NOT NULL,
+		    recipient   VARCHAR(255) NOT NULL,
+		    phone       VARCHAR(32)  NOT NULL,
+		    country     VARCHAR(255) NOT NULL,
+		    city        VARCHAR(255) NOT NULL,
+		    street      VARCHAR(255) NOT NULL,
+		    postal_code VARCHAR(32)  NOT NULL,
+		    created_at  DATETIME     NOT NULL,
+		    PRIMARY KEY (address_id),
+		    INDEX user_id_idx (user_id)
+		)
+		    ENGINE = InnoDB
+		    CHARACTER SET = utf8mb4
+		    COLLATE utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
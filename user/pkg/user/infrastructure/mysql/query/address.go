@@ -0,0 +1,79 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	appmodel "user/pkg/user/app/data"
+	"user/pkg/user/domain/model"
+)
+
+type addressRow struct {
+	ID         uuid.UUID `db:"address_id"`
+	UserID     uuid.UUID `db:"user_id"`
+	Recipient  string    `db:"recipient"`
+	Phone      string    `db:"phone"`
+	Country    string    `db:"country"`
+	City       string    `db:"city"`
+	Street     string    `db:"street"`
+	PostalCode string    `db:"postal_code"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+const addressColumns = `address_id, user_id, recipient, phone, country, city, street, postal_code, created_at`
+
+func (u *userQueryService) FindAddress(ctx context.Context, addressID uuid.UUID) (*appmodel.Address, error) {
+	var row addressRow
+	err := u.client.GetContext(
+		ctx,
+		&row,
+		`SELECT `+addressColumns+` FROM user_address WHERE address_id = ?`,
+		addressID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(model.ErrAddressNotFound)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	address := toAddress(row)
+	return &address, nil
+}
+
+func (u *userQueryService) ListAddresses(ctx context.Context, userID uuid.UUID) ([]appmodel.Address, error) {
+	var rows []addressRow
+	err := u.client.SelectContext(
+		ctx,
+		&rows,
+		`SELECT `+addressColumns+` FROM user_address WHERE user_id = ? ORDER BY address_id`,
+		userID,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	addresses := make([]appmodel.Address, len(rows))
+	for i, row := range rows {
+		addresses[i] = toAddress(row)
+	}
+	return addresses, nil
+}
+
+func toAddress(row addressRow) appmodel.Address {
+	return appmodel.Address{
+		ID:         row.ID,
+		UserID:     row.UserID,
+		Recipient:  row.Recipient,
+		Phone:      row.Phone,
+		Country:    row.Country,
+		City:       row.City,
+		Street:     row.Street,
+		PostalCode: row.PostalCode,
+		CreatedAt:  row.CreatedAt,
+	}
+}
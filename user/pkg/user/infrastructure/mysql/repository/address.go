@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"user/pkg/user/domain/model"
+)
+
+func NewAddressRepository(ctx context.Context, client mysql.ClientContext) model.AddressRepository {
+	return &addressRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type addressRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+func (a *addressRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (a *addressRepository) Store(address model.Address) error {
+	_, err := a.client.ExecContext(a.ctx,
+		`
+	INSERT INTO user_address (address_id, user_id, recipient, phone, country, city, street, postal_code, created_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON DUPLICATE KEY UPDATE
+		recipient=VALUES(recipient),
+		phone=VALUES(phone),
+		country=VALUES(country),
+		city=VALUES(city),
+		street=VALUES(street),
+		postal_code=VALUES(postal_code)
+	`,
+		address.ID,
+		address.UserID,
+		address.Recipient,
+		address.Phone,
+		address.Country,
+		address.City,
+		address.Street,
+		address.PostalCode,
+		address.CreatedAt,
+	)
+	return errors.WithStack(err)
+}
+
+func (a *addressRepository) Find(id uuid.UUID) (*model.Address, error) {
+	address := struct {
+		ID         uuid.UUID `db:"address_id"`
+		UserID     uuid.UUID `db:"user_id"`
+		Recipient  string    `db:"recipient"`
+		Phone      string    `db:"phone"`
+		Country    string    `db:"country"`
+		City       string    `db:"city"`
+		Street     string    `db:"street"`
+		PostalCode string    `db:"postal_code"`
+		CreatedAt  time.Time `db:"created_at"`
+	}{}
+
+	err := a.client.GetContext(
+		a.ctx,
+		&address,
+		`SELECT address_id, user_id, recipient, phone, country, city, street, postal_code, created_at FROM user_address WHERE address_id = ?`,
+		id,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(model.ErrAddressNotFound)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	return &model.Address{
+		ID:         address.ID,
+		UserID:     address.UserID,
+		Recipient:  address.Recipient,
+		Phone:      address.Phone,
+		Country:    address.Country,
+		City:       address.City,
+		Street:     address.Street,
+		PostalCode: address.PostalCode,
+		CreatedAt:  address.CreatedAt,
+	}, nil
+}
+
+func (a *addressRepository) Remove(id uuid.UUID) error {
+	_, err := a.client.ExecContext(a.ctx, `DELETE FROM user_address WHERE address_id = ?`, id)
+	return errors.WithStack(err)
+}
@@ -30,27 +30,46 @@ func (u *userRepository) NextID() (uuid.UUID, error) {
 }
 
 func (u *userRepository) Store(user model.User) error {
-	_, err := u.client.ExecContext(u.ctx,
+	if user.Version == 0 {
+		_, err := u.client.ExecContext(u.ctx,
+			`INSERT INTO user (user_id, status, login, email, telegram, created_at, updated_at, deleted_at, version) VALUES (?, ?, ?, ?, ?, ?, ?, ?, 1)`,
+			user.UserID,
+			user.Status,
+			user.Login,
+			toSQLNull(user.Email),
+			toSQLNull(user.Telegram),
+			user.CreatedAt,
+			user.UpdatedAt,
+			toSQLNull(user.DeletedAt),
+		)
+		return errors.WithStack(err)
+	}
+
+	res, err := u.client.ExecContext(u.ctx,
 		`
-	INSERT INTO user (user_id, status, login, email, telegram, created_at, updated_at, deleted_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-	ON DUPLICATE KEY UPDATE
-		status=VALUES(status),
-	    login=VALUES(login),
-	    email=VALUES(email),
-	    telegram=VALUES(telegram),
-	    updated_at=VALUES(updated_at),
-	    deleted_at=VALUES(deleted_at)
+	UPDATE user SET status = ?, login = ?, email = ?, telegram = ?, updated_at = ?, deleted_at = ?, version = version + 1
+	WHERE user_id = ? AND version = ?
 	`,
-		user.UserID,
 		user.Status,
 		user.Login,
 		toSQLNull(user.Email),
 		toSQLNull(user.Telegram),
-		user.CreatedAt,
 		user.UpdatedAt,
 		toSQLNull(user.DeletedAt),
+		user.UserID,
+		user.Version,
 	)
-	return errors.WithStack(err)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if rows == 0 {
+		return errors.WithStack(model.ErrUserVersionConflict)
+	}
+	return nil
 }
 
 func (u *userRepository) Find(spec model.FindSpec) (*model.User, error) {
@@ -63,13 +82,14 @@ func (u *userRepository) Find(spec model.FindSpec) (*model.User, error) {
 		CreatedAt time.Time           `db:"created_at"`
 		UpdatedAt time.Time           `db:"updated_at"`
 		DeletedAt sql.Null[time.Time] `db:"deleted_at"`
+		Version   int                 `db:"version"`
 	}{}
 	query, args := u.buildSpecArgs(spec)
 
 	err := u.client.GetContext(
 		u.ctx,
 		&user,
-		`SELECT user_id, status, login, email, telegram, created_at, updated_at, deleted_at FROM user WHERE `+query,
+		`SELECT user_id, status, login, email, telegram, created_at, updated_at, deleted_at, version FROM user WHERE `+query,
 		args...,
 	)
 	if err != nil {
@@ -88,6 +108,7 @@ func (u *userRepository) Find(spec model.FindSpec) (*model.User, error) {
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
 		DeletedAt: fromSQLNull(user.DeletedAt),
+		Version:   user.Version,
 	}, nil
 }
 
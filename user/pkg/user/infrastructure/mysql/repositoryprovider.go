@@ -5,6 +5,7 @@ import (
 
 	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
 
+	"user/pkg/common/idempotency"
 	"user/pkg/user/app/service"
 	"user/pkg/user/domain/model"
 	"user/pkg/user/infrastructure/mysql/repository"
@@ -21,3 +22,11 @@ type repositoryProvider struct {
 func (r *repositoryProvider) UserRepository(ctx context.Context) model.UserRepository {
 	return repository.NewUserRepository(ctx, r.client)
 }
+
+func (r *repositoryProvider) AddressRepository(ctx context.Context) model.AddressRepository {
+	return repository.NewAddressRepository(ctx, r.client)
+}
+
+func (r *repositoryProvider) IdempotencyRepository(ctx context.Context) idempotency.Repository {
+	return repository.NewIdempotencyRepository(ctx, r.client)
+}
@@ -0,0 +1,113 @@
+package transport
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"user/api/server/userpublicapi"
+	appdata "user/pkg/user/app/data"
+	"user/pkg/user/domain/model"
+	domainservice "user/pkg/user/domain/service"
+)
+
+func (u userInternalAPI) AddAddress(ctx context.Context, request *userpublicapi.AddAddressRequest) (*userpublicapi.AddAddressResponse, error) {
+	userID, err := uuid.Parse(request.UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.UserID)
+	}
+
+	addressID, err := u.addressService.AddAddress(ctx, userID, appdata.Address{
+		Recipient:  request.Recipient,
+		Phone:      request.Phone,
+		Country:    request.Country,
+		City:       request.City,
+		Street:     request.Street,
+		PostalCode: request.PostalCode,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrUserNotFound):
+			return nil, status.Errorf(codes.NotFound, "user %q not found", request.UserID)
+		case errors.Is(err, domainservice.ErrInvalidAddress):
+			return nil, status.Error(codes.InvalidArgument, errors.Cause(err).Error())
+		}
+		return nil, err
+	}
+	return &userpublicapi.AddAddressResponse{
+		AddressID: addressID.String(),
+	}, nil
+}
+
+func (u userInternalAPI) RemoveAddress(ctx context.Context, request *userpublicapi.RemoveAddressRequest) (*emptypb.Empty, error) {
+	userID, err := uuid.Parse(request.UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.UserID)
+	}
+	addressID, err := uuid.Parse(request.AddressID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.AddressID)
+	}
+
+	err = u.addressService.RemoveAddress(ctx, userID, addressID)
+	if err != nil {
+		if errors.Is(err, model.ErrAddressNotFound) {
+			return nil, status.Errorf(codes.NotFound, "address %q not found", request.AddressID)
+		}
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (u userInternalAPI) FindAddress(ctx context.Context, request *userpublicapi.FindAddressRequest) (*userpublicapi.Address, error) {
+	addressID, err := uuid.Parse(request.AddressID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.AddressID)
+	}
+
+	address, err := u.userQueryService.FindAddress(ctx, addressID)
+	if err != nil {
+		if errors.Is(err, model.ErrAddressNotFound) {
+			return nil, status.Errorf(codes.NotFound, "address %q not found", request.AddressID)
+		}
+		return nil, err
+	}
+	return toAddressResponse(*address), nil
+}
+
+func (u userInternalAPI) ListAddresses(ctx context.Context, request *userpublicapi.ListAddressesRequest) (*userpublicapi.ListAddressesResponse, error) {
+	userID, err := uuid.Parse(request.UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.UserID)
+	}
+
+	addresses, err := u.userQueryService.ListAddresses(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &userpublicapi.ListAddressesResponse{
+		Addresses: make([]*userpublicapi.Address, len(addresses)),
+	}
+	for i, address := range addresses {
+		response.Addresses[i] = toAddressResponse(address)
+	}
+	return response, nil
+}
+
+func toAddressResponse(address appdata.Address) *userpublicapi.Address {
+	return &userpublicapi.Address{
+		AddressID:  address.ID.String(),
+		UserID:     address.UserID.String(),
+		Recipient:  address.Recipient,
+		Phone:      address.Phone,
+		Country:    address.Country,
+		City:       address.City,
+		Street:     address.Street,
+		PostalCode: address.PostalCode,
+	}
+}
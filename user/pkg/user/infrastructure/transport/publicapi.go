@@ -4,11 +4,14 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"user/api/server/userpublicapi"
+	"user/pkg/common/idempotency"
 	appdata "user/pkg/user/app/data"
 	"user/pkg/user/app/query"
 	"user/pkg/user/app/service"
@@ -17,16 +20,19 @@ import (
 func NewUserInternalAPI(
 	userQueryService query.UserQueryService,
 	userService service.UserService,
+	addressService service.AddressService,
 ) userpublicapi.UserPublicAPIServer {
 	return &userInternalAPI{
 		userQueryService: userQueryService,
 		userService:      userService,
+		addressService:   addressService,
 	}
 }
 
 type userInternalAPI struct {
 	userQueryService query.UserQueryService
 	userService      service.UserService
+	addressService   service.AddressService
 
 	userpublicapi.UnimplementedUserPublicAPIServer
 }
@@ -37,8 +43,11 @@ func (u userInternalAPI) CreateUser(ctx context.Context, request *userpublicapi.
 		Email:    request.Email,
 		Telegram: request.Telegram,
 		Status:   0, // по умолчанию Blocked
-	})
+	}, idempotencyKey(ctx, request.IdempotencyKey))
 	if err != nil {
+		if errors.Is(err, idempotency.ErrKeyConflict) {
+			return nil, status.Error(codes.AlreadyExists, errors.Cause(err).Error())
+		}
 		return nil, err
 	}
 
@@ -116,3 +125,15 @@ func (u userInternalAPI) DeleteUser(ctx context.Context, request *userpublicapi.
 	}
 	return &emptypb.Empty{}, nil
 }
+
+const idempotencyKeyMetadata = "idempotency-key"
+
+func idempotencyKey(ctx context.Context, key *string) string {
+	if key != nil && *key != "" {
+		return *key
+	}
+	if values := metadata.ValueFromIncomingContext(ctx, idempotencyKeyMetadata); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}